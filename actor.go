@@ -0,0 +1,96 @@
+package ass
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ActorRule styles all the lines spoken by an actor
+type ActorRule struct {
+	Actor string `json:"actor"`
+	// Style replaces the style of the lines, if not empty
+	Style string `json:"style"`
+	// Color is an ABGR color the text is painted with, if not empty
+	Color string `json:"color"`
+}
+
+// DefaultActorPalette is a set of distinct, readable ABGR colors
+var DefaultActorPalette = []string{
+	"0000FFFF", // yellow
+	"00FFFF00", // cyan
+	"0000FF00", // green
+	"00FF00FF", // magenta
+	"000080FF", // orange
+	"00FFFFFF", // white
+}
+
+// ActorColorRules gives each actor of the subtitle a distinct color from the
+// palette, in order of first appearance. Colors are reused when the actors
+// outnumber the palette. DefaultActorPalette is used if palette is empty.
+func (as *Subtitle) ActorColorRules(palette []string) []ActorRule {
+	if len(palette) == 0 {
+		palette = DefaultActorPalette
+	}
+	var rules []ActorRule
+	seen := make(map[string]bool)
+	for _, evt := range as.Events {
+		if evt == nil || evt.Name == "" || seen[evt.Name] {
+			continue
+		}
+		seen[evt.Name] = true
+		rules = append(rules, ActorRule{Actor: evt.Name, Color: palette[len(rules)%len(palette)]})
+	}
+	return rules
+}
+
+// ApplyActorRules applies the rules to the events in a single pass and
+// returns the number of changed events. Only the first matching rule of an
+// actor is applied.
+func (as *Subtitle) ApplyActorRules(rules ...ActorRule) (int, error) {
+	byActor := make(map[string]ActorRule, len(rules))
+	for _, rule := range rules {
+		if rule.Color != "" && !isValidABGR(rule.Color) {
+			return 0, fmt.Errorf("Invalid color for actor %s: %s", rule.Actor, rule.Color)
+		}
+		if _, ok := byActor[rule.Actor]; !ok {
+			byActor[rule.Actor] = rule
+		}
+	}
+
+	changed := 0
+	for _, evt := range as.Events {
+		if evt == nil || evt.Name == "" {
+			continue
+		}
+		rule, ok := byActor[evt.Name]
+		if !ok {
+			continue
+		}
+		if rule.Style != "" {
+			evt.Style = rule.Style
+		}
+		if rule.Color != "" {
+			evt.Text = prependTags(evt.Text, colorTag(rule.Color))
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+// colorTag returns the override tags painting the text with an ABGR color
+func colorTag(color string) string {
+	tag := `\c&H` + color[2:] + `&`
+	if alpha := color[:2]; alpha != "00" {
+		tag += `\1a&H` + alpha + `&`
+	}
+	return tag
+}
+
+// prependTags adds tags at the start of the text, merged into the leading
+// override block if there is one
+func prependTags(text, tags string) string {
+	if strings.HasPrefix(text, "{") && strings.IndexByte(text, '}') > 0 {
+		return "{" + tags + text[1:]
+	}
+	return "{" + tags + "}" + text
+}
@@ -0,0 +1,45 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestActorRules(t *testing.T) {
+	sub := &Subtitle{
+		Events: []*Event{
+			{Name: "Naru", Text: "Hi"},
+			{Name: "Kei", Text: `{\i1}Hey`},
+			{Text: "(music)"},
+			{Name: "Naru", Text: "Bye"},
+		},
+	}
+
+	rules := sub.ActorColorRules([]string{"000000FF", "80FF0000"})
+	if len(rules) != 2 || rules[0].Actor != "Naru" || rules[1].Actor != "Kei" {
+		t.Fatalf("Unexpected rules: %+v", rules)
+	}
+	rules = append(rules, ActorRule{Actor: "Kei", Style: "Ignored"})
+	rules[0].Style = "Main"
+
+	n, err := sub.ApplyActorRules(rules...)
+	if err != nil || n != 3 {
+		t.Fatalf("Expect 3 changed events, got: %d %v", n, err)
+	}
+
+	expects := []Event{
+		{Name: "Naru", Style: "Main", Text: `{\c&H0000FF&}Hi`},
+		{Name: "Kei", Text: `{\c&HFF0000&\1a&H80&\i1}Hey`},
+		{Text: "(music)"},
+		{Name: "Naru", Style: "Main", Text: `{\c&H0000FF&}Bye`},
+	}
+	for i, expect := range expects {
+		if !reflect.DeepEqual(*sub.Events[i], expect) {
+			t.Errorf("Expect %+v, got: %+v", expect, *sub.Events[i])
+		}
+	}
+
+	if _, err := sub.ApplyActorRules(ActorRule{Actor: "Naru", Color: "red"}); err == nil {
+		t.Errorf("Expect invalid color error, but passed")
+	}
+}
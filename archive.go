@@ -0,0 +1,119 @@
+package ass
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+// maxArchiveSize limits the size of the zip archives read in memory
+const maxArchiveSize = 64 << 20
+
+// maxArchiveFileSize limits the uncompressed size of a file of an archive
+const maxArchiveFileSize = 64 << 20
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte("PK\x03\x04")
+)
+
+// decompress returns the content of a gzip stream, or of the first file of
+// a zip archive with one of the extensions. Other inputs are returned as is.
+func decompress(r io.Reader, exts ...string) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(4)
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(magic, zipMagic):
+		zr, err := readZip(br)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if hasExt(f.Name, exts) {
+				return openZipFile(f)
+			}
+		}
+		return nil, fmt.Errorf("No %s file in the archive", strings.Join(exts, " or "))
+	}
+	return br, nil
+}
+
+func readZip(r io.Reader) (*zip.Reader, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxArchiveSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxArchiveSize {
+		return nil, fmt.Errorf("Archive too large, more than %d bytes", maxArchiveSize)
+	}
+	return zip.NewReader(bytes.NewReader(data), int64(len(data)))
+}
+
+// openZipFile reads a file of an archive, closing it at once. The size in
+// the header may lie, so the reading is limited as well.
+func openZipFile(f *zip.File) (io.Reader, error) {
+	if f.UncompressedSize64 > maxArchiveFileSize {
+		return nil, fmt.Errorf("File too large, more than %d bytes", maxArchiveFileSize)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(rc, maxArchiveFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxArchiveFileSize {
+		return nil, fmt.Errorf("File too large, more than %d bytes", maxArchiveFileSize)
+	}
+	return bytes.NewReader(data), nil
+}
+
+func hasExt(name string, exts []string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, e := range exts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseZip reads all the ass, ssa and srt files of a zip archive, as
+// distributed by subtitle sites, by their path in the archive
+func ParseZip(r io.Reader) (map[string]*Subtitle, error) {
+	zr, err := readZip(r)
+	if err != nil {
+		return nil, err
+	}
+	subs := make(map[string]*Subtitle)
+	for _, f := range zr.File {
+		if !hasExt(f.Name, []string{".ass", ".ssa", ".srt"}) {
+			continue
+		}
+		content, err := openZipFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", f.Name, err)
+		}
+		var sub *Subtitle
+		if hasExt(f.Name, []string{".srt"}) {
+			sub, err = ParseSRT(content)
+		} else {
+			sub, err = Parse(content)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", f.Name, err)
+		}
+		subs[f.Name] = sub
+	}
+	return subs, nil
+}
@@ -0,0 +1,76 @@
+package ass
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+)
+
+const archiveScript = "[Events]\nDialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,packed\n"
+
+func TestParseCompressed(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(archiveScript))
+	zw.Close()
+
+	var zipped bytes.Buffer
+	w := zip.NewWriter(&zipped)
+	for name, content := range map[string]string{"readme.txt": "hi", "ep01.ass": archiveScript} {
+		f, _ := w.Create(name)
+		f.Write([]byte(content))
+	}
+	w.Close()
+
+	for name, input := range map[string][]byte{"gzip": gz.Bytes(), "zip": zipped.Bytes()} {
+		sub, err := Parse(bytes.NewReader(input))
+		if err != nil {
+			t.Errorf("%s: expect no error, got: %v", name, err)
+			continue
+		}
+		if len(sub.Events) != 1 || sub.Events[0].Text != "packed" {
+			t.Errorf("%s: expect the packed event, got: %v", name, sub.Events)
+		}
+	}
+
+	if _, err := ParseSRT(bytes.NewReader(zipped.Bytes())); err == nil || !strings.Contains(err.Error(), "No .srt file") {
+		t.Errorf("Expect error for an archive without srt file, got: %v", err)
+	}
+}
+
+func TestParseZip(t *testing.T) {
+	var zipped bytes.Buffer
+	w := zip.NewWriter(&zipped)
+	files := map[string]string{
+		"pack/ep01.ass":  archiveScript,
+		"pack/ep02.srt":  "1\n00:00:01,000 --> 00:00:02,000\nsrt\n",
+		"pack/cover.jpg": "jpeg",
+	}
+	for name, content := range files {
+		f, _ := w.Create(name)
+		f.Write([]byte(content))
+	}
+	w.Close()
+
+	subs, err := ParseZip(bytes.NewReader(zipped.Bytes()))
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	if len(subs) != 2 || subs["pack/ep01.ass"].Events[0].Text != "packed" || subs["pack/ep02.srt"].Events[0].Text != "srt" {
+		t.Errorf("Expect the ass and srt files, got: %v", subs)
+	}
+}
+
+func TestParseZipBomb(t *testing.T) {
+	var zipped bytes.Buffer
+	w := zip.NewWriter(&zipped)
+	f, _ := w.Create("bomb.ass")
+	f.Write(make([]byte, maxArchiveFileSize+1))
+	w.Close()
+
+	if _, err := ParseZip(bytes.NewReader(zipped.Bytes())); err == nil || !strings.Contains(err.Error(), "File too large") {
+		t.Errorf("Expect error for a file too large, got: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package ass
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Keys of the confidences in the extra data of the events, see
+// ImportSegments
+const (
+	confidenceKey     = "confidence"
+	wordConfidenceKey = "wordConfidence"
+)
+
+// lowConfidenceTags are the default tags of Selection.Highlight, red text
+const lowConfidenceTags = `\c&H0000FF&`
+
+// Segment is a line of speech recognition output
+type Segment struct {
+	Text  string    `json:"text"`
+	Start Timestamp `json:"start"`
+	End   Timestamp `json:"end"`
+	// Confidence is how sure the recognizer is of the segment, from 0 to 1,
+	// 0 if unknown
+	Confidence float64 `json:"confidence,omitempty"`
+	Words      []Word  `json:"words,omitempty"`
+}
+
+// ImportSegments makes an event per segment, the other fields of the
+// events copied from base. The text of a segment is the words joined if
+// it has none. The confidence of a segment, or else the lowest of its
+// words, is kept in the extra data of the event under "confidence" and
+// those of the words under "wordConfidence", separated by spaces.
+func ImportSegments(base Event, segments []Segment) ([]*Event, error) {
+	events := make([]*Event, 0, len(segments))
+	for i, seg := range segments {
+		if seg.End < seg.Start {
+			return nil, fmt.Errorf("Segment %d ends before it starts", i)
+		}
+		evt := base.clone()
+		evt.Start = seg.Start.String()
+		evt.End = seg.End.String()
+
+		text := strings.TrimSpace(seg.Text)
+		words := make([]string, len(seg.Words))
+		confidences := make([]string, len(seg.Words))
+		lowest, scored := 0.0, false
+		for j, w := range seg.Words {
+			words[j] = strings.TrimSpace(w.Text)
+			confidences[j] = formatNumber(w.Confidence)
+			if w.Confidence > 0 && (!scored || w.Confidence < lowest) {
+				lowest, scored = w.Confidence, true
+			}
+		}
+		if text == "" {
+			text = strings.Join(words, " ")
+		}
+		evt.Text = EscapeText(text)
+
+		if seg.Confidence > 0 {
+			evt.SetExtra(confidenceKey, formatNumber(seg.Confidence))
+		} else if scored {
+			evt.SetExtra(confidenceKey, formatNumber(lowest))
+		}
+		if scored {
+			evt.SetExtra(wordConfidenceKey, strings.Join(confidences, " "))
+		}
+		events = append(events, &evt)
+	}
+	return events, nil
+}
+
+// Confidence returns the confidence of the event, see ImportSegments, and
+// false if it has none
+func (evt *Event) Confidence() (float64, bool) {
+	v, err := strconv.ParseFloat(evt.Extra[confidenceKey], 64)
+	return v, err == nil
+}
+
+// WordConfidences returns the confidences of the words of the event, see
+// ImportSegments, 0 for the words without one
+func (evt *Event) WordConfidences() []float64 {
+	fields := strings.Fields(evt.Extra[wordConfidenceKey])
+	if len(fields) == 0 {
+		return nil
+	}
+	confidences := make([]float64, len(fields))
+	for i, f := range fields {
+		confidences[i], _ = strconv.ParseFloat(f, 64)
+	}
+	return confidences
+}
+
+// BelowConfidence keeps the events with a confidence lower than threshold,
+// those without confidence are dropped
+func (sel *Selection) BelowConfidence(threshold float64) *Selection {
+	return sel.Filter(func(evt *Event) bool {
+		c, ok := evt.Confidence()
+		return ok && c < threshold
+	})
+}
+
+// Highlight adds override tags at the start of the selected events, red
+// text if tags is empty, e.g. to point out the lines to check
+func (sel *Selection) Highlight(tags string) {
+	if tags == "" {
+		tags = lowConfidenceTags
+	}
+	for _, evt := range sel.events {
+		evt.Text = prependTags(evt.Text, tags)
+	}
+}
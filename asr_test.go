@@ -0,0 +1,75 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestImportSegments(t *testing.T) {
+	sec := func(s float64) Timestamp { return Timestamp(s * float64(time.Second)) }
+	base := Event{Style: "ASR", Extra: map[string]string{"source": "asr"}}
+	segments := []Segment{
+		{Text: " Hello {there} ", Start: sec(1), End: sec(2), Confidence: 0.91},
+		{Start: sec(2), End: sec(3.5), Words: []Word{
+			{Text: "good", Start: sec(2), End: sec(2.5), Confidence: 0.8},
+			{Text: "bye", Start: sec(2.5), End: sec(3.5), Confidence: 0.4255},
+			{Text: "now", Start: sec(3), End: sec(3.5)},
+		}},
+		{Text: "unscored", Start: sec(4), End: sec(5)},
+	}
+	events, err := ImportSegments(base, segments)
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	expect := []Event{
+		{Style: "ASR", Start: "0:00:01.00", End: "0:00:02.00", Text: `Hello \{there\}`,
+			Extra: map[string]string{"source": "asr", "confidence": "0.91"}},
+		{Style: "ASR", Start: "0:00:02.00", End: "0:00:03.50", Text: "good bye now",
+			Extra: map[string]string{"source": "asr", "confidence": "0.426", "wordConfidence": "0.8 0.426 0"}},
+		{Style: "ASR", Start: "0:00:04.00", End: "0:00:05.00", Text: "unscored",
+			Extra: map[string]string{"source": "asr"}},
+	}
+	for i, evt := range events {
+		if !reflect.DeepEqual(*evt, expect[i]) {
+			t.Errorf("Event %d: expect %+v, got: %+v", i, expect[i], *evt)
+		}
+	}
+	if len(base.Extra) != 1 {
+		t.Errorf("Expect the extra data of base unchanged, got: %v", base.Extra)
+	}
+
+	if c, ok := events[1].Confidence(); !ok || c != 0.426 {
+		t.Errorf("Expect a confidence of 0.426, got: %v, %v", c, ok)
+	}
+	if _, ok := events[2].Confidence(); ok {
+		t.Errorf("Expect no confidence")
+	}
+	if got := events[1].WordConfidences(); !reflect.DeepEqual(got, []float64{0.8, 0.426, 0}) {
+		t.Errorf("Expect the word confidences, got: %v", got)
+	}
+
+	if _, err := ImportSegments(base, []Segment{{Start: sec(2), End: sec(1)}}); err == nil {
+		t.Errorf("Expect an error for a segment ending before it starts")
+	}
+}
+
+func TestHighlightLowConfidence(t *testing.T) {
+	sub := &Subtitle{Events: []*Event{
+		{Text: "sure", Extra: map[string]string{"confidence": "0.95"}},
+		{Text: `{\i1}unsure`, Extra: map[string]string{"confidence": "0.3"}},
+		{Text: "unknown"},
+		{Text: "unsure", Extra: map[string]string{"confidence": "0.59"}},
+	}}
+	sel := sub.Select().BelowConfidence(0.6)
+	if sel.Len() != 2 {
+		t.Fatalf("Expect 2 events below 0.6, got: %d", sel.Len())
+	}
+	sel.Highlight("")
+	expect := []string{"sure", `{\c&H0000FF&\i1}unsure`, "unknown", `{\c&H0000FF&}unsure`}
+	for i, evt := range sub.Events {
+		if evt.Text != expect[i] {
+			t.Errorf("Event %d: expect %s, got: %s", i, expect[i], evt.Text)
+		}
+	}
+}
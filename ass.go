@@ -2,39 +2,80 @@ package ass
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
-	"regexp"
+	"sync"
 	"text/template"
+
+	"golang.org/x/text/language"
 )
 
 // Event is a single subtitle item
 // Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
 type Event struct {
 	Layer   int    `json:"layer"`
-	Start   string `json:"start"` // 0:00:00:00 h:mm:ss:msms
-	End     string `json:"end"`   // 0:00:00:00 h:mm:ss:msms
+	Start   string `json:"start"` // 0:00:00.00 h:mm:ss.cc
+	End     string `json:"end"`   // 0:00:00.00 h:mm:ss.cc
 	Style   string `json:"style"`
 	Name    string `json:"name"` // The speaker name, just a placeholder
 	MarginL uint   `json:"marginLeft"`
 	MarginR uint   `json:"marginRight"`
 	MarginV uint   `json:"marginV"`
-	Effect  string `json:"effect"`
+	Effect  Effect `json:"effect"`
 	Text    string `json:"text"`
+	// Comment events are written as Comment lines, not displayed
+	Comment bool `json:"comment,omitempty"`
+	// Forced events are shown even to the viewers not reading subtitles,
+	// e.g. foreign dialogue. They are written with a {forced} comment at the
+	// start of the text.
+	Forced bool `json:"forced,omitempty"`
+	// Extra is data attached to the event by the tools, e.g. an ID or a
+	// review status. It is written URL encoded in an {extra:...} comment at
+	// the start of the text.
+	Extra map[string]string `json:"extra,omitempty"`
 }
 
-var timeReg = regexp.MustCompile(`\d:[0-6]\d:[0-6]\d:\d\d`)
+// forcedMarker starts the text of the forced events in the file
+const forcedMarker = "{forced}"
 
 func (evt Event) validate() error {
-	if !timeReg.MatchString(evt.Start) {
-		return fmt.Errorf("Invalid start time: %s", evt.Start)
-	}
-	if !timeReg.MatchString(evt.End) {
-		return fmt.Errorf("Invalid end time: %s", evt.End)
+	if errs := evt.problems(); len(errs) > 0 {
+		return errs[0].Err
 	}
 	return nil
 }
 
+// problems returns all the problems of the event
+func (evt Event) problems() []*ValidationError {
+	var errs []*ValidationError
+	add := func(field string, err error) {
+		errs = append(errs, &ValidationError{Field: field, Err: err})
+	}
+	start, startErr := ParseTimestamp(evt.Start)
+	if startErr != nil {
+		add("Start", fmt.Errorf("Invalid start time: %s", evt.Start))
+	}
+	end, endErr := ParseTimestamp(evt.End)
+	if endErr != nil {
+		add("End", fmt.Errorf("Invalid end time: %s", evt.End))
+	}
+	// comments, e.g. karaoke templates, are often zero-length
+	if startErr == nil && endErr == nil && end <= start && !evt.Comment {
+		add("End", fmt.Errorf("End time %s is not after start time %s", evt.End, evt.Start))
+	}
+	if err := checkField("style", evt.Style); err != nil {
+		add("Style", err)
+	}
+	if err := checkField("name", evt.Name); err != nil {
+		add("Name", err)
+	}
+	if err := checkField("effect", string(evt.Effect)); err != nil {
+		add("Effect", err)
+	}
+	return errs
+}
+
 // Style is a style for ass subtitle
 type Style struct {
 	Name         string `json:"name"`
@@ -44,12 +85,115 @@ type Style struct {
 	SecondColor  string `json:"secondColor"`
 	OutlineColor string `json:"outlineColor"`
 	BackColor    string `json:"backColor"`
-	Bold         int    `json:"bold"`
-	Italic       int    `json:"italic"`
-	Underline    int    `json:"underline"`
-	StrikeOut    int    `json:"strikeOut"`
-	ScaleX       int    `json:"scaleX"`
-	ScaleY       int    `json:"scaleY"`
+	// Bold, Italic, Underline and StrikeOut are -1 for on and 0 for off,
+	// but Bold is written as 1, bold, if 0
+	Bold      int `json:"bold"`
+	Italic    int `json:"italic"`
+	Underline int `json:"underline"`
+	StrikeOut int `json:"strikeOut"`
+	// ScaleX and ScaleY are in percent, 100 if 0
+	ScaleX int `json:"scaleX"`
+	ScaleY int `json:"scaleY"`
+	// Spacing is the extra space between the letters, in pixels
+	Spacing float64 `json:"spacing"`
+	// Angle is the rotation of the text, in degrees
+	Angle float64 `json:"angle"`
+	// BorderStyle is 1 for an outline and a drop shadow, 3 for an opaque
+	// box, 1 if 0
+	BorderStyle int `json:"borderStyle"`
+	// Outline is the width of the border, 2 if 0
+	Outline float64 `json:"outline"`
+	Shadow  float64 `json:"shadow"`
+	// Alignment is the numpad position of the text, 2 (bottom center) if 0
+	Alignment int `json:"alignment"`
+	// MarginL and MarginR are 20 if 0, MarginV 2 if 0
+	MarginL uint `json:"marginLeft"`
+	MarginR uint `json:"marginRight"`
+	MarginV uint `json:"marginV"`
+	// Encoding is the charset of the font, 1 for the default one
+	Encoding int `json:"encoding"`
+}
+
+// bold returns the bold flag written, 0 means unset
+func bold(v int) int {
+	if v == 0 {
+		return 1
+	}
+	return v
+}
+
+// outline returns the effective outline width, 0 means unset
+func outline(v float64) float64 {
+	if v == 0 {
+		return defStyleOutline
+	}
+	return v
+}
+
+// marginH and marginV return the effective horizontal and vertical
+// margins, 0 means unset
+func marginH(v uint) uint {
+	if v == 0 {
+		return defStyleMarginH
+	}
+	return v
+}
+
+func marginV(v uint) uint {
+	if v == 0 {
+		return defStyleMarginV
+	}
+	return v
+}
+
+// margins returns the effective margins of the style, the default ones for
+// a nil style
+func (style *Style) margins() (l, r, v uint) {
+	if style == nil {
+		return defStyleMarginH, defStyleMarginH, defStyleMarginV
+	}
+	return marginH(style.MarginL), marginH(style.MarginR), marginV(style.MarginV)
+}
+
+// align returns the effective numpad alignment of the style, bottom center
+// for a nil style
+func (style *Style) align() int {
+	if style == nil {
+		return 2
+	}
+	return alignment(style.Alignment)
+}
+
+// margins returns the effective margins of the event, those of its style
+// where 0
+func (evt *Event) margins(style *Style) (l, r, v uint) {
+	l, r, v = style.margins()
+	if evt.MarginL != 0 {
+		l = evt.MarginL
+	}
+	if evt.MarginR != 0 {
+		r = evt.MarginR
+	}
+	if evt.MarginV != 0 {
+		v = evt.MarginV
+	}
+	return l, r, v
+}
+
+// borderStyle returns the effective border style, 0 means unset
+func borderStyle(v int) int {
+	if v == 0 {
+		return 1
+	}
+	return v
+}
+
+// alignment returns the effective numpad alignment, 0 means unset
+func alignment(v int) int {
+	if v == 0 {
+		return 2
+	}
+	return v
 }
 
 // Check color is ABGR or not
@@ -67,43 +211,90 @@ func isValidABGR(color string) bool {
 }
 
 func (style Style) validate() error {
+	if errs := style.problems(); len(errs) > 0 {
+		return errs[0].Err
+	}
+	return nil
+}
+
+// problems returns all the problems of the style
+func (style Style) problems() []*ValidationError {
+	var errs []*ValidationError
+	add := func(field string, err error) {
+		errs = append(errs, &ValidationError{Field: field, Err: err})
+	}
+	if err := checkField("style name", style.Name); err != nil {
+		add("Name", err)
+	}
+	if err := checkField("font name", style.FontName); err != nil {
+		add("FontName", err)
+	}
 	if style.PrimaryColor != "" && !isValidABGR(style.PrimaryColor) {
-		return fmt.Errorf("Invalid primary color: %s", style.PrimaryColor)
+		add("PrimaryColor", fmt.Errorf("Invalid primary color: %s", style.PrimaryColor))
 	}
 	if style.SecondColor != "" && !isValidABGR(style.SecondColor) {
-		return fmt.Errorf("Invalid secondary color: %s", style.SecondColor)
+		add("SecondColor", fmt.Errorf("Invalid secondary color: %s", style.SecondColor))
 	}
 	if style.OutlineColor != "" && !isValidABGR(style.OutlineColor) {
-		return fmt.Errorf("Invalid outline color: %s", style.OutlineColor)
+		add("OutlineColor", fmt.Errorf("Invalid outline color: %s", style.OutlineColor))
 	}
 	if style.BackColor != "" && !isValidABGR(style.BackColor) {
-		return fmt.Errorf("Invalid back color: %s", style.BackColor)
+		add("BackColor", fmt.Errorf("Invalid back color: %s", style.BackColor))
 	}
 	if style.Bold != 0 && style.Bold != -1 {
-		return fmt.Errorf("Invalid style bold: %d", style.Bold)
+		add("Bold", fmt.Errorf("Invalid style bold: %d", style.Bold))
 	}
 	if style.Italic != 0 && style.Italic != -1 {
-		return fmt.Errorf("Invalid style italic: %d", style.Italic)
+		add("Italic", fmt.Errorf("Invalid style italic: %d", style.Italic))
 	}
 	if style.Underline != 0 && style.Underline != -1 {
-		return fmt.Errorf("Invalid style underline: %d", style.Underline)
+		add("Underline", fmt.Errorf("Invalid style underline: %d", style.Underline))
 	}
 	if style.StrikeOut != 0 && style.StrikeOut != -1 {
-		return fmt.Errorf("Invalid style StrikeOut: %d", style.StrikeOut)
+		add("StrikeOut", fmt.Errorf("Invalid style StrikeOut: %d", style.StrikeOut))
 	}
-	return nil
+	if style.BorderStyle != 0 && style.BorderStyle != 1 && style.BorderStyle != 3 {
+		add("BorderStyle", fmt.Errorf("Invalid style border style: %d", style.BorderStyle))
+	}
+	if style.Alignment < 0 || style.Alignment > 9 {
+		add("Alignment", fmt.Errorf("Invalid style alignment: %d", style.Alignment))
+	}
+	return errs
 }
 
 // Subtitle the ass subtitle
 type Subtitle struct {
-	Title        string   `json:"title"`
-	OriginScript string   `json:"originScript"`
-	PlayerWidth  uint     `json:"playResX"`
-	PlayerHeight uint     `json:"playResY"`
-	PlayDepth    uint     `json:"playDepth"`
-	Timer        float32  `json:"timer"`
-	Styles       []*Style `json:"styles"`
-	Events       []*Event `json:"events"`
+	Title        string    `json:"title"`
+	OriginScript string    `json:"originScript"`
+	PlayerWidth  uint      `json:"playResX"`
+	PlayerHeight uint      `json:"playResY"`
+	PlayDepth    uint      `json:"playDepth"`
+	Timer        float32   `json:"timer"`
+	WrapStyle    WrapStyle `json:"wrapStyle"`
+	// ScaledBorderAndShadow scales the borders and shadows with the script
+	// resolution, they are in video pixels otherwise
+	ScaledBorderAndShadow bool        `json:"scaledBorderAndShadow"`
+	YCbCrMatrix           YCbCrMatrix `json:"ycbcrMatrix,omitempty"`
+	// Collisions is Normal when empty
+	Collisions Collisions `json:"collisions,omitempty"`
+	// LayoutResX and LayoutResY are the resolution of the video the script
+	// was laid out against, libass uses them for the aspect ratio
+	LayoutResX uint `json:"layoutResX,omitempty"`
+	LayoutResY uint `json:"layoutResY,omitempty"`
+	// Kerning turns on the font kerning
+	Kerning bool `json:"kerning,omitempty"`
+	// Language is the BCP 47 tag of the dialogue language, e.g. en or pt-BR
+	Language string `json:"language,omitempty"`
+
+	Styles []*Style `json:"styles"`
+	Events []*Event `json:"events"`
+	// RawSections are the sections not understood, kept as read
+	RawSections []*RawSection `json:"rawSections,omitempty"`
+
+	index   *EventIndex
+	lines   *sourceLines
+	charset string // the charset of the input, if parsed
+	workers int    // the workers of the rules run by a Validator, GOMAXPROCS if 0
 }
 
 // some default values
@@ -111,37 +302,97 @@ const (
 	defPlayerWidth  = 1920
 	defPlayerHeight = 1080
 	defFontName     = "Arial"
+	// the style fields written if 0
+	defStyleOutline = 2
+	defStyleMarginH = 20
+	defStyleMarginV = 2
 )
 
 // validate subtitle
 func (as *Subtitle) validate() error {
+	return as.Validate()
+}
+
+// Validate checks the whole subtitle and returns ValidationErrors holding
+// every problem found, or nil if the subtitle is valid. The events of large
+// subtitles are checked in parallel, the errors are still in order.
+func (as *Subtitle) Validate() error {
+	return as.validateWith(0)
+}
+
+// validateWith is Validate checking the events on up to workers goroutines,
+// GOMAXPROCS if workers <= 0
+func (as *Subtitle) validateWith(workers int) error {
+	var errs ValidationErrors
 
 	if as.Timer < 0 {
-		return fmt.Errorf("Invalid timer: %f", as.Timer)
+		errs = append(errs, &ValidationError{Index: -1, Field: "Timer", Err: fmt.Errorf("Invalid timer: %f", as.Timer)})
+	}
+	if as.WrapStyle < WrapSmart || as.WrapStyle > WrapSmartLower {
+		errs = append(errs, &ValidationError{Index: -1, Field: "WrapStyle", Err: fmt.Errorf("Invalid wrap style: %d", as.WrapStyle)})
+	}
+	if err := as.YCbCrMatrix.validate(); err != nil {
+		errs = append(errs, &ValidationError{Index: -1, Field: "YCbCrMatrix", Err: err})
+	}
+	if err := as.Collisions.validate(); err != nil {
+		errs = append(errs, &ValidationError{Index: -1, Field: "Collisions", Err: err})
+	}
+	if as.Language != "" {
+		if _, err := language.Parse(as.Language); err != nil {
+			errs = append(errs, &ValidationError{Index: -1, Field: "Language", Err: fmt.Errorf("Invalid language: %s", as.Language)})
+		}
 	}
 
-	for _, style := range as.Styles {
+	for i, style := range as.Styles {
 		if style == nil {
-			return fmt.Errorf("Style cannot be nil")
+			errs = append(errs, &ValidationError{Section: "Styles", Index: i, Err: fmt.Errorf("Style cannot be nil")})
+			continue
 		}
-		if err := style.validate(); err != nil {
-			return err
+		for _, err := range style.problems() {
+			err.Section, err.Index = "Styles", i
+			errs = append(errs, err)
 		}
 	}
 
-	for _, evt := range as.Events {
-		if evt == nil {
-			return fmt.Errorf("Event cannot be nil")
+	workers = workerCount(workers)
+	ranges := eventRanges(len(as.Events), workers)
+	chunks := make([]ValidationErrors, len(ranges))
+	parallel(len(ranges), workers, func(c int) {
+		for i := ranges[c].lo; i < ranges[c].hi; i++ {
+			evt := as.Events[i]
+			if evt == nil {
+				chunks[c] = append(chunks[c], &ValidationError{Section: "Events", Index: i, Err: fmt.Errorf("Event cannot be nil")})
+				continue
+			}
+			for _, err := range evt.problems() {
+				err.Section, err.Index = "Events", i
+				chunks[c] = append(chunks[c], err)
+			}
 		}
-		if err := evt.validate(); err != nil {
-			return err
+	})
+	for _, chunk := range chunks {
+		errs = append(errs, chunk...)
+	}
+
+	for i, raw := range as.RawSections {
+		if raw == nil {
+			errs = append(errs, &ValidationError{Section: "RawSections", Index: i, Err: fmt.Errorf("Section cannot be nil")})
+		} else if err := raw.validate(); err != nil {
+			errs = append(errs, &ValidationError{Section: "RawSections", Index: i, Err: err})
 		}
 	}
 
-	return nil
+	if len(errs) == 0 {
+		return nil
+	}
+	for _, err := range errs {
+		err.Line = as.sourceLine(err.Section, err.Index, err.Field)
+	}
+	return errs
 }
 
-// fulfill subtitle with some default values
+// fulfill subtitle with some default values. It is called on copies of a
+// subtitle, which share its styles: those are copied before any change.
 func (as *Subtitle) fulfill() {
 	if as.OriginScript == "" {
 		as.OriginScript = "unknown"
@@ -150,59 +401,224 @@ func (as *Subtitle) fulfill() {
 		as.PlayerWidth = defPlayerWidth
 		as.PlayerHeight = defPlayerHeight
 	} else if as.PlayerWidth == 0 {
-		as.PlayerWidth = defPlayerWidth * (as.PlayerHeight / defPlayerHeight)
+		as.PlayerWidth = as.PlayerHeight * defPlayerWidth / defPlayerHeight
 	} else if as.PlayerHeight == 0 {
-		as.PlayerHeight = defPlayerHeight * (as.PlayerWidth / defPlayerWidth)
+		as.PlayerHeight = as.PlayerWidth * defPlayerHeight / defPlayerWidth
 	}
-	for _, style := range as.Styles {
-		if style.FontName == "" {
-			style.FontName = defFontName
+	copied := false
+	for i, style := range as.Styles {
+		if style == nil || style.FontName != "" {
+			continue
 		}
+		if !copied {
+			as.Styles, copied = copyStyles(as.Styles), true
+		}
+		as.Styles[i].FontName = defFontName
 	}
 }
 
-const assV4Tpl = `
+// assV4Tpl is the output template, each section and the event lines are
+// defined separately so they can be written on their own
+const assV4Tpl = `{{define "Script Info"}}
 [Script Info]
 Title: {{.Title}}
 Original Script: {{.OriginScript}}
 ScriptType: v4.00+
-Collisions: Normal
+Collisions: {{with .Collisions}}{{.}}{{else}}Normal{{end}}
 PlayResX: {{.PlayerWidth}}
 PlayResY: {{.PlayerHeight}}
-Timer: {{printf "%.4f" .Timer}}
+{{with .LayoutResX}}LayoutResX: {{.}}
+{{end}}{{with .LayoutResY}}LayoutResY: {{.}}
+{{end}}Timer: {{printf "%.4f" .Timer}}
+WrapStyle: {{printf "%d" .WrapStyle}}
+ScaledBorderAndShadow: {{if .ScaledBorderAndShadow}}yes{{else}}no{{end}}
+{{if .Kerning}}Kerning: yes
+{{end}}{{with .YCbCrMatrix}}YCbCr Matrix: {{.}}
+{{end}}{{with .Language}}Language: {{.}}
+{{end}}{{end}}
 
+{{- define "V4+ Styles"}}
 [V4+ Styles]
 Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
 {{range .Styles -}}
-Style: {{.Name}},{{.FontName}},{{.FontSize}},&H{{.PrimaryColor}},&H{{.SecondColor}},&H{{.OutlineColor}},&H{{.BackColor}},1,0,0,0,100,100,0,0,1,2,0,2,20,20,2,0
+Style: {{.Name}},{{.FontName}},{{.FontSize}},&H{{.PrimaryColor}},&H{{.SecondColor}},&H{{.OutlineColor}},&H{{.BackColor}},{{bold .Bold}},{{.Italic}},{{.Underline}},{{.StrikeOut}},{{scale .ScaleX}},{{scale .ScaleY}},{{number .Spacing}},{{number .Angle}},{{borderStyle .BorderStyle}},{{number (outline .Outline)}},{{number .Shadow}},{{alignment .Alignment}},{{marginH .MarginL}},{{marginH .MarginR}},{{marginV .MarginV}},{{.Encoding}}
+{{end}}
 {{end}}
 
+{{- define "events header"}}
 [Events]
 Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
-{{range .Events -}}
-Dialogue: {{.Layer}},{{.Start}},{{.End}},{{.Style}},{{.Name}},{{printf "%04d" .MarginL}},{{printf "%04d" .MarginR}},{{printf "%04d" .MarginV}},{{.Effect}},{{.Text}}
 {{end}}
+
+{{- define "event" -}}
+{{if .Comment}}Comment{{else}}Dialogue{{end}}: {{.Layer}},{{.Start}},{{.End}},{{.Style}},{{.Name}},{{margin .MarginL}},{{margin .MarginR}},{{margin .MarginV}},{{.Effect}},{{if .Forced}}{forced}{{end}}{{extra .Extra}}{{text .Text}}
+{{end}}
+
+{{- define "Events"}}{{template "events header" .}}{{range .Events}}{{template "event" .}}{{end}}{{end}}
+
+{{- define "header"}}{{template "Script Info" .}}{{template "V4+ Styles" .}}{{template "events header" .}}{{end}}
 `
 
+var (
+	defaultTplOnce sync.Once
+	defaultTpl     *template.Template
+	defaultTplErr  error
+)
+
+// parseTemplate parses an output template with the default functions
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("ass").Funcs(template.FuncMap{
+		"text":   sanitizeText,
+		"margin": paddedMargin,
+		"extra":  extraBlock,
+		// the style fields
+		"scale":       scale,
+		"number":      formatNumber,
+		"borderStyle": borderStyle,
+		"alignment":   alignment,
+		"bold":        bold,
+		"outline":     outline,
+		"marginH":     marginH,
+		"marginV":     marginV,
+	}).Parse(text)
+}
+
+// newTemplate returns a copy of the output template, which is parsed once
+// and can be customized by the caller
+func newTemplate() (*template.Template, error) {
+	defaultTplOnce.Do(func() {
+		defaultTpl, defaultTplErr = parseTemplate(assV4Tpl)
+	})
+	if defaultTplErr != nil {
+		return nil, fmt.Errorf("Invalid output template: %v", defaultTplErr)
+	}
+	return defaultTpl.Clone()
+}
+
+func paddedMargin(v uint) string {
+	return fmt.Sprintf("%04d", v)
+}
+
 // WriteTo write ass subtitle to destination
 func (as Subtitle) WriteTo(w io.Writer) (int64, error) {
+	return as.WriteToWithOptions(w)
+}
+
+// WriteToWithOptions write ass subtitle to destination, the same as WriteTo
+// but the output can be tweaked with options
+func (as Subtitle) WriteToWithOptions(w io.Writer, opts ...WriteOption) (int64, error) {
+	options := defaultWriteOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.canonical {
+		options.sections = defaultWriteOptions().sections
+		options.padding = true
+	}
+	if err := options.validate(); err != nil {
+		return 0, err
+	}
+
 	err := as.validate()
 	if err != nil {
 		return 0, err
 	}
+	if options.canonical {
+		as.canonicalize()
+	}
 
 	// fulfill subtitle, add some default values
 	as.fulfill()
+	if options.sortEvents {
+		as.Events = sortedEvents(as.Events)
+	}
 
-	tpl := template.New("ass")
-	tpl.Parse(assV4Tpl)
+	// the direct serializer is used unless the template is customized
+	write := func(dst io.Writer, section string) error {
+		return writeSection(dst, &as, section, options.padding)
+	}
+	if options.template != "" || options.funcs != nil {
+		tpl, err := newTemplate()
+		if err != nil {
+			return 0, err
+		}
+		if !options.padding {
+			tpl.Funcs(template.FuncMap{"margin": func(v uint) uint { return v }})
+		}
+		if options.funcs != nil {
+			tpl.Funcs(options.funcs)
+		}
+		if options.template != "" {
+			if _, err = tpl.Parse(options.template); err != nil {
+				return 0, err
+			}
+		}
+		write = func(dst io.Writer, section string) error {
+			return tpl.ExecuteTemplate(dst, section, as)
+		}
+	}
 
-	writer := bufio.NewWriter(w)
-	err = tpl.Execute(writer, as)
+	// count what really reaches w, after encoding and flushing
+	counter := &countingWriter{w: w}
+	out, err := encodeWriter(counter, options.encoding)
 	if err != nil {
 		return 0, err
 	}
-	n := writer.Buffered()
-	err = writer.Flush()
-	return int64(n), err
+	writer := bufio.NewWriter(out)
+	if (options.bom && options.encoding == EncodingUTF8) || options.encoding == EncodingUTF8BOM {
+		writer.WriteString(utf8BOM)
+	}
+	var dst io.Writer = writer
+	if options.lineEnding != LF {
+		dst = &lineEndingWriter{w: writer, ending: []byte(options.lineEnding)}
+	}
+	for _, section := range options.sections {
+		if err = write(dst, section); err != nil {
+			return counter.n, err
+		}
+	}
+	for _, raw := range as.RawSections {
+		if _, err = raw.WriteTo(dst); err != nil {
+			return counter.n, err
+		}
+	}
+	if _, err = io.WriteString(dst, "\n"); err != nil {
+		return counter.n, err
+	}
+	if err = writer.Flush(); err != nil {
+		return counter.n, err
+	}
+	err = out.Close()
+	return counter.n, err
+}
+
+// WriteToContext is the same as WriteToWithOptions, but gives up writing
+// when ctx is done
+func (as Subtitle) WriteToContext(ctx context.Context, w io.Writer, opts ...WriteOption) (int64, error) {
+	return as.WriteToWithOptions(&contextWriter{ctx: ctx, w: w}, opts...)
+}
+
+// contextWriter fails writing once its context is done
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (cw *contextWriter) Write(p []byte) (int, error) {
+	if err := cw.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cw.w.Write(p)
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
 }
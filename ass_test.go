@@ -1,12 +1,28 @@
 package ass
 
-import "testing"
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
 
 func TestEventValidate(t *testing.T) {
 	cases := []struct {
 		input Event
 		valid bool
-	}{}
+	}{
+		{Event{Start: "0:00:00.00", End: "0:00:01.00"}, true},
+		{Event{Start: "0:00:00:00", End: "0:00:01:00"}, true},
+		{Event{Start: "0:00:00", End: "0:00:01.00"}, false},
+		{Event{Start: "0:00:00.00", End: "0:00:01.00", Name: "Tom, Jerry"}, false},
+		{Event{Start: "0:00:00.00", End: "0:00:01.00", Style: "Main\n"}, false},
+		{Event{Start: "0:00:00.00", End: "0:00:01.00", Text: "Hello, world"}, true},
+		{Event{Start: "0:00:01.00", End: "0:00:01.00"}, false},
+		{Event{Start: "0:00:02.00", End: "0:00:01.00"}, false},
+		{Event{Start: "-0:00:01.00", End: "0:00:01.00"}, false},
+		{Event{Start: "0:65:00.00", End: "1:00:00.00"}, false},
+	}
 
 	for _, c := range cases {
 		err := c.input.validate()
@@ -19,3 +35,66 @@ func TestEventValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestWriteSanitizeText(t *testing.T) {
+	sub := Subtitle{
+		Events: []*Event{{Start: "0:00:00.00", End: "0:00:01.00", Text: "line1\r\nline2\nline3"}},
+	}
+	var buf bytes.Buffer
+	if _, err := sub.WriteTo(&buf); err != nil {
+		t.Fatalf("Expect write success, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), `,line1\Nline2\Nline3`+"\n") {
+		t.Errorf("Expect line breaks converted, got: %s", buf.String())
+	}
+}
+
+// limitWriter fails after writing limit bytes
+type limitWriter struct {
+	limit int
+	buf   bytes.Buffer
+}
+
+func (lw *limitWriter) Write(p []byte) (int, error) {
+	if len(p) > lw.limit {
+		lw.buf.Write(p[:lw.limit])
+		n := lw.limit
+		lw.limit = 0
+		return n, io.ErrShortWrite
+	}
+	lw.limit -= len(p)
+	return lw.buf.Write(p)
+}
+
+func TestWriteToByteCount(t *testing.T) {
+	sub := Subtitle{}
+	for i := 0; i < 500; i++ {
+		sub.Events = append(sub.Events, &Event{Start: "0:00:00.00", End: "0:00:01.00", Text: strings.Repeat("text ", 10)})
+	}
+
+	cases := []struct {
+		opts []WriteOption
+	}{
+		{nil},
+		{[]WriteOption{WithLineEnding(CRLF), WithBOM(true)}},
+		{[]WriteOption{WithEncoding(EncodingUTF16LE)}},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		n, err := sub.WriteToWithOptions(&buf, c.opts...)
+		if err != nil {
+			t.Errorf("Expect write success, got: %v", err)
+			continue
+		}
+		if n != int64(buf.Len()) {
+			t.Errorf("Expect %d bytes written, got: %d", buf.Len(), n)
+		}
+	}
+
+	lw := &limitWriter{limit: 10000}
+	n, err := sub.WriteTo(lw)
+	if err == nil || n != 10000 || lw.buf.Len() != 10000 {
+		t.Errorf("Expect short write error after 10000 bytes, got: %d %v", n, err)
+	}
+}
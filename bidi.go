@@ -0,0 +1,186 @@
+package ass
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// The bidi control characters
+const (
+	lrm = '\u200E' // left-to-right mark
+	rlm = '\u200F' // right-to-left mark
+	alm = '\u061C' // arabic letter mark
+	lre = '\u202A' // left-to-right embedding
+	rle = '\u202B' // right-to-left embedding
+	pdf = '\u202C' // pop directional formatting
+	lro = '\u202D' // left-to-right override
+	rlo = '\u202E' // right-to-left override
+	lri = '\u2066' // left-to-right isolate
+	rli = '\u2067' // right-to-left isolate
+	fsi = '\u2068' // first strong isolate
+	pdi = '\u2069' // pop directional isolate
+)
+
+// isBidiControl reports whether r is an invisible bidi control character
+func isBidiControl(r rune) bool {
+	switch r {
+	case lrm, rlm, alm, lre, rle, pdf, lro, rlo, lri, rli, fsi, pdi:
+		return true
+	}
+	return false
+}
+
+// isZeroWidth reports whether r takes no width of its own: bidi controls
+// and combining marks, like the Arabic vowel signs
+func isZeroWidth(r rune) bool {
+	return isBidiControl(r) || unicode.Is(unicode.Mn, r)
+}
+
+// direction returns the strong direction of r: 1 for right-to-left, -1 for
+// left-to-right, 0 if neutral
+func direction(r rune) int {
+	p, _ := bidi.LookupRune(r)
+	switch p.Class() {
+	case bidi.R, bidi.AL:
+		return 1
+	case bidi.L:
+		return -1
+	}
+	return 0
+}
+
+// IsRTL reports whether the first strong character of the text, tags
+// aside, is right-to-left, as in Arabic or Hebrew dialogue
+func IsRTL(text string) bool {
+	for _, r := range StripTags(text) {
+		if isBidiControl(r) {
+			continue
+		}
+		if d := direction(r); d != 0 {
+			return d > 0
+		}
+	}
+	return false
+}
+
+// BidiMark is how MarkRTL sets the direction of right-to-left lines
+type BidiMark int
+
+// The bidi marks
+const (
+	// MarkRLM starts the lines with a right-to-left mark, U+200F
+	MarkRLM BidiMark = iota
+	// MarkRLE embeds the lines in a right-to-left embedding, U+202B to
+	// U+202C
+	MarkRLE
+)
+
+// MarkRTL sets the direction of each right-to-left line of the text, see
+// IsRTL, so that renderers defaulting to left-to-right put the neutral
+// characters, like the punctuation at the end, on the right side. The lines
+// already starting with a bidi control are left as they are.
+func MarkRTL(text string, mark BidiMark) string {
+	lines := strings.Split(text, `\N`)
+	for i, line := range lines {
+		plain := StripTags(line)
+		if r := []rune(plain); !IsRTL(plain) || isBidiControl(r[0]) {
+			continue
+		}
+		if mark == MarkRLE {
+			lines[i] = insertAfterTags(line, string(rle)) + string(pdf)
+		} else {
+			lines[i] = insertAfterTags(line, string(rlm))
+		}
+	}
+	return strings.Join(lines, `\N`)
+}
+
+// MarkRTL marks the right-to-left lines of all the events, see MarkRTL, and
+// returns the number of changed events
+func (as *Subtitle) MarkRTL(mark BidiMark) int {
+	n := 0
+	for _, evt := range as.Events {
+		if evt == nil {
+			continue
+		}
+		if text := MarkRTL(evt.Text, mark); text != evt.Text {
+			evt.Text = text
+			n++
+		}
+	}
+	return n
+}
+
+// balanceBidi closes the embeddings and isolates still open at the end of
+// each line and opens them again on the next one, since renderers reset
+// the direction at line breaks
+func balanceBidi(text string) string {
+	if !strings.ContainsAny(text, string([]rune{lre, rle, lro, rlo, lri, rli, fsi})) {
+		return text
+	}
+	lines := strings.Split(text, `\N`)
+	var open []rune
+	for i, line := range lines {
+		reopen := string(open)
+		for _, r := range StripTags(line) {
+			switch r {
+			case lre, rle, lro, rlo, lri, rli, fsi:
+				open = append(open, r)
+			case pdf, pdi:
+				if len(open) > 0 {
+					open = open[:len(open)-1]
+				}
+			}
+		}
+		var closing strings.Builder
+		for j := len(open) - 1; j >= 0; j-- {
+			switch open[j] {
+			case lri, rli, fsi:
+				closing.WriteRune(pdi)
+			default:
+				closing.WriteRune(pdf)
+			}
+		}
+		if i == len(lines)-1 {
+			closing.Reset()
+		}
+		lines[i] = insertAfterTags(line, reopen) + closing.String()
+	}
+	return strings.Join(lines, `\N`)
+}
+
+// MixedDirectionRule reports the events mixing left-to-right and
+// right-to-left text between two override blocks without any bidi control,
+// which renderers may lay out in the wrong order
+func MixedDirectionRule(severity Severity) Rule {
+	return eventRule(func(evt *Event) *Issue {
+		for _, p := range splitText(evt.Text) {
+			if p.Override {
+				continue
+			}
+			for _, span := range strings.Split(p.Text, `\N`) {
+				ltr, rtl := false, false
+				for _, r := range span {
+					if isBidiControl(r) {
+						ltr, rtl = false, false
+						break
+					}
+					switch direction(r) {
+					case 1:
+						rtl = true
+					case -1:
+						ltr = true
+					}
+				}
+				if ltr && rtl {
+					return &Issue{Severity: severity, Rule: "mixed-direction", Field: "Text",
+						Message: fmt.Sprintf("Mixed text directions without bidi control: %s", span)}
+				}
+			}
+		}
+		return nil
+	})
+}
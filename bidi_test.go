@@ -0,0 +1,68 @@
+package ass
+
+import "testing"
+
+func TestIsRTL(t *testing.T) {
+	cases := []struct {
+		text   string
+		expect bool
+	}{
+		{"Hello", false},
+		{"שלום", true},
+		{`{\i1}... مرحبا world`, true},
+		{"123 abc שלום", false},
+		{"", false},
+	}
+	for _, c := range cases {
+		if got := IsRTL(c.text); got != c.expect {
+			t.Errorf("Expect %v for %q, got: %v", c.expect, c.text, got)
+		}
+	}
+}
+
+func TestMarkRTL(t *testing.T) {
+	cases := []struct {
+		text   string
+		mark   BidiMark
+		expect string
+	}{
+		{"Hello", MarkRLM, "Hello"},
+		{`{\i1}שלום!\NHi`, MarkRLM, "{\\i1}\u200fשלום!\\NHi"},
+		{`שלום!\Nمرحبا`, MarkRLE, "\u202bשלום!\u202c\\N\u202bمرحبا\u202c"},
+		{"\u200fשלום!", MarkRLE, "\u200fשלום!"},
+	}
+	for _, c := range cases {
+		if got := MarkRTL(c.text, c.mark); got != c.expect {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+	}
+
+	sub := &Subtitle{Events: []*Event{{Text: "שלום"}, {Text: "Hello"}, nil}}
+	if n := sub.MarkRTL(MarkRLM); n != 1 || sub.Events[0].Text != "\u200fשלום" {
+		t.Errorf("Expect 1 marked event, got: %d, %q", n, sub.Events[0].Text)
+	}
+}
+
+func TestWrapBidi(t *testing.T) {
+	text := Wrap("\u202bאבג דהו זחט\u202c", WrapOptions{Width: 7, Style: WrapEndOfLine})
+	if expect := "\u202bאבג דהו\u202c\\N\u202bזחט\u202c"; text != expect {
+		t.Errorf("Expect %q, got: %q", expect, text)
+	}
+	if w := countRunes("\u200fשָׁלוֹם"); w != 4 {
+		t.Errorf("Expect the marks to take no width, got: %v", w)
+	}
+}
+
+func TestMixedDirectionRule(t *testing.T) {
+	sub := &Subtitle{Events: []*Event{
+		{Text: "Hello world"},
+		{Text: "שלום world"},
+		{Text: `{\i1}שלום{\i0} world`},
+		{Text: "\u200fשלום world"},
+		{Text: "שלום world", Comment: true},
+	}}
+	issues := MixedDirectionRule(SeverityWarning)(sub)
+	if len(issues) != 1 || issues[0].Index != 1 || issues[0].Rule != "mixed-direction" {
+		t.Errorf("Expect an issue for event 1, got: %+v", issues)
+	}
+}
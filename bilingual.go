@@ -0,0 +1,132 @@
+package ass
+
+import (
+	"fmt"
+	"sort"
+)
+
+// BilingualMode is how two languages are combined
+type BilingualMode int
+
+// The bilingual modes
+const (
+	// BilingualDualLine appends the secondary text as a new line of the primary event
+	BilingualDualLine BilingualMode = iota
+	// BilingualTopBottom keeps separate events, the secondary ones retimed to
+	// the primary events they overlap and given SecondaryStyle
+	BilingualTopBottom
+)
+
+// BilingualOptions configures MergeBilingual
+type BilingualOptions struct {
+	Mode BilingualMode
+	// MinOverlap is the part of the shorter event two events must overlap
+	// to be aligned, 0 means any overlap
+	MinOverlap float64
+	// SecondaryTags are override tags put before the secondary text in
+	// dual line mode, e.g. \fs30
+	SecondaryTags string
+	// SecondaryStyle is the style of the secondary events in top/bottom mode,
+	// they keep their own style if empty
+	SecondaryStyle string
+}
+
+// MergeBilingual combines two subtitles of different languages into a new
+// one. Each secondary event is aligned to the primary event it overlaps the
+// most, secondary events overlapping none are kept on their own. The script
+// info comes from primary, styles from both with primary ones preferred.
+func MergeBilingual(primary, secondary *Subtitle, opts BilingualOptions) (*Subtitle, error) {
+	type timed struct {
+		evt        *Event
+		start, end Timestamp
+	}
+	load := func(sub *Subtitle) ([]timed, error) {
+		var list []timed
+		for _, evt := range sub.Events {
+			if evt == nil {
+				return nil, fmt.Errorf("Event cannot be nil")
+			}
+			start, end, err := evt.times()
+			if err != nil {
+				return nil, err
+			}
+			copied := evt.clone()
+			list = append(list, timed{&copied, start, end})
+		}
+		return list, nil
+	}
+	prim, err := load(primary)
+	if err != nil {
+		return nil, err
+	}
+	sec, err := load(secondary)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := *primary
+	merged.Styles = append([]*Style{}, primary.Styles...)
+	for _, style := range secondary.Styles {
+		if style != nil && merged.styleByName(style.Name) == nil {
+			merged.Styles = append(merged.Styles, style)
+		}
+	}
+
+	var extra []*Event
+	for _, s := range sec {
+		best, bestOverlap := -1, Timestamp(0)
+		for i, p := range prim {
+			overlap := minTimestamp(p.end, s.end) - maxTimestamp(p.start, s.start)
+			shorter := minTimestamp(p.end-p.start, s.end-s.start)
+			if overlap <= 0 || float64(overlap) < opts.MinOverlap*float64(shorter) {
+				continue
+			}
+			if overlap > bestOverlap {
+				best, bestOverlap = i, overlap
+			}
+		}
+
+		switch {
+		case best >= 0 && opts.Mode == BilingualDualLine:
+			p := prim[best].evt
+			p.Text += `\N`
+			if opts.SecondaryTags != "" {
+				p.Text += "{" + opts.SecondaryTags + "}"
+			}
+			p.Text += s.evt.Text
+			continue
+		case best >= 0:
+			s.evt.Start, s.evt.End = prim[best].evt.Start, prim[best].evt.End
+		}
+		if opts.Mode == BilingualTopBottom && opts.SecondaryStyle != "" {
+			s.evt.Style = opts.SecondaryStyle
+		}
+		extra = append(extra, s.evt)
+	}
+
+	merged.Events = make([]*Event, 0, len(prim)+len(extra))
+	for _, p := range prim {
+		merged.Events = append(merged.Events, p.evt)
+	}
+	merged.Events = append(merged.Events, extra...)
+	sort.SliceStable(merged.Events, func(i, j int) bool {
+		a, _ := merged.Events[i].StartTime()
+		b, _ := merged.Events[j].StartTime()
+		return a < b
+	})
+	return &merged, nil
+}
+
+func minTimestamp(a, b Timestamp) Timestamp {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxTimestamp(a, b Timestamp) Timestamp {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,50 @@
+package ass
+
+import "testing"
+
+func TestMergeBilingual(t *testing.T) {
+	primary := &Subtitle{
+		Title:  "EN",
+		Styles: []*Style{{Name: "Default"}},
+		Events: []*Event{
+			{Start: "0:00:01.00", End: "0:00:03.00", Style: "Default", Text: "Hello"},
+			{Start: "0:00:04.00", End: "0:00:06.00", Style: "Default", Text: "Bye"},
+		},
+	}
+	secondary := &Subtitle{
+		Styles: []*Style{{Name: "Default"}, {Name: "JP"}},
+		Events: []*Event{
+			{Start: "0:00:01.10", End: "0:00:02.90", Style: "JP", Text: "こんにちは"},
+			{Start: "0:00:03.00", End: "0:00:03.50", Style: "JP", Text: "えっと"},
+			{Start: "0:00:03.90", End: "0:00:06.00", Style: "JP", Text: "さようなら"},
+		},
+	}
+
+	dual, err := MergeBilingual(primary, secondary, BilingualOptions{SecondaryTags: `\fs30`})
+	if err != nil {
+		t.Fatalf("Expect merge success, got: %v", err)
+	}
+	texts := []string{`Hello\N{\fs30}こんにちは`, "えっと", `Bye\N{\fs30}さようなら`}
+	if len(dual.Events) != len(texts) {
+		t.Fatalf("Expect %d events, got: %d", len(texts), len(dual.Events))
+	}
+	for i, text := range texts {
+		if dual.Events[i].Text != text {
+			t.Errorf("Expect %q, got: %q", text, dual.Events[i].Text)
+		}
+	}
+	if dual.Title != "EN" || len(dual.Styles) != 2 || primary.Events[0].Text != "Hello" {
+		t.Errorf("Unexpected merged subtitle: %+v", dual)
+	}
+
+	tb, err := MergeBilingual(primary, secondary, BilingualOptions{Mode: BilingualTopBottom, SecondaryStyle: "Top"})
+	if err != nil {
+		t.Fatalf("Expect merge success, got: %v", err)
+	}
+	if len(tb.Events) != 5 {
+		t.Fatalf("Expect 5 events, got: %d", len(tb.Events))
+	}
+	if evt := tb.Events[1]; evt.Style != "Top" || evt.Start != "0:00:01.00" || evt.End != "0:00:03.00" {
+		t.Errorf("Expect secondary event aligned to primary, got: %+v", evt)
+	}
+}
@@ -0,0 +1,310 @@
+package ass
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Cue is an event as pushed to live clients
+type Cue struct {
+	ID int64 `json:"id"`
+	// Start and End are in seconds
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Style string  `json:"style,omitempty"`
+	Name  string  `json:"name,omitempty"`
+	// Text is the text as displayed, lines separated by \n
+	Text string `json:"text"`
+}
+
+// cueBuffer is the number of cues queued for a client, a client falling
+// further behind is disconnected
+const cueBuffer = 64
+
+// Broadcaster pushes events to clients connected over Server-Sent Events or
+// WebSocket as they are published, for live captioning overlays. Joining
+// clients first receive the most recent cues.
+type Broadcaster struct {
+	mu      sync.Mutex
+	clients map[chan Cue]struct{}
+	recent  []Cue
+	catchUp int
+	nextID  int64
+	closed  bool
+}
+
+// NewBroadcaster creates a broadcaster sending the last catchUp cues to
+// the clients joining
+func NewBroadcaster(catchUp int) *Broadcaster {
+	return &Broadcaster{clients: make(map[chan Cue]struct{}), catchUp: catchUp}
+}
+
+// Publish validates the event and sends it to the connected clients
+func (b *Broadcaster) Publish(evt *Event) error {
+	if evt == nil {
+		return fmt.Errorf("Event cannot be nil")
+	}
+	if err := evt.validate(); err != nil {
+		return err
+	}
+	start, end, err := evt.times()
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return fmt.Errorf("Broadcaster is closed")
+	}
+	b.nextID++
+	cue := Cue{
+		ID:    b.nextID,
+		Start: start.Duration().Seconds(),
+		End:   end.Duration().Seconds(),
+		Style: evt.Style,
+		Name:  evt.Name,
+		Text:  strings.Join(displayLines(evt.Text), "\n"),
+	}
+	if b.catchUp > 0 {
+		b.recent = append(b.recent, cue)
+		if len(b.recent) > b.catchUp {
+			b.recent = append(b.recent[:0], b.recent[len(b.recent)-b.catchUp:]...)
+		}
+	}
+	for ch := range b.clients {
+		select {
+		case ch <- cue:
+		default:
+			// too slow, drop it
+			delete(b.clients, ch)
+			close(ch)
+		}
+	}
+	return nil
+}
+
+// Close disconnects all the clients
+func (b *Broadcaster) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for ch := range b.clients {
+		delete(b.clients, ch)
+		close(ch)
+	}
+}
+
+// subscribe registers a client, queuing the recent cues after lastID
+func (b *Broadcaster) subscribe(lastID int64) (chan Cue, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, false
+	}
+	ch := make(chan Cue, cueBuffer+len(b.recent))
+	for _, cue := range b.recent {
+		if cue.ID > lastID {
+			ch <- cue
+		}
+	}
+	b.clients[ch] = struct{}{}
+	return ch, true
+}
+
+func (b *Broadcaster) unsubscribe(ch chan Cue) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.clients[ch]; ok {
+		delete(b.clients, ch)
+		close(ch)
+	}
+}
+
+// ServeHTTP streams the cues as Server-Sent Events, or over a WebSocket
+// when the request is a WebSocket upgrade. SSE clients reconnecting with
+// Last-Event-ID only receive the cues they missed.
+func (b *Broadcaster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		b.serveWebSocket(w, r)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	lastID, _ := strconv.ParseInt(r.Header.Get("Last-Event-ID"), 10, 64)
+	ch, ok := b.subscribe(lastID)
+	if !ok {
+		http.Error(w, "Broadcaster is closed", http.StatusServiceUnavailable)
+		return
+	}
+	defer b.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case cue, ok := <-ch:
+			if !ok {
+				return
+			}
+			data, _ := json.Marshal(cue)
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: cue\ndata: %s\n\n", cue.ID, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// websocketGUID is the key suffix of the WebSocket handshake, RFC 6455
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+func (b *Broadcaster) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet || key == "" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+		http.Error(w, "Invalid WebSocket handshake", http.StatusBadRequest)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket unsupported", http.StatusInternalServerError)
+		return
+	}
+	ch, ok := b.subscribe(0)
+	if !ok {
+		http.Error(w, "Broadcaster is closed", http.StatusServiceUnavailable)
+		return
+	}
+	defer b.unsubscribe(ch)
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+	rw.WriteString("Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		return
+	}
+
+	// the client only sends control frames, stop when it goes away. Its
+	// pings are answered here, the only writer of the connection.
+	gone, done := make(chan struct{}), make(chan struct{})
+	defer close(done)
+	pings := make(chan []byte)
+	go func() {
+		defer close(gone)
+		readWebSocket(rw.Reader, pings, done)
+	}()
+	for {
+		select {
+		case payload := <-pings:
+			if err := writeWebSocketFrame(rw.Writer, 0xA, payload); err != nil {
+				return
+			}
+		case cue, ok := <-ch:
+			if !ok {
+				writeWebSocketFrame(rw.Writer, 0x8, nil)
+				return
+			}
+			data, _ := json.Marshal(cue)
+			if err := writeWebSocketFrame(rw.Writer, 0x1, data); err != nil {
+				return
+			}
+		case <-gone:
+			return
+		}
+	}
+}
+
+// writeWebSocketFrame writes an unmasked final frame
+func writeWebSocketFrame(w *bufio.Writer, opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126, byte(n>>8), byte(n))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(n))
+	}
+	w.Write(header)
+	w.Write(payload)
+	return w.Flush()
+}
+
+// readWebSocket reads the frames of the client until it closes or done is
+// closed. The payloads of the pings are sent to pings, to be answered with
+// pongs, the other frames are discarded.
+func readWebSocket(r *bufio.Reader, pings chan<- []byte, done <-chan struct{}) {
+	for {
+		var header [2]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return
+		}
+		opcode := header[0] & 0x0F
+		if opcode == 0x8 {
+			return
+		}
+		n := uint64(header[1] & 0x7F)
+		switch n {
+		case 126:
+			var ext [2]byte
+			if _, err := io.ReadFull(r, ext[:]); err != nil {
+				return
+			}
+			n = uint64(binary.BigEndian.Uint16(ext[:]))
+		case 127:
+			var ext [8]byte
+			if _, err := io.ReadFull(r, ext[:]); err != nil {
+				return
+			}
+			n = binary.BigEndian.Uint64(ext[:])
+		}
+		var mask [4]byte
+		if header[1]&0x80 != 0 {
+			if _, err := io.ReadFull(r, mask[:]); err != nil {
+				return
+			}
+		}
+		// control frames have at most 125 bytes
+		if opcode != 0x9 || n > 125 {
+			if _, err := io.CopyN(ioutil.Discard, r, int64(n)); err != nil {
+				return
+			}
+			continue
+		}
+		payload := make([]byte, n)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return
+		}
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+		select {
+		case pings <- payload:
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package ass
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBroadcasterSSE(t *testing.T) {
+	b := NewBroadcaster(1)
+	defer b.Close()
+	if err := b.Publish(&Event{Start: "0:00:01.00", End: "0:00:02.00", Text: "missed"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Publish(&Event{Start: "0:00:02.00", End: "0:00:03.00", Text: `{\i1}catch\Nup`}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Publish(&Event{Start: "bad", End: "0:00:03.00"}); err == nil {
+		t.Errorf("Expect error for an invalid event")
+	}
+
+	server := httptest.NewServer(b)
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expect text/event-stream, got: %s", ct)
+	}
+	reader := bufio.NewReader(resp.Body)
+	readCue := func() Cue {
+		var cue Cue
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("Expect a cue, got: %v", err)
+			}
+			if strings.HasPrefix(line, "data: ") {
+				if err := json.Unmarshal([]byte(line[6:]), &cue); err != nil {
+					t.Fatal(err)
+				}
+				return cue
+			}
+		}
+	}
+
+	if cue := readCue(); cue.ID != 2 || cue.Text != "catch\nup" || cue.Start != 2 {
+		t.Errorf("Expect the catch up cue, got: %+v", cue)
+	}
+	if err := b.Publish(&Event{Start: "0:00:03.00", End: "0:00:04.00", Name: "Host", Text: "live"}); err != nil {
+		t.Fatal(err)
+	}
+	if cue := readCue(); cue.ID != 3 || cue.Text != "live" || cue.Name != "Host" {
+		t.Errorf("Expect the live cue, got: %+v", cue)
+	}
+}
+
+func TestBroadcasterWebSocket(t *testing.T) {
+	b := NewBroadcaster(0)
+	server := httptest.NewServer(b)
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	io.WriteString(conn, "GET / HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n")
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Unexpected handshake: %d %v", resp.StatusCode, resp.Header)
+	}
+
+	if err := b.Publish(&Event{Start: "0:00:01.00", End: "0:00:02.00", Text: "over websocket"}); err != nil {
+		t.Fatal(err)
+	}
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		t.Fatal(err)
+	}
+	payload := make([]byte, header[1])
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		t.Fatal(err)
+	}
+	var cue Cue
+	if header[0] != 0x81 || json.Unmarshal(payload, &cue) != nil || cue.Text != "over websocket" {
+		t.Errorf("Expect a text frame with the cue, got: %x %s", header, payload)
+	}
+
+	// a masked ping is answered with a pong of the same payload
+	mask := []byte{1, 2, 3, 4}
+	ping := []byte{0x89, 0x80 | 4}
+	ping = append(ping, mask...)
+	for i, c := range []byte("beat") {
+		ping = append(ping, c^mask[i%4])
+	}
+	if _, err := conn.Write(ping); err != nil {
+		t.Fatal(err)
+	}
+	pong := make([]byte, 6)
+	if _, err := io.ReadFull(reader, pong); err != nil || string(pong) != "\x8a\x04beat" {
+		t.Errorf("Expect a pong frame, got: %x %v", pong, err)
+	}
+
+	b.Close()
+	if _, err := io.ReadFull(reader, header); err != nil || header[0] != 0x88 {
+		t.Errorf("Expect a close frame, got: %x %v", header, err)
+	}
+}
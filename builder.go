@@ -0,0 +1,66 @@
+package ass
+
+// Builder builds a subtitle with chained calls:
+//
+//	sub, err := ass.New("Title").
+//		Resolution(1920, 1080).
+//		Style(ass.Style{Name: "Default", FontSize: 48}).
+//		Dialogue(start, end, "Hello").
+//		Comment(start, end, "translator note").
+//		Build()
+type Builder struct {
+	sub   Subtitle
+	style string
+}
+
+// New starts building a subtitle with a title
+func New(title string) *Builder {
+	return &Builder{sub: Subtitle{Title: title}, style: "Default"}
+}
+
+// Resolution sets the script resolution, PlayResX and PlayResY
+func (b *Builder) Resolution(width, height uint) *Builder {
+	b.sub.PlayerWidth, b.sub.PlayerHeight = width, height
+	return b
+}
+
+// Style adds a style, used by the events added after it
+func (b *Builder) Style(style Style) *Builder {
+	b.sub.Styles = append(b.sub.Styles, &style)
+	b.style = style.Name
+	return b
+}
+
+// UseStyle sets the style of the events added after it
+func (b *Builder) UseStyle(name string) *Builder {
+	b.style = name
+	return b
+}
+
+// Dialogue adds a dialogue event of the current style
+func (b *Builder) Dialogue(start, end Timestamp, text string) *Builder {
+	return b.Event(Event{Start: start.String(), End: end.String(), Text: text})
+}
+
+// Comment adds a comment event of the current style
+func (b *Builder) Comment(start, end Timestamp, text string) *Builder {
+	return b.Event(Event{Start: start.String(), End: end.String(), Text: text, Comment: true})
+}
+
+// Event adds an event, of the current style if it has none
+func (b *Builder) Event(evt Event) *Builder {
+	if evt.Style == "" {
+		evt.Style = b.style
+	}
+	b.sub.Events = append(b.sub.Events, &evt)
+	return b
+}
+
+// Build validates the subtitle and returns it
+func (b *Builder) Build() (*Subtitle, error) {
+	sub := b.sub.Clone()
+	if err := sub.Validate(); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
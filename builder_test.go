@@ -0,0 +1,48 @@
+package ass
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuilder(t *testing.T) {
+	sec := func(s int) Timestamp { return Timestamp(time.Duration(s) * time.Second) }
+	sub, err := New("Built").
+		Resolution(1280, 720).
+		Dialogue(sec(0), sec(1), "default style").
+		Style(Style{Name: "Sign", FontSize: 30}).
+		Dialogue(sec(1), sec(2), "sign").
+		Comment(sec(2), sec(3), "note").
+		UseStyle("Default").
+		Event(Event{Start: "0:00:03.00", End: "0:00:04.00", Name: "Ann", Text: "custom"}).
+		Build()
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	if sub.Title != "Built" || sub.PlayerWidth != 1280 || sub.PlayerHeight != 720 || len(sub.Styles) != 1 {
+		t.Errorf("Unexpected subtitle: %+v", sub)
+	}
+	styles := []string{"Default", "Sign", "Sign", "Default"}
+	for i, evt := range sub.Events {
+		if evt.Style != styles[i] {
+			t.Errorf("Event %d: expect style %s, got: %s", i, styles[i], evt.Style)
+		}
+	}
+	if !sub.Events[2].Comment || sub.Events[1].Comment {
+		t.Errorf("Expect only the third event to be a comment")
+	}
+
+	var buf bytes.Buffer
+	if _, err := sub.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "\nComment: 0,0:00:02.00,0:00:03.00,Sign,,0000,0000,0000,,note\n") {
+		t.Errorf("Expect a Comment line, got: %s", buf.String())
+	}
+
+	if _, err := New("Invalid").Dialogue(sec(2), sec(1), "backwards").Build(); err == nil {
+		t.Errorf("Expect error for an event ending before it starts")
+	}
+}
@@ -0,0 +1,119 @@
+package ass
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// CensorMode is how Censor masks the listed words
+type CensorMode int
+
+// The censor modes
+const (
+	// CensorAsterisks keeps the first letter of the words and replaces the
+	// others by asterisks, e.g. d**n
+	CensorAsterisks CensorMode = iota
+	// CensorBleep replaces the words by an annotation, [bleep] by default
+	CensorBleep
+	// CensorRemove removes the events with any of the words
+	CensorRemove
+)
+
+// Censor masks a list of words in the plain text of the events, producing a
+// clean variant of a script. It is a Transformer.
+type Censor struct {
+	// Words are matched as whole words, ignoring the case. A word ending
+	// with * matches every word it starts, e.g. damn* matches damned.
+	Words []string
+	Mode  CensorMode
+	// Bleep is the annotation of CensorBleep, [bleep] if empty
+	Bleep string
+}
+
+var censorWordReg = regexp.MustCompile(`[\p{L}\p{N}]+(?:['’][\p{L}]+)*`)
+
+// match reports whether word is one of the censored words
+func (c Censor) match(word string) bool {
+	word = strings.ToLower(word)
+	for _, w := range c.Words {
+		w = strings.ToLower(w)
+		if prefix := strings.TrimSuffix(w, "*"); prefix != w {
+			if prefix != "" && strings.HasPrefix(word, prefix) {
+				return true
+			}
+		} else if word == w {
+			return true
+		}
+	}
+	return false
+}
+
+// CensorText masks the censored words in dialogue text, the override blocks
+// and drawings are left as they are. It reports whether any word was found.
+// CensorRemove masks the words as CensorAsterisks does.
+func (c Censor) CensorText(text string) (string, bool) {
+	parts := splitText(text)
+	found, drawing := false, false
+	for i, p := range parts {
+		if p.Override {
+			for _, tag := range splitTags(p.Text) {
+				if level, ok := drawingTag(tag); ok {
+					drawing = level > 0
+				}
+			}
+			continue
+		}
+		if drawing {
+			continue
+		}
+		parts[i].Text = censorWordReg.ReplaceAllStringFunc(p.Text, func(word string) string {
+			if !c.match(word) {
+				return word
+			}
+			found = true
+			if c.Mode == CensorBleep {
+				if c.Bleep == "" {
+					return "[bleep]"
+				}
+				return c.Bleep
+			}
+			_, size := utf8.DecodeRuneInString(word)
+			return word[:size] + strings.Repeat("*", utf8.RuneCountInString(word)-1)
+		})
+	}
+	if !found {
+		return text, false
+	}
+	return joinText(parts), true
+}
+
+// Transform censors the events of sub, comments included since they are
+// kept in the file
+func (c Censor) Transform(sub *Subtitle) error {
+	events := sub.Events[:0]
+	for _, evt := range sub.Events {
+		if evt == nil {
+			events = append(events, evt)
+			continue
+		}
+		text, found := c.CensorText(evt.Text)
+		if found && c.Mode == CensorRemove {
+			continue
+		}
+		evt.Text = text
+		events = append(events, evt)
+	}
+	for i := len(events); i < len(sub.Events); i++ {
+		sub.Events[i] = nil
+	}
+	sub.Events = events
+	return nil
+}
+
+// Censored returns a censored copy of the subtitle, see Censor
+func (as *Subtitle) Censored(c Censor) *Subtitle {
+	clean := as.Clone()
+	c.Transform(clean)
+	return clean
+}
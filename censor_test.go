@@ -0,0 +1,47 @@
+package ass
+
+import "testing"
+
+func TestCensorText(t *testing.T) {
+	cases := []struct {
+		censor Censor
+		text   string
+		expect string
+		found  bool
+	}{
+		{Censor{Words: []string{"damn"}}, `Damn it, {\i1}damn{\i0}! Damnation.`, `D*** it, {\i1}d***{\i0}! Damnation.`, true},
+		{Censor{Words: []string{"damn*"}}, `Damned, damnation.`, `D*****, d********.`, true},
+		{Censor{Words: []string{"heck"}, Mode: CensorBleep}, `What the heck?\NHeck's sake`, `What the [bleep]?\NHeck's sake`, true},
+		{Censor{Words: []string{"heck"}, Mode: CensorBleep, Bleep: "(bleep)"}, `heck`, `(bleep)`, true},
+		{Censor{Words: []string{"merde"}}, `Merde, żółw`, `M****, żółw`, true},
+		{Censor{Words: []string{"m"}}, `{\p1}m 0 0 l 1 1{\p0}clean`, `{\p1}m 0 0 l 1 1{\p0}clean`, false},
+		{Censor{Words: []string{"*"}}, `Anything`, `Anything`, false},
+	}
+	for _, c := range cases {
+		if got, found := c.censor.CensorText(c.text); got != c.expect || found != c.found {
+			t.Errorf("Expect %q, %v, got: %q, %v", c.expect, c.found, got, found)
+		}
+	}
+}
+
+func TestCensored(t *testing.T) {
+	sub := &Subtitle{Events: []*Event{
+		{Text: "Damn it"},
+		nil,
+		{Text: "Fine"},
+		{Text: "damn", Comment: true},
+	}}
+	clean := sub.Censored(Censor{Words: []string{"damn"}})
+	if len(clean.Events) != 4 || clean.Events[0].Text != "D*** it" || clean.Events[3].Text != "d***" || sub.Events[0].Text != "Damn it" {
+		t.Errorf("Expect a masked copy, got: %v", clean.Events)
+	}
+
+	clean = sub.Censored(Censor{Words: []string{"damn"}, Mode: CensorRemove})
+	if len(clean.Events) != 2 || clean.Events[1].Text != "Fine" || len(sub.Events) != 4 {
+		t.Errorf("Expect the events to be removed, got: %v", clean.Events)
+	}
+
+	if err := NewPipeline(Censor{Words: []string{"fine"}, Mode: CensorBleep}).Transform(sub); err != nil || sub.Events[2].Text != "[bleep]" {
+		t.Errorf("Expect the censor to run in a pipeline, got: %v, %v", sub.Events[2], err)
+	}
+}
@@ -0,0 +1,13 @@
+package ass
+
+// Clone returns a deep copy of the subtitle, styles and events included,
+// so the copy can be changed without touching the original
+func (as *Subtitle) Clone() *Subtitle {
+	cloned := *as
+	cloned.index = nil
+	cloned.Styles = copyStyles(as.Styles)
+	cloned.Events = copyEvents(as.Events)
+	cloned.lines = as.lines.clone(as, &cloned)
+	cloned.RawSections = copyRawSections(as.RawSections)
+	return &cloned
+}
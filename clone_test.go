@@ -0,0 +1,25 @@
+package ass
+
+import "testing"
+
+func TestClone(t *testing.T) {
+	sub := &Subtitle{
+		Title:  "Original",
+		Styles: []*Style{{Name: "Default"}},
+		Events: []*Event{{Text: "Hello"}, nil},
+	}
+	sub.Reindex()
+
+	cloned := sub.Clone()
+	cloned.Title = "SDH"
+	cloned.Styles[0].Name = "Changed"
+	cloned.Events[0].Text = "[music] Hello"
+	cloned.Events = append(cloned.Events, &Event{})
+
+	if sub.Title != "Original" || sub.Styles[0].Name != "Default" || sub.Events[0].Text != "Hello" || len(sub.Events) != 2 {
+		t.Errorf("Expect the original untouched, got: %+v", sub)
+	}
+	if cloned.Events[1] != nil || cloned.index != nil {
+		t.Errorf("Unexpected clone: %+v", cloned)
+	}
+}
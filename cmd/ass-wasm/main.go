@@ -0,0 +1,105 @@
+//go:build js && wasm
+// +build js,wasm
+
+// Command ass-wasm exposes the library to JavaScript, for browser based
+// subtitle editors:
+//
+//	GOOS=js GOARCH=wasm go build -o ass.wasm ./cmd/ass-wasm
+//
+// Once loaded with wasm_exec.js, the global ass object has:
+//
+//	ass.parse(text)          the subtitle as JSON
+//	ass.convert(text, "vtt") the subtitle as WebVTT, or "ass"
+//	ass.shift(text, seconds) the subtitle as ass, retimed
+//
+// The input text is SubRip or ass. Each function returns {value} or {error}.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"syscall/js"
+	"time"
+
+	"github.com/apigo/ass"
+)
+
+func main() {
+	js.Global().Set("ass", js.ValueOf(map[string]interface{}{
+		"parse":   function(parse),
+		"convert": function(convert),
+		"shift":   function(shift),
+	}))
+	select {}
+}
+
+// function wraps fn as a JavaScript function returning {value} or {error}
+func function(fn func(args []js.Value) (string, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		value, err := fn(args)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error()}
+		}
+		return map[string]interface{}{"value": value}
+	})
+}
+
+// read parses the first argument, SubRip or ass
+func read(args []js.Value) (*ass.Subtitle, error) {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return nil, fmt.Errorf("Expect the subtitle text as first argument")
+	}
+	text := args[0].String()
+	if strings.Contains(text, "-->") && !strings.Contains(text, "[Events]") {
+		return ass.ParseSRT(strings.NewReader(text))
+	}
+	return ass.Parse(strings.NewReader(text))
+}
+
+func parse(args []js.Value) (string, error) {
+	sub, err := read(args)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(sub)
+	return string(data), err
+}
+
+func convert(args []js.Value) (string, error) {
+	sub, err := read(args)
+	if err != nil {
+		return "", err
+	}
+	format := "ass"
+	if len(args) > 1 {
+		format = strings.ToLower(args[1].String())
+	}
+	var buf bytes.Buffer
+	switch format {
+	case "ass":
+		_, err = sub.WriteTo(&buf)
+	case "vtt":
+		_, err = sub.WriteVTT(&buf)
+	default:
+		return "", fmt.Errorf("Unsupported format: %s", format)
+	}
+	return buf.String(), err
+}
+
+func shift(args []js.Value) (string, error) {
+	sub, err := read(args)
+	if err != nil {
+		return "", err
+	}
+	if len(args) < 2 || args[1].Type() != js.TypeNumber {
+		return "", fmt.Errorf("Expect the seconds to shift as second argument")
+	}
+	if err := sub.Select().Shift(time.Duration(args[1].Float() * float64(time.Second))); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	_, err = sub.WriteTo(&buf)
+	return buf.String(), err
+}
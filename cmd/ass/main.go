@@ -0,0 +1,237 @@
+// Command ass converts, retimes, checks and merges subtitles.
+//
+//	ass convert in.srt out.ass
+//	ass shift -by 1.5s in.ass out.ass
+//	ass lint --profile netflix in.ass
+//	ass stats in.ass
+//	ass merge a.ass b.ass out.ass
+//
+// The input format is chosen by extension, .srt or ass. The output format
+// is chosen by extension too, .ass, .ssa, .srt or .vtt, and the output is
+// written as ass to stdout when omitted or -.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apigo/ass"
+)
+
+var profiles = map[string]ass.QCProfile{
+	ass.NetflixProfile.Name:         ass.NetflixProfile,
+	ass.NetflixChildrenProfile.Name: ass.NetflixChildrenProfile,
+}
+
+const usage = `Usage:
+  ass convert <in> [out]
+  ass shift -by <duration> <in> [out]
+  ass lint [--profile <name>] <in>
+  ass stats <in>
+  ass merge <a> <b> [out]
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes a command, returning the exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	cmd := &command{stdin: stdin, stdout: stdout}
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var exec func(args []string) error
+	switch args[0] {
+	case "convert":
+		exec = cmd.convert
+	case "shift":
+		flags.DurationVar(&cmd.by, "by", 0, "time to add to the events, negative to go back")
+		exec = cmd.shift
+	case "lint":
+		flags.StringVar(&cmd.profile, "profile", "", "quality control profile: "+strings.Join(profileNames(), ", "))
+		exec = cmd.lint
+	case "stats":
+		exec = cmd.stats
+	case "merge":
+		exec = cmd.merge
+	default:
+		fmt.Fprintf(stderr, "Unknown command: %s\n%s", args[0], usage)
+		return 2
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if err := exec(flags.Args()); err != nil {
+		fmt.Fprintln(stderr, err)
+		if err == errUsage {
+			fmt.Fprint(stderr, usage)
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+var errUsage = fmt.Errorf("Invalid arguments")
+
+type command struct {
+	stdin   io.Reader
+	stdout  io.Writer
+	by      time.Duration
+	profile string
+}
+
+func profileNames() []string {
+	var names []string
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// read reads a subtitle file, - is stdin
+func (c *command) read(path string) (*ass.Subtitle, error) {
+	var r io.Reader = c.stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	if strings.EqualFold(filepath.Ext(strings.TrimSuffix(path, ".gz")), ".srt") {
+		return ass.ParseSRT(r)
+	}
+	return ass.Parse(r)
+}
+
+// write writes a subtitle to the file of args[i], stdout if missing or -
+func (c *command) write(sub *ass.Subtitle, args []string, i int) error {
+	path := "-"
+	if i < len(args) {
+		path = args[i]
+	}
+	var writeTo func(io.Writer) (int64, error)
+	switch ext := strings.ToLower(filepath.Ext(path)); {
+	case path == "-":
+		writeTo = sub.WriteTo
+	case ext == ".ass" || ext == ".ssa":
+		return sub.WriteFile(path)
+	case ext == ".srt":
+		writeTo = sub.WriteSRT
+	case ext == ".vtt":
+		writeTo = sub.WriteVTT
+	default:
+		return fmt.Errorf("Unsupported output format: %s", path)
+	}
+
+	w := c.stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	_, err := writeTo(w)
+	return err
+}
+
+func (c *command) convert(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errUsage
+	}
+	sub, err := c.read(args[0])
+	if err != nil {
+		return err
+	}
+	return c.write(sub, args, 1)
+}
+
+func (c *command) shift(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errUsage
+	}
+	sub, err := c.read(args[0])
+	if err != nil {
+		return err
+	}
+	if err := sub.Select().Shift(c.by); err != nil {
+		return err
+	}
+	return c.write(sub, args, 1)
+}
+
+func (c *command) lint(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	sub, err := c.read(args[0])
+	if err != nil {
+		return err
+	}
+	var issues []ass.Issue
+	if c.profile != "" {
+		profile, ok := profiles[c.profile]
+		if !ok {
+			return fmt.Errorf("Unknown profile: %s", c.profile)
+		}
+		issues = profile.Check(sub).Issues
+	} else {
+		issues = ass.NewValidator(ass.Standard).Check(sub)
+	}
+
+	failed := false
+	for _, issue := range issues {
+		fmt.Fprintln(c.stdout, issue)
+		if issue.Severity >= ass.SeverityError {
+			failed = true
+		}
+	}
+	if failed {
+		return fmt.Errorf("%d issues found", len(issues))
+	}
+	return nil
+}
+
+func (c *command) stats(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	sub, err := c.read(args[0])
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sub.Stats())
+}
+
+func (c *command) merge(args []string) error {
+	if len(args) < 2 || len(args) > 3 {
+		return errUsage
+	}
+	a, err := c.read(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := c.read(args[1])
+	if err != nil {
+		return err
+	}
+	return c.write(ass.Merge(a, b, ass.MergeOptions{SortEvents: true}), args, 2)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ass-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	srt := filepath.Join(dir, "in.srt")
+	ioutil.WriteFile(srt, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:03,100\nA line far too long to be read in a tenth of a second\n"), 0644)
+	out := filepath.Join(dir, "out.ass")
+
+	cases := []struct {
+		args   []string
+		code   int
+		stdout string
+	}{
+		{[]string{"convert", srt, out}, 0, ""},
+		{[]string{"shift", "-by", "1.5s", out, "-"}, 0, "Dialogue: 0,0:00:02.50,0:00:03.50,Default,,0000,0000,0000,,Hello"},
+		{[]string{"convert", out, filepath.Join(dir, "out.vtt")}, 0, ""},
+		{[]string{"convert", out, filepath.Join(dir, "out.srt")}, 0, ""},
+		{[]string{"convert", out, filepath.Join(dir, "out.txt")}, 1, ""},
+		{[]string{"lint", "--profile", "netflix", out}, 1, "Reading speed too high"},
+		{[]string{"lint", "--profile", "unknown", out}, 1, ""},
+		{[]string{"stats", out}, 0, `"events": 2`},
+		{[]string{"merge", out, srt}, 0, "Style: Default_b"},
+		{[]string{"convert"}, 2, ""},
+		{[]string{"unknown"}, 2, ""},
+	}
+	for _, c := range cases {
+		var stdout, stderr bytes.Buffer
+		code := run(c.args, strings.NewReader(""), &stdout, &stderr)
+		if code != c.code {
+			t.Errorf("%v: expect exit code %d, got: %d (%s)", c.args, c.code, code, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), c.stdout) {
+			t.Errorf("%v: expect output containing %q, got: %s", c.args, c.stdout, stdout.String())
+		}
+	}
+
+	if vtt, err := ioutil.ReadFile(filepath.Join(dir, "out.vtt")); err != nil || !strings.HasPrefix(string(vtt), "WEBVTT") {
+		t.Errorf("Expect a WebVTT file, got: %s, %v", vtt, err)
+	}
+	if srt, err := ioutil.ReadFile(filepath.Join(dir, "out.srt")); err != nil || !strings.HasPrefix(string(srt), "1\n00:00:01,000 --> 00:00:02,000\nHello\n") {
+		t.Errorf("Expect a SubRip file, got: %s, %v", srt, err)
+	}
+}
@@ -0,0 +1,128 @@
+package ass
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Collisions is how the renderer moves lines overlapping each other
+type Collisions string
+
+// The collision modes defined by the ass spec
+const (
+	// CollisionsNormal moves the later line away from the earlier ones
+	CollisionsNormal Collisions = "Normal"
+	// CollisionsReverse moves the earlier lines to make room for the later
+	CollisionsReverse Collisions = "Reverse"
+)
+
+func (c Collisions) validate() error {
+	switch c {
+	case "", CollisionsNormal, CollisionsReverse:
+		return nil
+	}
+	return fmt.Errorf("Invalid collisions: %s", string(c))
+}
+
+// Collision is an event the renderer is predicted to move out of the way of
+// other events shown at the same time
+type Collision struct {
+	// Index is the displaced event
+	Index int
+	// Others are the events in its way, in the order of the events
+	Others []int
+	// Offset is how far the event is moved, in script pixels
+	Offset int
+}
+
+// PredictCollisions predicts the events displaced by the renderer because
+// they are shown at the same time, on the same layer and the same vertical
+// alignment as others, that of their style by default, and overlap them
+// given their vertical margins. The events with \pos or \move are never
+// displaced, their height is estimated by ApproxMeasurer, automatic
+// wrapping aside. Invalid events and comments are ignored.
+func (as *Subtitle) PredictCollisions() []Collision {
+	return as.PredictCollisionsWith(ApproxMeasurer{})
+}
+
+// PredictCollisionsWith is PredictCollisions with the heights of the events
+// measured by m
+func (as *Subtitle) PredictCollisionsWith(m TextMeasurer) []Collision {
+	type placed struct {
+		index      int
+		start, end Timestamp
+		layer      int
+		band       int
+		margin     int // from the edge of the band, 0 in the middle
+		height     int
+		offset     int
+	}
+	var events []placed
+	for i, evt := range as.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		start, end, err := evt.times()
+		if err != nil {
+			continue
+		}
+		style := as.styleByName(evt.Style)
+		band, positioned := collisionBand(evt.Text, style.align())
+		if positioned {
+			continue
+		}
+		margin := 0
+		if band != 1 {
+			_, _, v := evt.margins(style)
+			margin = int(v)
+		}
+		_, height := MeasureText(m, evt.Text, style)
+		events = append(events, placed{index: i, start: start, end: end, layer: evt.Layer, band: band, margin: margin, height: int(math.Ceil(height))})
+	}
+
+	// the renderer keeps the position of the lines already shown, which are
+	// the earlier ones, or the later ones in reverse mode
+	reverse := as.Collisions == CollisionsReverse
+	sort.SliceStable(events, func(i, j int) bool {
+		if reverse {
+			return events[i].start > events[j].start
+		}
+		return events[i].start < events[j].start
+	})
+
+	var collisions []Collision
+	for i := range events {
+		e := &events[i]
+		var others []*placed
+		for j := 0; j < i; j++ {
+			o := &events[j]
+			if o.layer == e.layer && o.band == e.band && o.start < e.end && e.start < o.end {
+				others = append(others, o)
+			}
+		}
+		sort.Slice(others, func(a, b int) bool { return others[a].margin+others[a].offset < others[b].margin+others[b].offset })
+		var in []int
+		for _, o := range others {
+			from, otherFrom := e.margin+e.offset, o.margin+o.offset
+			if from < otherFrom+o.height && otherFrom < from+e.height {
+				in = append(in, o.index)
+				e.offset = otherFrom + o.height - e.margin
+			}
+		}
+		if e.offset > 0 {
+			sort.Ints(in)
+			collisions = append(collisions, Collision{Index: e.index, Others: in, Offset: e.offset})
+		}
+	}
+	sort.Slice(collisions, func(i, j int) bool { return collisions[i].Index < collisions[j].Index })
+	return collisions
+}
+
+// collisionBand returns the vertical alignment of dialogue text, 0 for the
+// bottom, 1 for the middle and 2 for the top, align being the alignment of
+// its style, and whether it is positioned by \pos or \move
+func collisionBand(text string, align int) (int, bool) {
+	align, _, _, positioned := textLayout(text, align)
+	return (align - 1) / 3, positioned
+}
@@ -0,0 +1,95 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPredictCollisions(t *testing.T) {
+	events := []*Event{
+		{Start: "0:00:01.00", End: "0:00:05.00", Style: "Default", Text: "first"},
+		{Start: "0:00:02.00", End: "0:00:04.00", Style: "Default", Text: `second\Nline`},
+		{Start: "0:00:03.00", End: "0:00:06.00", Style: "Default", Text: "third"},
+		{Start: "0:00:02.00", End: "0:00:03.00", Style: "Default", Text: `{\pos(10,10)}sign`},
+		{Start: "0:00:02.00", End: "0:00:03.00", Style: "Default", Text: `{\an8}top`},
+		{Start: "0:00:02.00", End: "0:00:03.00", Layer: 1, Style: "Default", Text: "layer"},
+		{Start: "0:00:02.00", End: "0:00:03.00", Style: "Default", Text: "note", Comment: true},
+		{Start: "0:00:05.00", End: "0:00:07.00", Style: "Default", Text: "after"},
+	}
+	cases := []struct {
+		mode   Collisions
+		expect []Collision
+	}{
+		{"", []Collision{
+			{Index: 1, Others: []int{0}, Offset: 40},
+			{Index: 2, Others: []int{0, 1}, Offset: 120},
+		}},
+		{CollisionsReverse, []Collision{
+			{Index: 1, Others: []int{2}, Offset: 80},
+			{Index: 2, Others: []int{7}, Offset: 40},
+		}},
+	}
+
+	for _, c := range cases {
+		sub := &Subtitle{Collisions: c.mode, Styles: []*Style{{Name: "Default", FontSize: 40}}, Events: events}
+		if got := sub.PredictCollisions(); !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("Collisions %q: expect %+v, got: %+v", c.mode, c.expect, got)
+		}
+	}
+}
+
+func TestCollisionBand(t *testing.T) {
+	cases := []struct {
+		text       string
+		band       int
+		positioned bool
+	}{
+		{"plain", 0, false},
+		{`{\an8}top`, 2, false},
+		{`{\an5}middle`, 1, false},
+		{`{\a6}legacy top`, 2, false},
+		{`{\a10}legacy middle`, 1, false},
+		{`{\a3}legacy bottom`, 0, false},
+		{`{\an8\a4}invalid legacy`, 2, false},
+		{`{\alpha&H80&\an4}alpha`, 1, false},
+		{`{\an7\pos(10,20)}sign`, 2, true},
+	}
+	for _, c := range cases {
+		if band, positioned := collisionBand(c.text, 2); band != c.band || positioned != c.positioned {
+			t.Errorf("Expect %d %v for %s, got: %d %v", c.band, c.positioned, c.text, band, positioned)
+		}
+	}
+}
+
+func TestPredictCollisionsStyles(t *testing.T) {
+	styles := []*Style{{Name: "Default", FontSize: 40}, {Name: "Top", FontSize: 40, Alignment: 8}}
+	cases := []struct {
+		events []*Event
+		expect []Collision
+	}{
+		// a top sign doesn't push the dialogue
+		{[]*Event{
+			{Start: "0:00:01.00", End: "0:00:02.00", Style: "Top", Text: "sign"},
+			{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "dialogue"},
+		}, nil},
+		{[]*Event{
+			{Start: "0:00:01.00", End: "0:00:02.00", Style: "Top", Text: "sign"},
+			{Start: "0:00:01.00", End: "0:00:02.00", Style: "Top", Text: "other sign"},
+		}, []Collision{{Index: 1, Others: []int{0}, Offset: 40}}},
+		// the margins keep the lines apart
+		{[]*Event{
+			{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "dialogue"},
+			{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", MarginV: 100, Text: "higher"},
+		}, nil},
+		{[]*Event{
+			{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", MarginV: 20, Text: "dialogue"},
+			{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", MarginV: 40, Text: "higher"},
+		}, []Collision{{Index: 1, Others: []int{0}, Offset: 20}}},
+	}
+	for i, c := range cases {
+		sub := &Subtitle{Styles: styles, Events: c.events}
+		if got := sub.PredictCollisions(); !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("Case %d: expect %+v, got: %+v", i, c.expect, got)
+		}
+	}
+}
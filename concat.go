@@ -0,0 +1,48 @@
+package ass
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Concat joins subtitles of consecutive segments, e.g. per chapter speech
+// recognition output, into one. The events of each part are shifted by its
+// offset. The script info comes from the first part; styles are unified,
+// a style colliding with a different one of the same name is renamed with
+// the part number, e.g. Default_2. The parts are left untouched.
+func Concat(parts []*Subtitle, offsets []time.Duration) (*Subtitle, error) {
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("No subtitles to concatenate")
+	}
+	if len(parts) != len(offsets) {
+		return nil, fmt.Errorf("Expect %d offsets, got: %d", len(parts), len(offsets))
+	}
+	for _, part := range parts {
+		if part == nil {
+			return nil, fmt.Errorf("Subtitle cannot be nil")
+		}
+	}
+
+	joined := *parts[0]
+	joined.index = nil
+	joined.Styles = nil
+	joined.Events = nil
+	for i, part := range parts {
+		renames := joined.addStyles(part.Styles, "_"+strconv.Itoa(i+1))
+		for _, evt := range copyEvents(part.Events) {
+			if evt == nil {
+				return nil, fmt.Errorf("Event cannot be nil")
+			}
+			start, end, err := evt.times()
+			if err != nil {
+				return nil, err
+			}
+			evt.Start = (start + Timestamp(offsets[i])).String()
+			evt.End = (end + Timestamp(offsets[i])).String()
+			evt.renameStyles(renames)
+			joined.Events = append(joined.Events, evt)
+		}
+	}
+	return &joined, nil
+}
@@ -0,0 +1,55 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConcat(t *testing.T) {
+	parts := []*Subtitle{
+		{
+			Title:  "Part 1",
+			Styles: []*Style{{Name: "Default", FontName: "Arial"}},
+			Events: []*Event{{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "one"}},
+		},
+		{
+			Title:  "Part 2",
+			Styles: []*Style{{Name: "Default", FontName: "Arial"}},
+			Events: []*Event{{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "two"}},
+		},
+		{
+			Styles: []*Style{{Name: "Default", FontName: "Verdana"}},
+			Events: []*Event{{Start: "0:00:00.50", End: "0:00:01.00", Style: "Default", Text: "three"}},
+		},
+	}
+
+	joined, err := Concat(parts, []time.Duration{0, time.Minute, time.Hour})
+	if err != nil {
+		t.Fatalf("Expect concat success, got: %v", err)
+	}
+	if joined.Title != "Part 1" || len(joined.Styles) != 2 || joined.Styles[1].Name != "Default_3" {
+		t.Errorf("Unexpected joined subtitle: %+v", joined)
+	}
+
+	events := []Event{
+		{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "one"},
+		{Start: "0:01:01.00", End: "0:01:02.00", Style: "Default", Text: "two"},
+		{Start: "1:00:00.50", End: "1:00:01.00", Style: "Default_3", Text: "three"},
+	}
+	for i, evt := range events {
+		if !reflect.DeepEqual(*joined.Events[i], evt) {
+			t.Errorf("Expect %+v, got: %+v", evt, *joined.Events[i])
+		}
+	}
+	if parts[1].Events[0].Start != "0:00:01.00" {
+		t.Errorf("Expect the parts left untouched")
+	}
+
+	if _, err := Concat(parts, nil); err == nil {
+		t.Errorf("Expect offsets mismatch error, but passed")
+	}
+	if _, err := Concat([]*Subtitle{nil, parts[1]}, make([]time.Duration, 2)); err == nil {
+		t.Errorf("Expect nil subtitle error, but passed")
+	}
+}
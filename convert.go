@@ -0,0 +1,156 @@
+package ass
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// WritableFS is a file system files can be written to
+type WritableFS interface {
+	// WriteFile writes a file, creating its parent directories, name being
+	// a slash separated path as in fs.FS
+	WriteFile(name string, data []byte) error
+}
+
+// WritableDir returns a WritableFS writing to the files under dir
+func WritableDir(dir string) WritableFS {
+	return writableDir(dir)
+}
+
+type writableDir string
+
+func (dir writableDir) WriteFile(name string, data []byte) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+	p := filepath.Join(string(dir), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0644)
+}
+
+// ConvertOptions configures ConvertTree
+type ConvertOptions struct {
+	// Format is the output format: ass (default), srt or vtt. The files
+	// already in this format are skipped.
+	Format string
+	// Workers is the number of files converted concurrently, the number of
+	// CPUs by default
+	Workers int
+	// Progress, if set, is called after each file with its path, the error
+	// converting it, if any, and the number of files done so far out of
+	// total. Calls are serialized.
+	Progress func(name string, err error, done, total int)
+}
+
+// ConvertTree converts the ass, ssa and srt files of src to the format of
+// opts, written to dst at the same paths with the extension of the format.
+// All the files are converted even if some fail, the error of the first
+// failing one is returned.
+func ConvertTree(src fs.FS, dst WritableFS, opts ConvertOptions) error {
+	if opts.Format == "" {
+		opts.Format = "ass"
+	}
+	switch opts.Format {
+	case "ass", "srt", "vtt":
+	default:
+		return fmt.Errorf("Unsupported format: %s", opts.Format)
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+
+	var names []string
+	err := fs.WalkDir(src, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch ext := strings.ToLower(path.Ext(name)); ext {
+		case ".ass", ".ssa", ".srt":
+			if ext[1:] != opts.Format {
+				names = append(names, name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var (
+		mu       sync.Mutex
+		done     int
+		firstErr error
+		wg       sync.WaitGroup
+		queue    = make(chan string)
+	)
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range queue {
+				err := convertFile(src, dst, name, opts.Format)
+				if err != nil {
+					err = fmt.Errorf("%s: %v", name, err)
+				}
+				mu.Lock()
+				done++
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				if opts.Progress != nil {
+					opts.Progress(name, err, done, len(names))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, name := range names {
+		queue <- name
+	}
+	close(queue)
+	wg.Wait()
+	return firstErr
+}
+
+func convertFile(src fs.FS, dst WritableFS, name, format string) error {
+	f, err := src.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var sub *Subtitle
+	if strings.EqualFold(path.Ext(name), ".srt") {
+		sub, err = ParseSRT(f)
+	} else {
+		sub, err = Parse(f)
+	}
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "ass":
+		_, err = sub.WriteTo(&buf)
+	case "srt":
+		_, err = sub.WriteSRT(&buf)
+	case "vtt":
+		_, err = sub.WriteVTT(&buf)
+	}
+	if err != nil {
+		return err
+	}
+	return dst.WriteFile(strings.TrimSuffix(name, path.Ext(name))+"."+format, buf.Bytes())
+}
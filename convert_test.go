@@ -0,0 +1,57 @@
+package ass
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestConvertTree(t *testing.T) {
+	srt := "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	src := fstest.MapFS{
+		"a.srt":          {Data: []byte(srt)},
+		"season/b.SRT":   {Data: []byte(srt)},
+		"season/c.ass":   {Data: []byte("[Events]\nDialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,ass\n")},
+		"season/bad.srt": {Data: []byte("garbage\n")},
+		"notes.txt":      {Data: []byte("skip me")},
+	}
+	dir, err := ioutil.TempDir("", "ass-convert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var progress []string
+	err = ConvertTree(src, WritableDir(dir), ConvertOptions{Workers: 2, Progress: func(name string, err error, done, total int) {
+		progress = append(progress, fmt.Sprintf("%s %v %d", name, err != nil, total))
+	}})
+	if err == nil || !strings.Contains(err.Error(), "season/bad.srt") {
+		t.Errorf("Expect the error of bad.srt, got: %v", err)
+	}
+	sort.Strings(progress)
+	expect := []string{"a.srt false 3", "season/b.SRT false 3", "season/bad.srt true 3"}
+	if fmt.Sprint(progress) != fmt.Sprint(expect) {
+		t.Errorf("Expect progress %v, got: %v", expect, progress)
+	}
+	for _, name := range []string{"a.ass", "season/b.ass"} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil || !strings.Contains(string(data), "Hello") {
+			t.Errorf("Expect %s converted, got: %v", name, err)
+		}
+	}
+
+	if err := ConvertTree(src, WritableDir(dir), ConvertOptions{Format: "vtt"}); err == nil {
+		t.Errorf("Expect the error of bad.srt")
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(dir, "season", "c.vtt")); err != nil || !strings.HasPrefix(string(data), "WEBVTT") {
+		t.Errorf("Expect c.ass converted to WebVTT, got: %v", err)
+	}
+	if err := ConvertTree(src, WritableDir(dir), ConvertOptions{Format: "pdf"}); err == nil {
+		t.Errorf("Expect error for an unsupported format")
+	}
+}
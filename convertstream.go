@@ -0,0 +1,378 @@
+package ass
+
+import (
+	"bufio"
+	"container/heap"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// streamWindow is the number of events ConvertStream holds to put the cues
+// in order of start time
+const streamWindow = 64
+
+var (
+	vttTimeReg = regexp.MustCompile(`^\s*((?:\d+:)?\d\d:\d\d\.\d{3})\s+-->\s+((?:\d+:)?\d\d:\d\d\.\d{3})`)
+	vttTagReg  = regexp.MustCompile(`<(/?)([A-Za-z0-9]*)([^>]*)>`)
+)
+
+// ConvertStream converts a subtitle from srcFormat to dstFormat, ass, srt or
+// vtt, event by event, without reading the whole input first. The cues are
+// written in order of start time as WriteSRT and WriteVTT do, as long as
+// the input is not out of order by more than a few dozens of events. Only
+// the script info, styles and events of ass input are kept.
+func ConvertStream(dst io.Writer, dstFormat string, src io.Reader, srcFormat string) error {
+	var dec eventDecoder
+	var err error
+	switch srcFormat {
+	case "ass":
+		dec, err = newASSDecoder(src)
+	case "srt":
+		dec, err = newSRTDecoder(src)
+	case "vtt":
+		dec, err = newVTTDecoder(src)
+	default:
+		return fmt.Errorf("Unsupported format: %s", srcFormat)
+	}
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(dst)
+	var enc func(evt *Event) error
+	window := &eventWindow{}
+	switch dstFormat {
+	case "ass":
+		sw, err := NewStreamWriter(w, *dec.header())
+		if err != nil {
+			return err
+		}
+		enc = sw.WriteEvent
+		// the events are kept in their order
+		window = nil
+	case "srt":
+		n := 0
+		enc = func(evt *Event) error {
+			if n > 0 {
+				w.WriteByte('\n')
+			}
+			n++
+			start, end, _ := evt.times()
+			_, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s\n", n, srtFormat(start), srtFormat(end), markupText(evt.Text, false))
+			return err
+		}
+	case "vtt":
+		w.WriteString("WEBVTT\n")
+		if lang := dec.header().Language; lang != "" {
+			w.WriteString("Language: " + lang + "\n")
+		}
+		enc = func(evt *Event) error {
+			start, end, _ := evt.times()
+			_, err := w.Write(appendVTTCue(nil, start.Duration(), end.Duration(), evt.Text))
+			return err
+		}
+	default:
+		return fmt.Errorf("Unsupported format: %s", dstFormat)
+	}
+
+	for n := 1; ; n++ {
+		evt, err := dec.decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := evt.validate(); err != nil {
+			return fmt.Errorf("Event %d: %v", n, err)
+		}
+		if window == nil {
+			if err := enc(evt); err != nil {
+				return err
+			}
+			continue
+		}
+		if evt.Comment {
+			continue
+		}
+		if evt = window.add(evt); evt != nil {
+			if err := enc(evt); err != nil {
+				return err
+			}
+		}
+	}
+	if window == nil {
+		// the blank line ending the file, as WriteTo writes
+		w.WriteByte('\n')
+	} else {
+		for window.Len() > 0 {
+			if err := enc(heap.Pop(window).(windowEvent).evt); err != nil {
+				return err
+			}
+		}
+	}
+	return w.Flush()
+}
+
+// eventDecoder reads the events of a subtitle one by one
+type eventDecoder interface {
+	// header returns the subtitle without events
+	header() *Subtitle
+	// decode reads the next event, io.EOF at the end of the input
+	decode() (*Event, error)
+}
+
+// windowEvent is an event of an eventWindow, seq keeping the order of the
+// events starting at the same time
+type windowEvent struct {
+	evt   *Event
+	start Timestamp
+	seq   int
+}
+
+// eventWindow is a heap of the events not written yet, earliest first
+type eventWindow struct {
+	events []windowEvent
+	seq    int
+}
+
+// add adds evt and returns the earliest event once the window is full
+func (w *eventWindow) add(evt *Event) *Event {
+	start, _ := evt.StartTime()
+	heap.Push(w, windowEvent{evt, start, w.seq})
+	w.seq++
+	if w.Len() <= streamWindow {
+		return nil
+	}
+	return heap.Pop(w).(windowEvent).evt
+}
+
+func (w *eventWindow) Len() int { return len(w.events) }
+
+func (w *eventWindow) Less(i, j int) bool {
+	a, b := w.events[i], w.events[j]
+	return a.start < b.start || a.start == b.start && a.seq < b.seq
+}
+
+func (w *eventWindow) Swap(i, j int) { w.events[i], w.events[j] = w.events[j], w.events[i] }
+
+func (w *eventWindow) Push(x interface{}) { w.events = append(w.events, x.(windowEvent)) }
+
+func (w *eventWindow) Pop() interface{} {
+	last := w.events[len(w.events)-1]
+	w.events = w.events[:len(w.events)-1]
+	return last
+}
+
+// assDecoder reads the events of an ass subtitle one by one, the sections
+// not understood are skipped
+type assDecoder struct {
+	r     *bufio.Reader
+	p     *parser
+	hdr   *Subtitle
+	first *Event // the event read with the header
+}
+
+func newASSDecoder(r io.Reader) (*assDecoder, error) {
+	r, err := decompress(r, ".ass", ".ssa")
+	if err != nil {
+		return nil, err
+	}
+	decoded, charset, err := DetectEncoding(r)
+	if err != nil {
+		return nil, err
+	}
+	d := &assDecoder{r: bufio.NewReader(decoded), p: newParser(parseOptions{})}
+	d.p.sub.charset = charset
+	// the header is whatever comes before the first event
+	if d.first, err = d.next(); err != nil && err != io.EOF {
+		return nil, err
+	}
+	hdr := *d.p.sub
+	hdr.Events, hdr.RawSections, hdr.lines = nil, nil, nil
+	d.hdr = &hdr
+	return d, nil
+}
+
+func (d *assDecoder) header() *Subtitle {
+	return d.hdr
+}
+
+func (d *assDecoder) decode() (*Event, error) {
+	if evt := d.first; evt != nil {
+		d.first = nil
+		return evt, nil
+	}
+	return d.next()
+}
+
+// next parses lines up to the next event
+func (d *assDecoder) next() (*Event, error) {
+	p := d.p
+	for {
+		line, err := d.r.ReadString('\n')
+		if line != "" {
+			p.lineNo++
+			if err := p.parseLine(strings.TrimRight(line, "\r\n")); err != nil {
+				return nil, fmt.Errorf("Line %d: %v", p.lineNo, err)
+			}
+			if p.raw != nil {
+				p.raw.Lines = p.raw.Lines[:0]
+			}
+			if len(p.sub.Events) > 0 {
+				evt := p.sub.Events[0]
+				p.sub.Events = p.sub.Events[:0]
+				delete(p.sub.lines.events, evt)
+				return evt, nil
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// vttDecoder reads the cues of a WebVTT subtitle one by one
+type vttDecoder struct {
+	scanner *bufio.Scanner
+	lineNo  int
+	start   int // the line number of the last block
+	hdr     *Subtitle
+}
+
+func newVTTDecoder(r io.Reader) (*vttDecoder, error) {
+	r, err := decompress(r, ".vtt")
+	if err != nil {
+		return nil, err
+	}
+	decoded, charset, err := DetectEncoding(r)
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(decoded)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	d := &vttDecoder{scanner: scanner, hdr: newCueSubtitle()}
+	d.hdr.charset = charset
+
+	header, err := d.block()
+	if err != nil {
+		return nil, err
+	}
+	if len(header) == 0 || !isBlockStart(strings.TrimPrefix(header[0], utf8BOM), "WEBVTT") {
+		return nil, fmt.Errorf("Line 1: Invalid WebVTT header")
+	}
+	for _, line := range header[1:] {
+		if i := strings.IndexByte(line, ':'); i >= 0 && strings.EqualFold(strings.TrimSpace(line[:i]), "language") {
+			d.hdr.Language = strings.TrimSpace(line[i+1:])
+		}
+	}
+	return d, nil
+}
+
+// isBlockStart reports whether line starts with the keyword, followed by
+// a space or nothing
+func isBlockStart(line, keyword string) bool {
+	return strings.HasPrefix(line, keyword) &&
+		(len(line) == len(keyword) || line[len(keyword)] == ' ' || line[len(keyword)] == '\t')
+}
+
+func (d *vttDecoder) header() *Subtitle {
+	return d.hdr
+}
+
+// block reads the next lines up to a blank line, nil at the end
+func (d *vttDecoder) block() ([]string, error) {
+	var lines []string
+	for d.scanner.Scan() {
+		d.lineNo++
+		line := strings.TrimRight(d.scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			if len(lines) > 0 {
+				return lines, nil
+			}
+			continue
+		}
+		if len(lines) == 0 {
+			d.start = d.lineNo
+		}
+		lines = append(lines, line)
+	}
+	return lines, d.scanner.Err()
+}
+
+func (d *vttDecoder) decode() (*Event, error) {
+	for {
+		block, err := d.block()
+		if err != nil {
+			return nil, err
+		}
+		if len(block) == 0 {
+			return nil, io.EOF
+		}
+		if isBlockStart(block[0], "NOTE") || isBlockStart(block[0], "STYLE") || isBlockStart(block[0], "REGION") {
+			continue
+		}
+		// the timing line may follow the identifier of the cue
+		timing := 0
+		if !strings.Contains(block[0], "-->") && len(block) > 1 {
+			timing = 1
+		}
+		m := vttTimeReg.FindStringSubmatch(block[timing])
+		if m == nil {
+			return nil, fmt.Errorf("Line %d: Invalid cue: %s", d.start+timing, block[timing])
+		}
+		text, name := vttText(strings.Join(block[timing+1:], "\n"))
+		return &Event{Style: "Default", Name: name, Start: vttParseTimestamp(m[1]).String(), End: vttParseTimestamp(m[2]).String(), Text: text}, nil
+	}
+}
+
+// vttParseTimestamp parses a timestamp matched by vttTimeReg, [hh:]mm:ss.ttt
+func vttParseTimestamp(s string) Timestamp {
+	fields := strings.Split(s, ":")
+	var d time.Duration
+	for _, f := range fields[:len(fields)-1] {
+		n, _ := strconv.Atoi(f)
+		d = d*60 + time.Duration(n)
+	}
+	sec := fields[len(fields)-1]
+	s1, _ := strconv.Atoi(sec[:2])
+	ms, _ := strconv.Atoi(sec[3:])
+	return Timestamp(d*time.Minute + time.Duration(s1)*time.Second + time.Duration(ms)*time.Millisecond)
+}
+
+// vttText converts the text of a cue to dialogue text, with <i>, <b> and
+// <u> converted to override tags, and returns the speaker of the first
+// voice tag
+func vttText(text string) (string, string) {
+	var b strings.Builder
+	var name string
+	plain := func(s string) {
+		b.WriteString(strings.Replace(EscapeText(html.UnescapeString(s)), "\u00a0", `\h`, -1))
+	}
+	last := 0
+	for _, m := range vttTagReg.FindAllStringSubmatchIndex(text, -1) {
+		plain(text[last:m[0]])
+		last = m[1]
+		closing := m[3] > m[2]
+		switch tag := strings.ToLower(text[m[4]:m[5]]); tag {
+		case "i", "b", "u":
+			if closing {
+				b.WriteString(`{\` + tag + `0}`)
+			} else {
+				b.WriteString(`{\` + tag + `1}`)
+			}
+		case "v":
+			annotation := text[m[6]:m[7]]
+			if i := strings.IndexAny(annotation, " \t"); i >= 0 && !closing && name == "" {
+				name = strings.TrimSpace(strings.Replace(annotation[i+1:], ",", " ", -1))
+			}
+		}
+	}
+	plain(text[last:])
+	return b.String(), name
+}
@@ -0,0 +1,93 @@
+package ass
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConvertStream(t *testing.T) {
+	sub := newBenchSubtitle(300)
+	// out of order by a few events
+	for i := 0; i+5 < len(sub.Events); i += 10 {
+		sub.Events[i], sub.Events[i+5] = sub.Events[i+5], sub.Events[i]
+	}
+	var ass, srt, vtt bytes.Buffer
+	sub.WriteTo(&ass)
+	sub.WriteSRT(&srt)
+	sub.WriteVTT(&vtt)
+	fromSRT, _ := ParseSRT(bytes.NewReader(srt.Bytes()))
+	var srtToASS, srtToVTT bytes.Buffer
+	fromSRT.WriteTo(&srtToASS)
+	fromSRT.WriteVTT(&srtToVTT)
+
+	cases := []struct {
+		src, srcFormat, dstFormat string
+		expect                    string
+	}{
+		{ass.String(), "ass", "ass", ass.String()},
+		{ass.String(), "ass", "srt", srt.String()},
+		{ass.String(), "ass", "vtt", vtt.String()},
+		{srt.String(), "srt", "ass", srtToASS.String()},
+		{srt.String(), "srt", "vtt", srtToVTT.String()},
+		{vtt.String(), "vtt", "srt", srt.String()},
+	}
+	for _, c := range cases {
+		var out bytes.Buffer
+		if err := ConvertStream(&out, c.dstFormat, strings.NewReader(c.src), c.srcFormat); err != nil {
+			t.Errorf("Expect %s to %s success, got: %v", c.srcFormat, c.dstFormat, err)
+			continue
+		}
+		if out.String() != c.expect {
+			t.Errorf("Expect %s to %s the same as converting the whole subtitle", c.srcFormat, c.dstFormat)
+		}
+	}
+}
+
+func TestConvertStreamVTT(t *testing.T) {
+	vtt := "WEBVTT - title\nLanguage: fr\n\nNOTE a comment\n\nSTYLE\n::cue { color: red }\n\n" +
+		"intro\n00:01.000 --> 00:02.500 align:start\n<v.loud Jean>Bonjour</v> <i>à</i> {tous} &amp; &lt;3\n\n" +
+		"01:00:00.000 --> 01:00:01.000\n<c.yellow>ligne</c>&nbsp;un\nligne deux\n"
+	var out bytes.Buffer
+	if err := ConvertStream(&out, "ass", strings.NewReader(vtt), "vtt"); err != nil {
+		t.Fatal(err)
+	}
+	sub, err := Parse(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Language != "fr" || len(sub.Events) != 2 {
+		t.Fatalf("Expect 2 events in fr, got: %s, %v", sub.Language, sub.Events)
+	}
+	expect := []Event{
+		{Start: "0:00:01.00", End: "0:00:02.50", Style: "Default", Name: "Jean", Text: `Bonjour {\i1}à{\i0} \{tous\} & <3`},
+		{Start: "1:00:00.00", End: "1:00:01.00", Style: "Default", Text: `ligne\hun\Nligne deux`},
+	}
+	for i, evt := range sub.Events {
+		if !reflect.DeepEqual(*evt, expect[i]) {
+			t.Errorf("Expect %v, got: %v", expect[i], *evt)
+		}
+	}
+}
+
+func TestConvertStreamErrors(t *testing.T) {
+	cases := []struct {
+		src, srcFormat, dstFormat string
+		expect                    string
+	}{
+		{"", "txt", "ass", "Unsupported format: txt"},
+		{"WEBVTT\n", "vtt", "sub", "Unsupported format: sub"},
+		{"1\n00:00:01,000 --> 00:00:02,000\na\n", "srt", "sub", "Unsupported format: sub"},
+		{"WEBVT\n", "vtt", "srt", "Line 1: Invalid WebVTT header"},
+		{"WEBVTT\n\n1\n2\n00:01.000 --> 00:02.000\n", "vtt", "srt", "Line 4: Invalid cue: 2"},
+		{"[Events]\nDialogue: 0,0:00:02.00,0:00:01.00,Default,,0,0,0,,a\n", "ass", "srt",
+			"Event 1: End time 0:00:01.00 is not after start time 0:00:02.00"},
+	}
+	for _, c := range cases {
+		var out bytes.Buffer
+		if err := ConvertStream(&out, c.dstFormat, strings.NewReader(c.src), c.srcFormat); err == nil || err.Error() != c.expect {
+			t.Errorf("Expect error %q, got: %v", c.expect, err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package ass
+
+import "time"
+
+// Dedupe cleans up repeated captions, as left by OCR or the conversion of
+// roll-up captions. Exact duplicates of an earlier event are removed, then
+// each event following one with the same text, style, actor and layer is
+// merged into it when it starts at most maxGap after the previous one ends.
+// It returns the number of events removed.
+func (as *Subtitle) Dedupe(maxGap time.Duration) int {
+	seen := make(map[eventKey]bool, len(as.Events))
+	events := make([]*Event, 0, len(as.Events))
+	var last *Event
+	for _, evt := range as.Events {
+		if evt == nil {
+			continue
+		}
+		key := evt.key()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if last != nil {
+			if end, ok := consecutive(last, evt, maxGap); ok {
+				last.End = end.String()
+				continue
+			}
+		}
+		events = append(events, evt)
+		last = evt
+	}
+	removed := len(as.Events) - len(events)
+	as.Events = events
+	return removed
+}
+
+// consecutive reports whether evt repeats prev within maxGap, returning the
+// end of both
+func consecutive(prev, evt *Event, maxGap time.Duration) (Timestamp, bool) {
+	if evt.Text != prev.Text || evt.Style != prev.Style || evt.Name != prev.Name || evt.Layer != prev.Layer {
+		return 0, false
+	}
+	start, end, err := prev.times()
+	if err != nil {
+		return 0, false
+	}
+	nextStart, nextEnd, err := evt.times()
+	if err != nil || nextStart < start || time.Duration(nextStart-end) > maxGap {
+		return 0, false
+	}
+	return maxTimestamp(end, nextEnd), true
+}
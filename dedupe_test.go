@@ -0,0 +1,32 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDedupe(t *testing.T) {
+	sub := &Subtitle{Events: []*Event{
+		{Start: "0:00:01.00", End: "0:00:02.00", Text: "roll"},
+		{Start: "0:00:02.00", End: "0:00:03.00", Text: "roll"},
+		{Start: "0:00:03.05", End: "0:00:04.00", Text: "roll"},
+		{Start: "0:00:04.00", End: "0:00:05.00", Text: "next"},
+		{Start: "0:00:04.00", End: "0:00:05.00", Text: "next"},
+		{Start: "0:00:06.00", End: "0:00:07.00", Text: "next"},
+		{Start: "0:00:07.00", End: "0:00:08.00", Style: "Sign", Text: "next"},
+	}}
+	removed := sub.Dedupe(100 * time.Millisecond)
+	if removed != 3 {
+		t.Errorf("Expect 3 events removed, got: %d", removed)
+	}
+	expect := []*Event{
+		{Start: "0:00:01.00", End: "0:00:04.00", Text: "roll"},
+		{Start: "0:00:04.00", End: "0:00:05.00", Text: "next"},
+		{Start: "0:00:06.00", End: "0:00:07.00", Text: "next"},
+		{Start: "0:00:07.00", End: "0:00:08.00", Style: "Sign", Text: "next"},
+	}
+	if !reflect.DeepEqual(sub.Events, expect) {
+		t.Errorf("Expect %v, got: %v", expect, sub.Events)
+	}
+}
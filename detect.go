@@ -0,0 +1,121 @@
+package ass
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// detectSize is how many bytes are inspected to detect the charset
+const detectSize = 64 * 1024
+
+// charsetCandidates are the legacy charsets tried when the input is not
+// UTF-8, with some of the most frequent characters of their language
+var charsetCandidates = []struct {
+	name   string
+	common string
+}{
+	{"shift_jis", "のにはをたがでてとしれさいるかなもすあっこだりまうよねじゃ。、「」"},
+	{"gbk", "的一是不了人我在有他这中大来上个们到说国和地也子时道出而要于就下得可你么没好吗，。"},
+	{"euc-kr", "이다는의에가고하지을를서한으로도기사리나어수있그것들정요니야해"},
+}
+
+// DetectEncoding detects the charset of the input and returns a reader
+// converting it to UTF-8, with the byte order mark removed, and the name of
+// the detected charset: utf-8, utf-16le, utf-16be, shift_jis, gbk or euc-kr.
+// Legacy charsets are guessed from the most frequent characters of their
+// languages, which works well enough for dialogue text.
+func DetectEncoding(r io.Reader) (io.Reader, string, error) {
+	br := bufio.NewReaderSize(r, detectSize)
+	sample, err := br.Peek(detectSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, "", err
+	}
+	partial := len(sample) == detectSize
+
+	var charset string
+	switch {
+	case bytes.HasPrefix(sample, []byte(utf8BOM)):
+		br.Discard(len(utf8BOM))
+		return br, "utf-8", nil
+	case bytes.HasPrefix(sample, []byte{0xFF, 0xFE}):
+		br.Discard(2)
+		charset = "utf-16le"
+	case bytes.HasPrefix(sample, []byte{0xFE, 0xFF}):
+		br.Discard(2)
+		charset = "utf-16be"
+	default:
+		charset = sniffCharset(sample, partial)
+	}
+
+	if charset == "utf-8" {
+		return br, charset, nil
+	}
+	decoded, err := NewDecodingReader(br, charset)
+	return decoded, charset, err
+}
+
+// sniffCharset guesses the charset of a sample without byte order mark
+func sniffCharset(sample []byte, partial bool) string {
+	// text is mostly ASCII, so UTF-16 has a zero byte in most code units
+	var evenZeros, oddZeros int
+	for i, c := range sample {
+		if c != 0 {
+			continue
+		}
+		if i%2 == 0 {
+			evenZeros++
+		} else {
+			oddZeros++
+		}
+	}
+	if oddZeros > len(sample)/4 && oddZeros > evenZeros*4 {
+		return "utf-16le"
+	}
+	if evenZeros > len(sample)/4 && evenZeros > oddZeros*4 {
+		return "utf-16be"
+	}
+
+	valid := sample
+	if partial {
+		// the sample may end in the middle of a character
+		for i := 0; i < utf8.UTFMax && len(valid) > 0 && !utf8.Valid(valid); i++ {
+			valid = valid[:len(valid)-1]
+		}
+	}
+	if utf8.Valid(valid) {
+		return "utf-8"
+	}
+
+	best, bestScore := "", -1
+	for _, candidate := range charsetCandidates {
+		r, err := NewDecodingReader(bytes.NewReader(sample), candidate.name)
+		if err != nil {
+			continue
+		}
+		var buf bytes.Buffer
+		buf.ReadFrom(r)
+		score, invalid := 0, 0
+		for _, c := range buf.String() {
+			switch {
+			case c == utf8.RuneError:
+				invalid++
+			case strings.ContainsRune(candidate.common, c):
+				score++
+			}
+		}
+		if invalid > 1 || (invalid == 1 && !partial) {
+			continue
+		}
+		if score > bestScore {
+			best, bestScore = candidate.name, score
+		}
+	}
+	if best == "" {
+		// not decodable at all, let the caller see the raw bytes
+		return "utf-8"
+	}
+	return best
+}
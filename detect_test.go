@@ -0,0 +1,94 @@
+package ass
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	encode := func(charset, s string) []byte {
+		enc, _ := htmlindex.Get(charset)
+		b, err := enc.NewEncoder().Bytes([]byte(s))
+		if err != nil {
+			t.Fatalf("Can't encode %q in %s: %v", s, charset, err)
+		}
+		return b
+	}
+
+	cases := []struct {
+		input   []byte
+		charset string
+		expect  string
+	}{
+		{[]byte("Dialogue: hello"), "utf-8", "Dialogue: hello"},
+		{[]byte(utf8BOM + "Dialogue: 日本"), "utf-8", "Dialogue: 日本"},
+		{append([]byte{0xFF, 0xFE}, encode("utf-16le", "Dialogue: 日本")...), "utf-16le", "Dialogue: 日本"},
+		{append([]byte{0xFE, 0xFF}, encode("utf-16be", "Dialogue: 日本")...), "utf-16be", "Dialogue: 日本"},
+		{encode("utf-16le", "Dialogue: hello"), "utf-16le", "Dialogue: hello"},
+		{encode("shift_jis", "Dialogue: これは日本語の字幕です。"), "shift_jis", "Dialogue: これは日本語の字幕です。"},
+		{encode("gbk", "Dialogue: 你好，我们在这里说中文的字幕。"), "gbk", "Dialogue: 你好，我们在这里说中文的字幕。"},
+		{encode("euc-kr", "Dialogue: 이것은 한국어 자막입니다 그리고 좋아요"), "euc-kr", "Dialogue: 이것은 한국어 자막입니다 그리고 좋아요"},
+	}
+
+	for _, c := range cases {
+		r, charset, err := DetectEncoding(bytes.NewReader(c.input))
+		if err != nil {
+			t.Errorf("Expect detect success, got: %v", err)
+			continue
+		}
+		if charset != c.charset {
+			t.Errorf("Expect charset %s, got: %s", c.charset, charset)
+			continue
+		}
+		got, _ := ioutil.ReadAll(r)
+		if string(got) != c.expect {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+	}
+}
+
+func TestParseCharset(t *testing.T) {
+	utf16 := func(s string) []byte {
+		enc, _ := htmlindex.Get("utf-16le")
+		b, _ := enc.NewEncoder().Bytes([]byte(s))
+		return append([]byte{0xFF, 0xFE}, b...)
+	}
+	srt := "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+
+	cases := []struct {
+		parse  func() (*Subtitle, error)
+		expect string
+	}{
+		{func() (*Subtitle, error) { return Parse(bytes.NewReader([]byte(sampleScript))) }, "utf-8"},
+		{func() (*Subtitle, error) { return Parse(bytes.NewReader(utf16(sampleScript))) }, "utf-16le"},
+		{func() (*Subtitle, error) { return ParseBytes([]byte(sampleScript)) }, "utf-8"},
+		{func() (*Subtitle, error) { return ParseBytes(utf16(sampleScript)) }, "utf-16le"},
+		{func() (*Subtitle, error) { return ParseSRT(bytes.NewReader(utf16(srt))) }, "utf-16le"},
+		{func() (*Subtitle, error) {
+			ls, err := ParseLazy(bytes.NewReader(utf16(sampleScript)))
+			if err != nil {
+				return nil, err
+			}
+			return ls.Subtitle()
+		}, "utf-16le"},
+	}
+	for i, c := range cases {
+		sub, err := c.parse()
+		if err != nil {
+			t.Errorf("Expect parse %d success, got: %v", i, err)
+			continue
+		}
+		if sub.Charset() != c.expect {
+			t.Errorf("Expect charset %s for parse %d, got: %s", c.expect, i, sub.Charset())
+		}
+		if sub.Clone().Charset() != c.expect {
+			t.Errorf("Expect the charset cloned for parse %d", i)
+		}
+	}
+	if charset := (&Subtitle{}).Charset(); charset != "" {
+		t.Errorf("Expect no charset if not parsed, got: %s", charset)
+	}
+}
@@ -0,0 +1,152 @@
+package ass
+
+import (
+	"fmt"
+	"sort"
+)
+
+// speakerKey is the key of the speaker ID in the extra data of an event
+const speakerKey = "speaker"
+
+// SpeakerSegment is a span of speech of a speaker, from diarization
+type SpeakerSegment struct {
+	Speaker string    `json:"speaker"`
+	Start   Timestamp `json:"start"`
+	End     Timestamp `json:"end"`
+}
+
+// SpeakerMapping is how the events of a speaker are changed by
+// ApplySpeakers, which may be edited before
+type SpeakerMapping struct {
+	Speaker string `json:"speaker"`
+	// Actor is the name given to the events, the speaker ID by default
+	Actor string `json:"actor"`
+	// Style replaces the style of the events, if not empty
+	Style string `json:"style"`
+	// Color is an ABGR color the text is painted with, if not empty
+	Color string `json:"color"`
+	// Events are the indexes of the events of the speaker
+	Events []int `json:"events"`
+}
+
+// SpeakerReport maps the events of a subtitle to speakers, see MapSpeakers
+type SpeakerReport struct {
+	// Speakers are in order of first speech
+	Speakers []SpeakerMapping `json:"speakers"`
+	// Ambiguous are the indexes of the events overlapping several
+	// speakers, given to the one speaking the longest during the event
+	Ambiguous []int `json:"ambiguous"`
+	// Unassigned are the indexes of the events overlapping no segment, or
+	// with invalid timestamps
+	Unassigned []int `json:"unassigned"`
+}
+
+// MapSpeakers assigns each event to the speaker of the diarized segments
+// it overlaps the most, comments left out, and gives each speaker a color
+// from the palette, DefaultActorPalette if empty. The subtitle is not
+// changed: the report can be reviewed and edited, then applied with
+// ApplySpeakers.
+func (as *Subtitle) MapSpeakers(segments []SpeakerSegment, palette []string) (*SpeakerReport, error) {
+	if len(palette) == 0 {
+		palette = DefaultActorPalette
+	}
+	sorted := make([]SpeakerSegment, len(segments))
+	copy(sorted, segments)
+	for i, seg := range sorted {
+		if seg.End < seg.Start {
+			return nil, fmt.Errorf("Segment %d ends before it starts", i)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	report := &SpeakerReport{}
+	bySpeaker := make(map[string]int)
+	// maxEnd[i] is the latest end of the segments up to i, to skip those
+	// ending before an event
+	maxEnd := make([]Timestamp, len(sorted))
+	for i, seg := range sorted {
+		if _, ok := bySpeaker[seg.Speaker]; !ok {
+			bySpeaker[seg.Speaker] = len(report.Speakers)
+			report.Speakers = append(report.Speakers, SpeakerMapping{
+				Speaker: seg.Speaker,
+				Actor:   seg.Speaker,
+				Color:   palette[len(report.Speakers)%len(palette)],
+			})
+		}
+		maxEnd[i] = seg.End
+		if i > 0 && maxEnd[i-1] > seg.End {
+			maxEnd[i] = maxEnd[i-1]
+		}
+	}
+
+	for i, evt := range as.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		start, end, err := evt.times()
+		if err != nil {
+			report.Unassigned = append(report.Unassigned, i)
+			continue
+		}
+		overlaps := make(map[int]Timestamp)
+		best := -1
+		first := sort.Search(len(sorted), func(j int) bool { return maxEnd[j] > start })
+		for _, seg := range sorted[first:] {
+			if seg.Start >= end {
+				break
+			}
+			overlap := minTimestamp(end, seg.End) - maxTimestamp(start, seg.Start)
+			if overlap <= 0 {
+				continue
+			}
+			speaker := bySpeaker[seg.Speaker]
+			overlaps[speaker] += overlap
+			if best < 0 || overlaps[speaker] > overlaps[best] {
+				best = speaker
+			}
+		}
+		switch {
+		case best < 0:
+			report.Unassigned = append(report.Unassigned, i)
+			continue
+		case len(overlaps) > 1:
+			report.Ambiguous = append(report.Ambiguous, i)
+		}
+		report.Speakers[best].Events = append(report.Speakers[best].Events, i)
+	}
+	return report, nil
+}
+
+// ApplySpeakers changes the events of each speaker of the report: their
+// actor, style and color, and the speaker ID kept in their extra data under
+// "speaker". It returns the number of changed events, nothing is changed
+// if a color or an event index is invalid.
+func (as *Subtitle) ApplySpeakers(report *SpeakerReport) (int, error) {
+	for _, m := range report.Speakers {
+		if m.Color != "" && !isValidABGR(m.Color) {
+			return 0, fmt.Errorf("Invalid color for speaker %s: %s", m.Speaker, m.Color)
+		}
+		for _, i := range m.Events {
+			if i < 0 || i >= len(as.Events) || as.Events[i] == nil {
+				return 0, fmt.Errorf("Invalid event for speaker %s: %d", m.Speaker, i)
+			}
+		}
+	}
+
+	changed := 0
+	for _, m := range report.Speakers {
+		for _, i := range m.Events {
+			evt := as.Events[i]
+			evt.Name = m.Actor
+			if m.Style != "" {
+				evt.Style = m.Style
+			}
+			if m.Color != "" {
+				evt.Text = prependTags(evt.Text, colorTag(m.Color))
+			}
+			evt.SetExtra(speakerKey, m.Speaker)
+			changed++
+		}
+	}
+	return changed, nil
+}
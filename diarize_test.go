@@ -0,0 +1,74 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMapSpeakers(t *testing.T) {
+	sec := func(s float64) Timestamp { return Timestamp(s * float64(time.Second)) }
+	sub := &Subtitle{Events: []*Event{
+		{Start: "0:00:00.00", End: "0:00:02.00", Style: "Default", Text: "Hi"},
+		{Start: "0:00:02.00", End: "0:00:05.00", Style: "Default", Text: "Hello"},
+		{Start: "0:00:05.00", End: "0:00:06.00", Style: "Default", Text: "note", Comment: true},
+		{Start: "0:00:10.00", End: "0:00:11.00", Style: "Default", Text: "silence"},
+		nil,
+		{Start: "bad", End: "0:00:12.00", Style: "Default", Text: "bad"},
+		{Start: "0:00:12.00", End: "0:00:13.00", Style: "Default", Text: `{\i1}Bye`},
+	}}
+	segments := []SpeakerSegment{
+		{Speaker: "SPEAKER_01", Start: sec(1.5), End: sec(4.5)},
+		{Speaker: "SPEAKER_00", Start: sec(4.5), End: sec(20)},
+		{Speaker: "SPEAKER_00", Start: sec(0), End: sec(2.2)},
+		{Speaker: "SPEAKER_02", Start: sec(30), End: sec(31)},
+	}
+	report, err := sub.MapSpeakers(segments, []string{"0000FFFF", "00FFFF00"})
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	expect := &SpeakerReport{
+		Speakers: []SpeakerMapping{
+			{Speaker: "SPEAKER_00", Actor: "SPEAKER_00", Color: "0000FFFF", Events: []int{0, 3, 6}},
+			{Speaker: "SPEAKER_01", Actor: "SPEAKER_01", Color: "00FFFF00", Events: []int{1}},
+			{Speaker: "SPEAKER_02", Actor: "SPEAKER_02", Color: "0000FFFF"},
+		},
+		Ambiguous:  []int{0, 1},
+		Unassigned: []int{5},
+	}
+	if !reflect.DeepEqual(report, expect) {
+		t.Fatalf("Expect %+v, got: %+v", expect, report)
+	}
+
+	// manual override before applying
+	report.Speakers[0].Actor = "Ann"
+	report.Speakers[1].Style = "Bob"
+	report.Speakers[1].Color = ""
+	n, err := sub.ApplySpeakers(report)
+	if err != nil || n != 4 {
+		t.Fatalf("Expect 4 events changed, got: %d, %v", n, err)
+	}
+	cases := []struct {
+		index             int
+		name, style, text string
+		speaker           string
+	}{
+		{0, "Ann", "Default", `{\c&H00FFFF&}Hi`, "SPEAKER_00"},
+		{1, "SPEAKER_01", "Bob", "Hello", "SPEAKER_01"},
+		{2, "", "Default", "note", ""},
+		{6, "Ann", "Default", `{\c&H00FFFF&\i1}Bye`, "SPEAKER_00"},
+	}
+	for _, c := range cases {
+		evt := sub.Events[c.index]
+		if evt.Name != c.name || evt.Style != c.style || evt.Text != c.text || evt.Extra["speaker"] != c.speaker {
+			t.Errorf("Event %d: expect %s, %s, %s and %s, got: %+v", c.index, c.name, c.style, c.text, c.speaker, *evt)
+		}
+	}
+
+	if _, err := sub.ApplySpeakers(&SpeakerReport{Speakers: []SpeakerMapping{{Speaker: "x", Events: []int{4}}}}); err == nil {
+		t.Errorf("Expect an error for a nil event")
+	}
+	if _, err := sub.MapSpeakers([]SpeakerSegment{{Start: sec(2), End: sec(1)}}, nil); err == nil {
+		t.Errorf("Expect an error for a segment ending before it starts")
+	}
+}
@@ -0,0 +1,247 @@
+package ass
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ChangeKind is the kind of a change between two subtitles
+type ChangeKind int
+
+// The change kinds
+const (
+	ChangeAdded ChangeKind = iota
+	ChangeRemoved
+	ChangeModified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	}
+	return fmt.Sprintf("change(%d)", int(k))
+}
+
+// FieldChange is the change of a single field
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Change is a difference between two subtitles
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Section is Styles or Events, empty for the script info
+	Section string `json:"section,omitempty"`
+	// OldIndex and NewIndex are the indexes of the item in the old and new
+	// subtitle, -1 when it doesn't exist there
+	OldIndex int `json:"oldIndex"`
+	NewIndex int `json:"newIndex"`
+	// Fields are the changed fields of a modified item
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// diffLimit is the size of the edit table above which unmatched events are
+// paired in order instead of searching the longest common subsequence
+const diffLimit = 4 << 20
+
+// Diff reports what changed from a to b: script info fields, styles matched
+// by name, and events aligned on the longest run of identical events, the
+// remaining events between them being paired, on the same start time or
+// text first, as modifications.
+func Diff(a, b *Subtitle) []Change {
+	var changes []Change
+	if fields := diffFields(a, b); len(fields) > 0 {
+		changes = append(changes, Change{Kind: ChangeModified, OldIndex: -1, NewIndex: -1, Fields: fields})
+	}
+
+	// styles, by name
+	newStyles := make(map[string]int)
+	for j, style := range b.Styles {
+		if style != nil {
+			newStyles[style.Name] = j
+		}
+	}
+	matched := make(map[int]bool)
+	for i, style := range a.Styles {
+		if style == nil {
+			continue
+		}
+		j, ok := newStyles[style.Name]
+		if !ok {
+			changes = append(changes, Change{Kind: ChangeRemoved, Section: "Styles", OldIndex: i, NewIndex: -1})
+			continue
+		}
+		matched[j] = true
+		if fields := diffFields(style, b.Styles[j]); len(fields) > 0 {
+			changes = append(changes, Change{Kind: ChangeModified, Section: "Styles", OldIndex: i, NewIndex: j, Fields: fields})
+		}
+	}
+	for j, style := range b.Styles {
+		if style != nil && !matched[j] {
+			changes = append(changes, Change{Kind: ChangeAdded, Section: "Styles", OldIndex: -1, NewIndex: j})
+		}
+	}
+
+	return append(changes, diffEvents(a.Events, b.Events)...)
+}
+
+func diffEvents(a, b []*Event) []Change {
+	var changes []Change
+	for _, p := range alignEvents(a, b) {
+		switch {
+		case p.old < 0:
+			changes = append(changes, Change{Kind: ChangeAdded, Section: "Events", OldIndex: -1, NewIndex: p.new})
+		case p.new < 0:
+			changes = append(changes, Change{Kind: ChangeRemoved, Section: "Events", OldIndex: p.old, NewIndex: -1})
+		case !sameEvent(a[p.old], b[p.new]):
+			changes = append(changes, Change{Kind: ChangeModified, Section: "Events", OldIndex: p.old, NewIndex: p.new,
+				Fields: diffFields(a[p.old], b[p.new])})
+		}
+	}
+	return changes
+}
+
+func sameEvent(a, b *Event) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.key() == b.key()
+}
+
+// eventPair pairs an event of the old list with one of the new list,
+// -1 means added or removed
+type eventPair struct {
+	old, new int
+}
+
+// alignEvents aligns two lists of events on the longest run of identical
+// events. The events between them are aligned on the events with the same
+// start time or text, the remaining ones are paired in order. The pairs are
+// in the order of both lists.
+func alignEvents(a, b []*Event) []eventPair {
+	var pairs []eventPair
+	// events paired in order
+	inOrder := func(i0, i1, j0, j1 int) {
+		for ; i0 < i1 && j0 < j1; i0, j0 = i0+1, j0+1 {
+			pairs = append(pairs, eventPair{i0, j0})
+		}
+		for ; i0 < i1; i0++ {
+			pairs = append(pairs, eventPair{i0, -1})
+		}
+		for ; j0 < j1; j0++ {
+			pairs = append(pairs, eventPair{-1, j0})
+		}
+	}
+	similar := func(i, j int) bool {
+		if a[i] == nil || b[j] == nil {
+			return false
+		}
+		return a[i].Start == b[j].Start || a[i].Text == b[j].Text
+	}
+	// events between two identical ones
+	gap := func(i0, i1, j0, j1 int) {
+		for _, m := range commonSubsequence(i0, i1, j0, j1, similar) {
+			inOrder(i0, m.old, j0, m.new)
+			pairs = append(pairs, m)
+			i0, j0 = m.old+1, m.new+1
+		}
+		inOrder(i0, i1, j0, j1)
+	}
+	equal := func(i, j int) bool {
+		return sameEvent(a[i], b[j])
+	}
+
+	// common prefix and suffix
+	lo := 0
+	for lo < len(a) && lo < len(b) && equal(lo, lo) {
+		pairs = append(pairs, eventPair{lo, lo})
+		lo++
+	}
+	hiA, hiB := len(a), len(b)
+	for hiA > lo && hiB > lo && equal(hiA-1, hiB-1) {
+		hiA--
+		hiB--
+	}
+	i, j := lo, lo
+	for _, m := range commonSubsequence(lo, hiA, lo, hiB, equal) {
+		gap(i, m.old, j, m.new)
+		pairs = append(pairs, m)
+		i, j = m.old+1, m.new+1
+	}
+	gap(i, hiA, j, hiB)
+
+	for k := 0; hiA+k < len(a); k++ {
+		pairs = append(pairs, eventPair{hiA + k, hiB + k})
+	}
+	return pairs
+}
+
+// commonSubsequence returns the pairs of the longest common subsequence of
+// the ranges [i0, i1) and [j0, j1), nothing when the ranges are too large
+func commonSubsequence(i0, i1, j0, j1 int, equal func(i, j int) bool) []eventPair {
+	n, m := i1-i0, j1-j0
+	if n <= 0 || m <= 0 || n*m > diffLimit {
+		return nil
+	}
+	lengths := make([][]int32, n+1)
+	for i := range lengths {
+		lengths[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case equal(i0+i, j0+j):
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] >= lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+	var pairs []eventPair
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case equal(i0+i, j0+j):
+			pairs = append(pairs, eventPair{i0 + i, j0 + j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
+
+// diffFields compares the exported fields of two structs of the same type,
+// slices of pointers such as the styles and events are skipped
+func diffFields(a, b interface{}) []FieldChange {
+	va, vb := reflect.Indirect(reflect.ValueOf(a)), reflect.Indirect(reflect.ValueOf(b))
+	if !va.IsValid() || !vb.IsValid() {
+		return nil
+	}
+	var fields []FieldChange
+	for i := 0; i < va.NumField(); i++ {
+		field := va.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Ptr {
+			continue
+		}
+		old, cur := fmt.Sprint(va.Field(i).Interface()), fmt.Sprint(vb.Field(i).Interface())
+		if old != cur {
+			fields = append(fields, FieldChange{Field: field.Name, Old: old, New: cur})
+		}
+	}
+	return fields
+}
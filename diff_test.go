@@ -0,0 +1,44 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := &Subtitle{
+		Title:  "v1",
+		Styles: []*Style{{Name: "Default", FontSize: 20}, {Name: "Old"}},
+		Events: []*Event{
+			{Start: "0:00:01.00", End: "0:00:02.00", Text: "one"},
+			{Start: "0:00:02.00", End: "0:00:03.00", Text: "two"},
+			{Start: "0:00:03.00", End: "0:00:04.00", Text: "three"},
+			{Start: "0:00:04.00", End: "0:00:05.00", Text: "four"},
+		},
+	}
+	b := a.Clone()
+	b.Title = "v2"
+	b.Styles[0].FontSize = 24
+	b.Styles[1].Name = "New"
+	b.Events[1].Text = "TWO"
+	b.Events = append(b.Events[:2], b.Events[3:]...)
+	b.Events = append(b.Events, &Event{Start: "0:00:05.00", End: "0:00:06.00", Text: "five"})
+
+	expects := []Change{
+		{Kind: ChangeModified, OldIndex: -1, NewIndex: -1, Fields: []FieldChange{{"Title", "v1", "v2"}}},
+		{Kind: ChangeModified, Section: "Styles", OldIndex: 0, NewIndex: 0, Fields: []FieldChange{{"FontSize", "20", "24"}}},
+		{Kind: ChangeRemoved, Section: "Styles", OldIndex: 1, NewIndex: -1},
+		{Kind: ChangeAdded, Section: "Styles", OldIndex: -1, NewIndex: 1},
+		{Kind: ChangeModified, Section: "Events", OldIndex: 1, NewIndex: 1, Fields: []FieldChange{{"Text", "two", "TWO"}}},
+		{Kind: ChangeRemoved, Section: "Events", OldIndex: 2, NewIndex: -1},
+		{Kind: ChangeAdded, Section: "Events", OldIndex: -1, NewIndex: 3},
+	}
+	changes := Diff(a, b)
+	if !reflect.DeepEqual(changes, expects) {
+		t.Errorf("Expect %+v, got: %+v", expects, changes)
+	}
+
+	if changes := Diff(a, a.Clone()); len(changes) != 0 {
+		t.Errorf("Expect no changes, got: %+v", changes)
+	}
+}
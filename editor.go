@@ -0,0 +1,115 @@
+package ass
+
+// Editor wraps a subtitle to record its edits, so they can be undone and
+// redone. Each edit keeps a snapshot of the subtitle as it was before, the
+// whole subtitle is restored on undo, script info included.
+//
+// The subtitle must only be changed through Edit for the history to stay
+// consistent.
+type Editor struct {
+	sub *Subtitle
+	// Limit is the maximum number of edits which can be undone, no limit
+	// if 0
+	Limit int
+	undo  []editorState
+	redo  []editorState
+}
+
+// editorState is the subtitle as it was before or after a named edit
+type editorState struct {
+	name string
+	sub  *Subtitle
+}
+
+// NewEditor creates an editor of sub
+func NewEditor(sub *Subtitle) *Editor {
+	return &Editor{sub: sub}
+}
+
+// Subtitle returns the edited subtitle
+func (e *Editor) Subtitle() *Subtitle {
+	return e.sub
+}
+
+// Edit runs fn on the subtitle and records the change under name, as shown
+// in an edit menu, clearing the edits to redo. The subtitle is restored and
+// nothing recorded if fn fails.
+func (e *Editor) Edit(name string, fn func(sub *Subtitle) error) error {
+	before := e.sub.Clone()
+	if err := fn(e.sub); err != nil {
+		e.restore(before)
+		return err
+	}
+	e.undo = append(e.undo, editorState{name: name, sub: before})
+	if e.Limit > 0 && len(e.undo) > e.Limit {
+		e.undo = append(e.undo[:0], e.undo[len(e.undo)-e.Limit:]...)
+	}
+	e.redo = nil
+	return nil
+}
+
+// Apply runs a transformer as an edit, see Edit
+func (e *Editor) Apply(name string, t Transformer) error {
+	return e.Edit(name, t.Transform)
+}
+
+// Undo reverts the last edit, returning false if there is none
+func (e *Editor) Undo() bool {
+	if len(e.undo) == 0 {
+		return false
+	}
+	state := e.undo[len(e.undo)-1]
+	e.undo = e.undo[:len(e.undo)-1]
+	e.redo = append(e.redo, editorState{name: state.name, sub: e.sub.Clone()})
+	e.restore(state.sub)
+	return true
+}
+
+// Redo applies again the last edit undone, returning false if there is none
+func (e *Editor) Redo() bool {
+	if len(e.redo) == 0 {
+		return false
+	}
+	state := e.redo[len(e.redo)-1]
+	e.redo = e.redo[:len(e.redo)-1]
+	e.undo = append(e.undo, editorState{name: state.name, sub: e.sub.Clone()})
+	e.restore(state.sub)
+	return true
+}
+
+// UndoName returns the name of the edit Undo would revert, empty if none
+func (e *Editor) UndoName() string {
+	if len(e.undo) == 0 {
+		return ""
+	}
+	return e.undo[len(e.undo)-1].name
+}
+
+// RedoName returns the name of the edit Redo would apply, empty if none
+func (e *Editor) RedoName() string {
+	if len(e.redo) == 0 {
+		return ""
+	}
+	return e.redo[len(e.redo)-1].name
+}
+
+// CanUndo reports whether there is an edit to undo
+func (e *Editor) CanUndo() bool {
+	return len(e.undo) > 0
+}
+
+// CanRedo reports whether there is an edit to redo
+func (e *Editor) CanRedo() bool {
+	return len(e.redo) > 0
+}
+
+// ClearHistory forgets all the edits
+func (e *Editor) ClearHistory() {
+	e.undo, e.redo = nil, nil
+}
+
+// restore sets the subtitle to a snapshot in place, so pointers to the
+// subtitle stay valid
+func (e *Editor) restore(snapshot *Subtitle) {
+	*e.sub = *snapshot
+}
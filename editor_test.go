@@ -0,0 +1,90 @@
+package ass
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEditor(t *testing.T) {
+	sub := &Subtitle{Title: "title", Events: []*Event{{Start: "0:00:01.00", End: "0:00:02.00", Text: "one"}}}
+	e := NewEditor(sub)
+	if e.Undo() || e.Redo() || e.CanUndo() || e.UndoName() != "" {
+		t.Errorf("Expect nothing to undo or redo")
+	}
+
+	if err := e.Edit("Rename", func(sub *Subtitle) error {
+		sub.Title = "renamed"
+		sub.Events[0].Text = "ONE"
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Apply("Shift", Shift(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Edit("Fail", func(sub *Subtitle) error {
+		sub.Events = nil
+		return errors.New("failed")
+	}); err == nil || len(sub.Events) != 1 {
+		t.Errorf("Expect a failed edit reverted, got: %v, %v", err, sub.Events)
+	}
+	if e.UndoName() != "Shift" || sub.Events[0].Start != "0:00:02.00" {
+		t.Errorf("Expect Shift to undo, got: %s, %s", e.UndoName(), sub.Events[0].Start)
+	}
+
+	cases := []struct {
+		action      func() bool
+		ok          bool
+		title, text string
+		start       string
+		undo, redo  string
+	}{
+		{e.Undo, true, "renamed", "ONE", "0:00:01.00", "Rename", "Shift"},
+		{e.Undo, true, "title", "one", "0:00:01.00", "", "Rename"},
+		{e.Undo, false, "title", "one", "0:00:01.00", "", "Rename"},
+		{e.Redo, true, "renamed", "ONE", "0:00:01.00", "Rename", "Shift"},
+		{e.Redo, true, "renamed", "ONE", "0:00:02.00", "Shift", ""},
+		{e.Redo, false, "renamed", "ONE", "0:00:02.00", "Shift", ""},
+	}
+	for i, c := range cases {
+		if ok := c.action(); ok != c.ok {
+			t.Errorf("Case %d: expect %v, got: %v", i, c.ok, ok)
+		}
+		if e.Subtitle() != sub || sub.Title != c.title || sub.Events[0].Text != c.text || sub.Events[0].Start != c.start {
+			t.Errorf("Case %d: expect %s %s %s, got: %s %s %s", i, c.title, c.text, c.start, sub.Title, sub.Events[0].Text, sub.Events[0].Start)
+		}
+		if e.UndoName() != c.undo || e.RedoName() != c.redo {
+			t.Errorf("Case %d: expect %q/%q to undo/redo, got: %q/%q", i, c.undo, c.redo, e.UndoName(), e.RedoName())
+		}
+	}
+
+	e.Undo()
+	e.Edit("Clear", func(sub *Subtitle) error {
+		sub.Events = nil
+		return nil
+	})
+	if e.CanRedo() || len(sub.Events) != 0 {
+		t.Errorf("Expect an edit to clear the edits to redo")
+	}
+}
+
+func TestEditorLimit(t *testing.T) {
+	sub := &Subtitle{}
+	e := NewEditor(sub)
+	e.Limit = 2
+	for _, title := range []string{"a", "b", "c"} {
+		title := title
+		e.Edit(title, func(sub *Subtitle) error {
+			sub.Title = title
+			return nil
+		})
+	}
+	if !e.Undo() || !e.Undo() || e.Undo() || sub.Title != "a" {
+		t.Errorf("Expect 2 edits undone, got: %s", sub.Title)
+	}
+	e.ClearHistory()
+	if e.CanRedo() {
+		t.Errorf("Expect the history cleared")
+	}
+}
@@ -0,0 +1,127 @@
+package ass
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Effect is the Effect field of an event. Besides free text, the renderers
+// support the Scroll up, Scroll down and Banner effects, see ScrollEffect and
+// BannerEffect.
+type Effect string
+
+// ScrollEffect scrolls the text vertically between two lines of the screen
+type ScrollEffect struct {
+	// Down scrolls down instead of up
+	Down bool
+	// Y1 and Y2 delimit the scrolling region, in any order, 0 for both means
+	// the whole screen
+	Y1, Y2 int
+	// Delay slows the scrolling down, in milliseconds per pixel, 0 to 100
+	Delay int
+	// FadeAwayHeight is the height over which the text fades at the edges
+	FadeAwayHeight int
+}
+
+// BannerEffect scrolls the text horizontally across the screen on one line
+type BannerEffect struct {
+	// Delay slows the scrolling down, in milliseconds per pixel, 0 to 100
+	Delay int
+	// LeftToRight scrolls from the left, the text comes from the right
+	// otherwise
+	LeftToRight bool
+	// FadeAwayWidth is the width over which the text fades at the edges
+	FadeAwayWidth int
+}
+
+// Effect returns the effect field value
+func (e ScrollEffect) Effect() Effect {
+	name := "Scroll up"
+	if e.Down {
+		name = "Scroll down"
+	}
+	s := fmt.Sprintf("%s;%d;%d;%d", name, e.Y1, e.Y2, e.Delay)
+	if e.FadeAwayHeight != 0 {
+		s += ";" + strconv.Itoa(e.FadeAwayHeight)
+	}
+	return Effect(s)
+}
+
+// Effect returns the effect field value
+func (e BannerEffect) Effect() Effect {
+	s := "Banner;" + strconv.Itoa(e.Delay)
+	if e.LeftToRight || e.FadeAwayWidth != 0 {
+		s += ";" + strconv.Itoa(boolInt(e.LeftToRight))
+	}
+	if e.FadeAwayWidth != 0 {
+		s += ";" + strconv.Itoa(e.FadeAwayWidth)
+	}
+	return Effect(s)
+}
+
+func boolInt(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// ScrollUp returns a Scroll up effect between y1 and y2
+func ScrollUp(y1, y2, delay int) Effect {
+	return ScrollEffect{Y1: y1, Y2: y2, Delay: delay}.Effect()
+}
+
+// ScrollDown returns a Scroll down effect between y1 and y2
+func ScrollDown(y1, y2, delay int) Effect {
+	return ScrollEffect{Down: true, Y1: y1, Y2: y2, Delay: delay}.Effect()
+}
+
+// Banner returns a Banner effect
+func Banner(delay int, leftToRight bool) Effect {
+	return BannerEffect{Delay: delay, LeftToRight: leftToRight}.Effect()
+}
+
+// effectArgs splits the effect into its name and integer arguments, ok is
+// false if an argument is not an integer
+func (e Effect) effectArgs() (name string, args []int, ok bool) {
+	parts := strings.Split(string(e), ";")
+	for _, p := range parts[1:] {
+		n, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil {
+			return parts[0], nil, false
+		}
+		args = append(args, n)
+	}
+	return parts[0], args, true
+}
+
+// Scroll parses a Scroll up or Scroll down effect
+func (e Effect) Scroll() (ScrollEffect, bool) {
+	name, args, ok := e.effectArgs()
+	down := strings.EqualFold(name, "Scroll down")
+	if !ok || (!down && !strings.EqualFold(name, "Scroll up")) || len(args) < 3 || len(args) > 4 {
+		return ScrollEffect{}, false
+	}
+	scroll := ScrollEffect{Down: down, Y1: args[0], Y2: args[1], Delay: args[2]}
+	if len(args) == 4 {
+		scroll.FadeAwayHeight = args[3]
+	}
+	return scroll, true
+}
+
+// Banner parses a Banner effect
+func (e Effect) Banner() (BannerEffect, bool) {
+	name, args, ok := e.effectArgs()
+	if !ok || !strings.EqualFold(name, "Banner") || len(args) < 1 || len(args) > 3 {
+		return BannerEffect{}, false
+	}
+	banner := BannerEffect{Delay: args[0]}
+	if len(args) > 1 {
+		banner.LeftToRight = args[1] != 0
+	}
+	if len(args) > 2 {
+		banner.FadeAwayWidth = args[2]
+	}
+	return banner, true
+}
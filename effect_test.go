@@ -0,0 +1,47 @@
+package ass
+
+import "testing"
+
+func TestEffect(t *testing.T) {
+	cases := []struct {
+		effect Effect
+		scroll *ScrollEffect
+		banner *BannerEffect
+	}{
+		{"Scroll up;100;400;10", &ScrollEffect{Y1: 100, Y2: 400, Delay: 10}, nil},
+		{"Scroll down;0;0;5;30", &ScrollEffect{Down: true, Delay: 5, FadeAwayHeight: 30}, nil},
+		{"Banner;20", nil, &BannerEffect{Delay: 20}},
+		{"Banner;20;1", nil, &BannerEffect{Delay: 20, LeftToRight: true}},
+		{"Banner;20;0;40", nil, &BannerEffect{Delay: 20, FadeAwayWidth: 40}},
+		{"Scroll up;100", nil, nil},
+		{"Banner;fast", nil, nil},
+		{"Karaoke", nil, nil},
+	}
+
+	for _, c := range cases {
+		scroll, ok := c.effect.Scroll()
+		if ok != (c.scroll != nil) || (ok && scroll != *c.scroll) {
+			t.Errorf("Scroll %q: expect %+v, got: %+v, %v", c.effect, c.scroll, scroll, ok)
+		}
+		if ok && scroll.Effect() != c.effect {
+			t.Errorf("Expect %q, got: %q", c.effect, scroll.Effect())
+		}
+		banner, ok := c.effect.Banner()
+		if ok != (c.banner != nil) || (ok && banner != *c.banner) {
+			t.Errorf("Banner %q: expect %+v, got: %+v, %v", c.effect, c.banner, banner, ok)
+		}
+		if ok && banner.Effect() != c.effect {
+			t.Errorf("Expect %q, got: %q", c.effect, banner.Effect())
+		}
+	}
+
+	if e := ScrollUp(10, 200, 5); e != "Scroll up;10;200;5" {
+		t.Errorf("Unexpected scroll effect: %q", e)
+	}
+	if e := ScrollDown(10, 200, 5); e != "Scroll down;10;200;5" {
+		t.Errorf("Unexpected scroll effect: %q", e)
+	}
+	if e := Banner(3, true); e != "Banner;3;1" {
+		t.Errorf("Unexpected banner effect: %q", e)
+	}
+}
@@ -0,0 +1,62 @@
+package ass
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Encoding is the character encoding of the output
+type Encoding int
+
+// The output encodings
+const (
+	EncodingUTF8 Encoding = iota
+	// EncodingUTF8BOM is UTF-8 with a byte order mark, expected by many
+	// Windows players
+	EncodingUTF8BOM
+	// EncodingUTF16LE is little endian UTF-16 with a byte order mark
+	EncodingUTF16LE
+)
+
+// WithEncoding sets the character encoding of the output, UTF-8 by default
+func WithEncoding(enc Encoding) WriteOption {
+	return func(opts *writeOptions) {
+		opts.encoding = enc
+	}
+}
+
+// encodeWriter wraps w to encode the UTF-8 output, the returned writer must
+// be closed to flush the encoder
+func encodeWriter(w io.Writer, enc Encoding) (io.WriteCloser, error) {
+	switch enc {
+	case EncodingUTF8, EncodingUTF8BOM:
+		return nopCloser{w}, nil
+	case EncodingUTF16LE:
+		encoder := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder()
+		return transform.NewWriter(w, encoder), nil
+	}
+	return nil, fmt.Errorf("Invalid encoding: %d", enc)
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// NewDecodingReader converts input in the named charset, e.g. shift_jis,
+// gbk or utf-16le, to UTF-8. The names are those of the WHATWG encoding
+// standard, which includes the common aliases.
+func NewDecodingReader(r io.Reader, charset string) (io.Reader, error) {
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("Unknown charset: %s", charset)
+	}
+	return transform.NewReader(r, enc.NewDecoder()), nil
+}
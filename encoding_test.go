@@ -0,0 +1,72 @@
+package ass
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestWriteEncoding(t *testing.T) {
+	sub := Subtitle{Events: []*Event{{Start: "0:00:00.00", End: "0:00:01.00", Text: "日本"}}}
+
+	cases := []struct {
+		opts   []WriteOption
+		prefix string
+		utf16  bool
+	}{
+		{[]WriteOption{WithEncoding(EncodingUTF8)}, "\n[Script Info]", false},
+		{[]WriteOption{WithEncoding(EncodingUTF8BOM)}, utf8BOM + "\n[Script Info]", false},
+		{[]WriteOption{WithEncoding(EncodingUTF16LE), WithBOM(true)}, "\xFF\xFE\n\x00[\x00", true},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		if _, err := sub.WriteToWithOptions(&buf, c.opts...); err != nil {
+			t.Errorf("Expect write success, got: %v", err)
+			continue
+		}
+		if !strings.HasPrefix(buf.String(), c.prefix) {
+			t.Errorf("Expect prefix %q, got: %q", c.prefix, buf.String()[:10])
+		}
+		if !c.utf16 {
+			continue
+		}
+		r, err := NewDecodingReader(&buf, "utf-16le")
+		if err != nil {
+			t.Fatalf("Expect decoding reader, got: %v", err)
+		}
+		decoded, _ := ioutil.ReadAll(r)
+		if !strings.HasSuffix(string(decoded), ",日本\n\n") {
+			t.Errorf("Unexpected decoded output: %q", decoded)
+		}
+	}
+}
+
+func TestNewDecodingReader(t *testing.T) {
+	cases := []struct {
+		charset string
+		input   string
+		expect  string
+	}{
+		{"shift_jis", "\x93\xfa\x96\x7b", "日本"},
+		{"gbk", "\xc4\xe3\xba\xc3", "你好"},
+		{"euc-kr", "\xc7\xd1\xb1\xdb", "한글"},
+	}
+
+	for _, c := range cases {
+		r, err := NewDecodingReader(strings.NewReader(c.input), c.charset)
+		if err != nil {
+			t.Errorf("Expect charset %s supported, got: %v", c.charset, err)
+			continue
+		}
+		got, _ := ioutil.ReadAll(r)
+		if string(got) != c.expect {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+	}
+
+	if _, err := NewDecodingReader(strings.NewReader(""), "klingon"); err == nil {
+		t.Errorf("Expect unknown charset error, but passed")
+	}
+}
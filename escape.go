@@ -0,0 +1,31 @@
+package ass
+
+import (
+	"fmt"
+	"strings"
+)
+
+var newlineReplacer = strings.NewReplacer("\r\n", `\N`, "\n", `\N`, "\r", `\N`)
+
+// sanitizeText makes dialogue text safe to be written on a single line,
+// raw line breaks become \N
+func sanitizeText(text string) string {
+	return newlineReplacer.Replace(text)
+}
+
+var plainReplacer = strings.NewReplacer("\r\n", `\N`, "\n", `\N`, "\r", `\N`, "{", `\{`, "}", `\}`)
+
+// EscapeText converts plain text, e.g. user input, to dialogue text:
+// line breaks become \N and braces are escaped so they are not taken as
+// override blocks. Note that escaped braces are only understood by libass.
+func EscapeText(text string) string {
+	return plainReplacer.Replace(text)
+}
+
+// checkField makes sure a value can be written as a comma separated field
+func checkField(field, value string) error {
+	if strings.ContainsAny(value, ",\r\n") {
+		return fmt.Errorf("Invalid %s, comma and line break are not allowed: %q", field, value)
+	}
+	return nil
+}
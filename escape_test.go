@@ -0,0 +1,9 @@
+package ass
+
+import "testing"
+
+func TestEscapeText(t *testing.T) {
+	if got := EscapeText("{a}\nb"); got != `\{a\}\Nb` {
+		t.Errorf("Unexpected escaped text: %q", got)
+	}
+}
@@ -0,0 +1,109 @@
+package ass
+
+import (
+	"net/url"
+	"strings"
+)
+
+// extraMarker starts the block holding the extra data of an event, before
+// its text and after the forced marker
+const extraMarker = "{extra:"
+
+// SetExtra sets a value of the extra data of the event
+func (evt *Event) SetExtra(key, value string) {
+	if evt.Extra == nil {
+		evt.Extra = make(map[string]string)
+	}
+	evt.Extra[key] = value
+}
+
+// encodeExtra encodes the extra data as a query string, sorted by key,
+// without braces nor backslashes so it can't break the override blocks
+func encodeExtra(extra map[string]string) string {
+	if len(extra) == 0 {
+		return ""
+	}
+	values := make(url.Values, len(extra))
+	for k, v := range extra {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
+
+// extraBlock returns the block written for the extra data, if any
+func extraBlock(extra map[string]string) string {
+	if len(extra) == 0 {
+		return ""
+	}
+	return extraMarker + encodeExtra(extra) + "}"
+}
+
+func appendExtra(b []byte, extra map[string]string) []byte {
+	if len(extra) == 0 {
+		return b
+	}
+	b = append(b, extraMarker...)
+	b = append(b, encodeExtra(extra)...)
+	return append(b, '}')
+}
+
+// parseExtra splits the extra data from the start of text. The text is
+// returned as is if it doesn't start with a valid extra block.
+func parseExtra(text string) (map[string]string, string) {
+	if !strings.HasPrefix(text, extraMarker) {
+		return nil, text
+	}
+	end := strings.IndexByte(text, '}')
+	if end < 0 {
+		return nil, text
+	}
+	values, err := url.ParseQuery(text[len(extraMarker):end])
+	if err != nil {
+		return nil, text
+	}
+	var extra map[string]string
+	for k, v := range values {
+		if extra == nil {
+			extra = make(map[string]string, len(values))
+		}
+		extra[k] = v[0]
+	}
+	return extra, text[end+1:]
+}
+
+// clone returns a copy of the event which doesn't share its extra data
+func (evt Event) clone() Event {
+	evt.Extra = copyExtra(evt.Extra)
+	return evt
+}
+
+func copyExtra(extra map[string]string) map[string]string {
+	if extra == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(extra))
+	for k, v := range extra {
+		copied[k] = v
+	}
+	return copied
+}
+
+// eventKey is an event as a comparable value, its extra data encoded
+type eventKey struct {
+	Layer                     int
+	Start, End, Style, Name   string
+	MarginL, MarginR, MarginV uint
+	Effect                    Effect
+	Text                      string
+	Comment, Forced           bool
+	Extra                     string
+}
+
+func (evt *Event) key() eventKey {
+	return eventKey{
+		Layer: evt.Layer, Start: evt.Start, End: evt.End, Style: evt.Style, Name: evt.Name,
+		MarginL: evt.MarginL, MarginR: evt.MarginR, MarginV: evt.MarginV,
+		Effect: evt.Effect, Text: evt.Text, Comment: evt.Comment, Forced: evt.Forced,
+		Extra: encodeExtra(evt.Extra),
+	}
+}
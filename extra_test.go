@@ -0,0 +1,78 @@
+package ass
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtraRoundTrip(t *testing.T) {
+	sub := &Subtitle{Events: []*Event{
+		{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "Hola", Forced: true,
+			Extra: map[string]string{"id": "a1", "confidence": "0.82"}},
+		{Start: "0:00:02.00", End: "0:00:03.00", Style: "Default", Text: `{\i1}Hello`,
+			Extra: map[string]string{"note": "a {b}\\c & d=e"}},
+		{Start: "0:00:03.00", End: "0:00:04.00", Style: "Default", Text: "Bye"},
+	}}
+	var buf bytes.Buffer
+	if _, err := sub.WriteTo(&buf); err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), ",{forced}{extra:confidence=0.82&id=a1}Hola\n") {
+		t.Errorf("Expect the extra block after the forced marker, got: %s", buf.String())
+	}
+	parsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	for i, evt := range sub.Events {
+		got := parsed.Events[i]
+		if got.Text != evt.Text || got.Forced != evt.Forced || !reflect.DeepEqual(got.Extra, evt.Extra) {
+			t.Errorf("Event %d: expect %+v, got: %+v", i, *evt, *got)
+		}
+	}
+}
+
+func TestParseExtra(t *testing.T) {
+	cases := []struct {
+		text  string
+		extra map[string]string
+		rest  string
+	}{
+		{"Hello", nil, "Hello"},
+		{"{extra:id=1}Hello", map[string]string{"id": "1"}, "Hello"},
+		{"{extra:id=1&id=2&x=a+b}", map[string]string{"id": "1", "x": "a b"}, ""},
+		{"{extra:}Hello", nil, "Hello"},
+		{"{extra:id=%zz}Hello", nil, "{extra:id=%zz}Hello"},
+		{"{extra:id=1", nil, "{extra:id=1"},
+		{`{\i1}{extra:id=1}`, nil, `{\i1}{extra:id=1}`},
+	}
+	for _, c := range cases {
+		extra, rest := parseExtra(c.text)
+		if !reflect.DeepEqual(extra, c.extra) || rest != c.rest {
+			t.Errorf("Expect %v and %q for %q, got: %v and %q", c.extra, c.rest, c.text, extra, rest)
+		}
+	}
+}
+
+func TestExtraCompare(t *testing.T) {
+	a := &Event{Start: "0:00:01.00", End: "0:00:02.00", Text: "Hello", Extra: map[string]string{"id": "1"}}
+	b := copyEvent(a)
+	b.Extra["id"] = "2"
+	if a.Extra["id"] != "1" {
+		t.Errorf("Expect the extra data to be copied")
+	}
+	if sameEvent(a, b) {
+		t.Errorf("Expect events with different extra data to differ")
+	}
+	b.SetExtra("id", "1")
+	if !sameEvent(a, b) {
+		t.Errorf("Expect events with the same extra data to be the same")
+	}
+
+	sub := &Subtitle{Events: []*Event{a, b, {Start: "0:00:01.00", End: "0:00:02.00", Text: "Hello"}}}
+	if removed := sub.Dedupe(0); removed != 2 {
+		t.Errorf("Expect 2 events removed, got: %d", removed)
+	}
+}
@@ -0,0 +1,82 @@
+package ass
+
+import (
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// FilterOptions configures the ffmpeg filter burning a subtitle in
+type FilterOptions struct {
+	// Filter is the ffmpeg filter, "subtitles" by default or "ass"
+	Filter string
+	// FontsDir is a directory of fonts used besides the system ones
+	FontsDir string
+}
+
+// FFmpegFilter returns the filtergraph burning the subtitle file at path
+// in, e.g. subtitles=filename=in.ass, escaped for -vf or -filter_complex
+func FFmpegFilter(path string, opts FilterOptions) string {
+	return ffmpegFilter(path, opts, runtime.GOOS == "windows")
+}
+
+func ffmpegFilter(path string, opts FilterOptions, windows bool) string {
+	filter := opts.Filter
+	if filter == "" {
+		filter = "subtitles"
+	}
+	filter += "=filename=" + escapeFilterPath(path, windows)
+	if opts.FontsDir != "" {
+		filter += ":fontsdir=" + escapeFilterPath(opts.FontsDir, windows)
+	}
+	return filter
+}
+
+// escapeFilterPath escapes a path as a filter option value. Backslashes of
+// Windows paths become slashes so only the drive colon needs escaping. The
+// value is escaped twice: for the option parser, then for the filtergraph.
+func escapeFilterPath(path string, windows bool) string {
+	if windows {
+		path = strings.Replace(path, `\`, "/", -1)
+	}
+	var option strings.Builder
+	for _, r := range path {
+		if strings.ContainsRune(`\':`, r) {
+			option.WriteByte('\\')
+		}
+		option.WriteRune(r)
+	}
+	var graph strings.Builder
+	for _, r := range option.String() {
+		if strings.ContainsRune(`\'[],;`, r) {
+			graph.WriteByte('\\')
+		}
+		graph.WriteRune(r)
+	}
+	return graph.String()
+}
+
+// FFmpegArgs writes the subtitle to a temporary file and returns the ffmpeg
+// arguments burning it into the video of input, written to output with the
+// audio copied. cleanup removes the temporary file once ffmpeg is done.
+func (as Subtitle) FFmpegArgs(input, output string, opts FilterOptions, writeOpts ...WriteOption) (args []string, cleanup func() error, err error) {
+	tmp, err := ioutil.TempFile("", "ass-*.ass")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup = func() error {
+		return os.Remove(tmp.Name())
+	}
+	if _, err = as.WriteToWithOptions(tmp, writeOpts...); err == nil {
+		err = tmp.Close()
+	} else {
+		tmp.Close()
+	}
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	args = []string{"-i", input, "-vf", FFmpegFilter(tmp.Name(), opts), "-c:a", "copy", output}
+	return args, cleanup, nil
+}
@@ -0,0 +1,48 @@
+package ass
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestFFmpegFilter(t *testing.T) {
+	cases := []struct {
+		path    string
+		opts    FilterOptions
+		windows bool
+		expect  string
+	}{
+		{"/tmp/sub.ass", FilterOptions{}, false, "subtitles=filename=/tmp/sub.ass"},
+		{"/tmp/it's [1].ass", FilterOptions{Filter: "ass"}, false, `ass=filename=/tmp/it\\\'s \[1\].ass`},
+		{"/tmp/a:b,c.ass", FilterOptions{}, false, `subtitles=filename=/tmp/a\\:b\,c.ass`},
+		{`C:\Videos\sub.ass`, FilterOptions{FontsDir: `C:\Fonts`}, true, `subtitles=filename=C\\:/Videos/sub.ass:fontsdir=C\\:/Fonts`},
+	}
+
+	for _, c := range cases {
+		if got := ffmpegFilter(c.path, c.opts, c.windows); got != c.expect {
+			t.Errorf("Filter of %q: expect %s, got: %s", c.path, c.expect, got)
+		}
+	}
+}
+
+func TestFFmpegArgs(t *testing.T) {
+	sub := Subtitle{Events: []*Event{{Start: "0:00:01.00", End: "0:00:02.00", Text: "burn"}}}
+	args, cleanup, err := sub.FFmpegArgs("in.mp4", "out.mp4", FilterOptions{})
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	if len(args) != 7 || args[0] != "-i" || args[1] != "in.mp4" || args[6] != "out.mp4" {
+		t.Errorf("Unexpected args: %q", args)
+	}
+	path := strings.TrimPrefix(args[3], "subtitles=filename=")
+	if data, err := ioutil.ReadFile(path); err != nil || !strings.Contains(string(data), "burn") {
+		t.Errorf("Expect the filter to point at the subtitle, got: %s", args[3])
+	}
+	if err := cleanup(); err != nil {
+		t.Errorf("Expect the temporary file removed, got: %v", err)
+	}
+	if _, err := ioutil.ReadFile(path); err == nil {
+		t.Errorf("Expect the temporary file removed")
+	}
+}
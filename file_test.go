@@ -0,0 +1,46 @@
+package ass
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "sub.ass")
+
+	sub := newBenchSubtitle(10)
+	if err := sub.WriteFile(path); err != nil {
+		t.Fatalf("Expect write success, got: %v", err)
+	}
+	read, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expect read success, got: %v", err)
+	}
+	if len(read.Events) != 10 || read.Title != sub.Title {
+		t.Errorf("Unexpected subtitle read: %+v", read)
+	}
+
+	// a failed write keeps the old file and leaves no temporary file
+	sub.Events[0].Start = "bad"
+	if err := sub.WriteFile(path); err == nil {
+		t.Errorf("Expect invalid subtitle error, but passed")
+	}
+	if read, err := ReadFile(path); err != nil || len(read.Events) != 10 {
+		t.Errorf("Expect the old file kept, got: %v", err)
+	}
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("Expect no temporary file left, got %d files", len(files))
+	}
+
+	if _, err := ReadFile(filepath.Join(dir, "missing.ass")); err == nil {
+		t.Errorf("Expect missing file error, but passed")
+	}
+}
@@ -0,0 +1,152 @@
+package ass
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// styleByName find the style with the given name, nil if not exists
+func (as *Subtitle) styleByName(name string) *Style {
+	for _, style := range as.Styles {
+		if style != nil && style.Name == name {
+			return style
+		}
+	}
+	return nil
+}
+
+// styleTags returns the override tags turning style def into style s
+func styleTags(def, s *Style) string {
+	var b strings.Builder
+	if s.FontName != def.FontName {
+		b.WriteString(`\fn` + s.FontName)
+	}
+	if s.FontSize != def.FontSize {
+		b.WriteString(`\fs` + strconv.Itoa(s.FontSize))
+	}
+	colorTags(&b, "1", def.PrimaryColor, s.PrimaryColor)
+	colorTags(&b, "2", def.SecondColor, s.SecondColor)
+	colorTags(&b, "3", def.OutlineColor, s.OutlineColor)
+	colorTags(&b, "4", def.BackColor, s.BackColor)
+	flagTag(&b, `\b`, def.Bold, s.Bold)
+	flagTag(&b, `\i`, def.Italic, s.Italic)
+	flagTag(&b, `\u`, def.Underline, s.Underline)
+	flagTag(&b, `\s`, def.StrikeOut, s.StrikeOut)
+	if scale(s.ScaleX) != scale(def.ScaleX) {
+		b.WriteString(`\fscx` + strconv.Itoa(scale(s.ScaleX)))
+	}
+	if scale(s.ScaleY) != scale(def.ScaleY) {
+		b.WriteString(`\fscy` + strconv.Itoa(scale(s.ScaleY)))
+	}
+	numbers := []struct {
+		tag    string
+		def, v float64
+	}{
+		{`\fsp`, def.Spacing, s.Spacing},
+		{`\frz`, def.Angle, s.Angle},
+		{`\bord`, outline(def.Outline), outline(s.Outline)},
+		{`\shad`, def.Shadow, s.Shadow},
+	}
+	for _, n := range numbers {
+		if n.v != n.def {
+			b.WriteString(n.tag + formatNumber(n.v))
+		}
+	}
+	if alignment(s.Alignment) != alignment(def.Alignment) {
+		b.WriteString(`\an` + strconv.Itoa(alignment(s.Alignment)))
+	}
+	return b.String()
+}
+
+// colorTags writes \Nc and \Na tags for an ABGR color which differs from def
+func colorTags(b *strings.Builder, n, def, color string) {
+	if !isValidABGR(color) {
+		color = "00000000"
+	}
+	if !isValidABGR(def) {
+		def = "00000000"
+	}
+	if !strings.EqualFold(color[2:], def[2:]) {
+		b.WriteString(`\` + n + `c&H` + color[2:] + `&`)
+	}
+	if !strings.EqualFold(color[:2], def[:2]) {
+		b.WriteString(`\` + n + `a&H` + color[:2] + `&`)
+	}
+}
+
+func flagTag(b *strings.Builder, tag string, def, v int) {
+	if v == def {
+		return
+	}
+	if v != 0 {
+		b.WriteString(tag + "1")
+	} else {
+		b.WriteString(tag + "0")
+	}
+}
+
+// scale returns the effective scale percent, 0 means unset
+func scale(v int) int {
+	if v == 0 {
+		return 100
+	}
+	return v
+}
+
+// FlattenStyles bakes the style of every event into inline override tags
+// against the style named def, so that the script only uses one style.
+// Style resets (\r and \rName) inside the text are rewritten as well.
+// Styles other than def are removed from the subtitle.
+func (as *Subtitle) FlattenStyles(def string) error {
+	defStyle := as.styleByName(def)
+	if defStyle == nil {
+		return fmt.Errorf("Unknown style: %s", def)
+	}
+
+	for _, evt := range as.Events {
+		if evt == nil {
+			return fmt.Errorf("Event cannot be nil")
+		}
+		style := defStyle
+		if evt.Style != "" && evt.Style != def {
+			if style = as.styleByName(evt.Style); style == nil {
+				return fmt.Errorf("Unknown style: %s", evt.Style)
+			}
+		}
+
+		parts := splitText(evt.Text)
+		for i, p := range parts {
+			if !p.Override {
+				continue
+			}
+			// keep the comment before the first tag, if any
+			var b strings.Builder
+			b.WriteString(p.Text[:strings.IndexByte(p.Text+`\`, '\\')])
+			for _, tag := range splitTags(p.Text) {
+				if !strings.HasPrefix(tag, `\r`) {
+					b.WriteString(tag)
+					continue
+				}
+				reset := style
+				if name := tag[2:]; name != "" {
+					if reset = as.styleByName(name); reset == nil {
+						// renderers fall back to the line style
+						reset = style
+					}
+				}
+				b.WriteString(`\r` + styleTags(defStyle, reset))
+			}
+			parts[i].Text = b.String()
+		}
+
+		if tags := styleTags(defStyle, style); tags != "" {
+			parts = append([]textPart{{Text: tags, Override: true}}, parts...)
+		}
+		evt.Text = joinText(parts)
+		evt.Style = def
+	}
+
+	as.Styles = []*Style{defStyle}
+	return nil
+}
@@ -0,0 +1,46 @@
+package ass
+
+import "testing"
+
+func TestFlattenStyles(t *testing.T) {
+	newSub := func(text, style string) *Subtitle {
+		return &Subtitle{
+			Styles: []*Style{
+				{Name: "Default", FontName: "Arial", FontSize: 48, PrimaryColor: "00FFFFFF"},
+				{Name: "Signs", FontName: "Verdana", FontSize: 48, PrimaryColor: "800000FF", Bold: -1},
+			},
+			Events: []*Event{{Start: "0:00:00.00", End: "0:00:01.00", Style: style, Text: text}},
+		}
+	}
+
+	cases := []struct {
+		style  string
+		text   string
+		expect string
+	}{
+		{"Default", "Hello", "Hello"},
+		{"Signs", "Hello", `{\fnVerdana\1c&H0000FF&\1a&H80&\b1}Hello`},
+		{"Signs", `A{\r}B`, `{\fnVerdana\1c&H0000FF&\1a&H80&\b1}A{\r\fnVerdana\1c&H0000FF&\1a&H80&\b1}B`},
+		{"Default", `A{\i1\rSigns}B`, `A{\i1\r\fnVerdana\1c&H0000FF&\1a&H80&\b1}B`},
+		{"Default", `{TL note: pun}Hello`, `{TL note: pun}Hello`},
+		{"Default", `A{note\rSigns}B`, `A{note\r\fnVerdana\1c&H0000FF&\1a&H80&\b1}B`},
+	}
+
+	for _, c := range cases {
+		sub := newSub(c.text, c.style)
+		if err := sub.FlattenStyles("Default"); err != nil {
+			t.Errorf("Expect flatten success, got: %v", err)
+			continue
+		}
+		if got := sub.Events[0].Text; got != c.expect {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+		if len(sub.Styles) != 1 || sub.Events[0].Style != "Default" {
+			t.Errorf("Expect only the default style left")
+		}
+	}
+
+	if err := newSub("", "Default").FlattenStyles("Missing"); err == nil {
+		t.Errorf("Expect unknown default style error, but passed")
+	}
+}
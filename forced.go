@@ -0,0 +1,36 @@
+package ass
+
+import "strings"
+
+// IsForced reports whether the event is forced: flagged as such, or of a
+// style or actor among the markers, compared case insensitively
+func (evt Event) IsForced(markers ...string) bool {
+	if evt.Forced {
+		return true
+	}
+	for _, m := range markers {
+		if strings.EqualFold(evt.Style, m) || (evt.Name != "" && strings.EqualFold(evt.Name, m)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ForcedOnly returns a new subtitle with only the forced events, see
+// Event.IsForced, for a forced narrative track: the foreign dialogue and
+// signs shown to the viewers not reading subtitles. Styles and the script
+// info are copied, comments are dropped.
+func (as *Subtitle) ForcedOnly(markers ...string) *Subtitle {
+	forced := *as
+	forced.index = nil
+	forced.Styles = copyStyles(as.Styles)
+	forced.Events = nil
+	for _, evt := range as.Events {
+		if evt == nil || evt.Comment || !evt.IsForced(markers...) {
+			continue
+		}
+		e := evt.clone()
+		forced.Events = append(forced.Events, &e)
+	}
+	return &forced
+}
@@ -0,0 +1,58 @@
+package ass
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestForcedOnly(t *testing.T) {
+	sub := &Subtitle{
+		Styles: []*Style{{Name: "Default"}, {Name: "Sign"}},
+		Events: []*Event{
+			{Style: "Default", Text: "Hello"},
+			{Style: "Default", Text: "Hola", Forced: true},
+			{Style: "sign", Text: "Exit"},
+			{Style: "Default", Name: "Alien", Text: "Zorg"},
+			{Style: "Sign", Text: "Note", Comment: true},
+			nil,
+		},
+	}
+	forced := sub.ForcedOnly("Sign", "alien")
+	var texts []string
+	for _, evt := range forced.Events {
+		texts = append(texts, evt.Text)
+	}
+	if strings.Join(texts, ",") != "Hola,Exit,Zorg" {
+		t.Errorf("Expect the forced events, got: %v", texts)
+	}
+	forced.Styles[0].Name = "Changed"
+	forced.Events[0].Text = "Changed"
+	if sub.Styles[0].Name != "Default" || sub.Events[1].Text != "Hola" {
+		t.Errorf("Expect the original subtitle unchanged")
+	}
+	if n := len(sub.ForcedOnly().Events); n != 1 {
+		t.Errorf("Expect 1 flagged event without markers, got: %d", n)
+	}
+}
+
+func TestForcedRoundTrip(t *testing.T) {
+	sub := &Subtitle{Events: []*Event{
+		{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "Hola", Forced: true},
+		{Start: "0:00:02.00", End: "0:00:03.00", Style: "Default", Text: "Hello"},
+	}}
+	var buf bytes.Buffer
+	if _, err := sub.WriteTo(&buf); err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), ",{forced}Hola\n") {
+		t.Errorf("Expect the forced marker, got: %s", buf.String())
+	}
+	parsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	if !parsed.Events[0].Forced || parsed.Events[0].Text != "Hola" || parsed.Events[1].Forced {
+		t.Errorf("Expect the forced flag to round trip, got: %+v", parsed.Events)
+	}
+}
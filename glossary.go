@@ -0,0 +1,220 @@
+package ass
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Term is a glossary entry, a term kept consistent across the dialogue
+type Term struct {
+	Term string `json:"term"`
+	// Translation replaces the term, if not empty
+	Translation string `json:"translation"`
+	// Tags are override tags, e.g. \i1, the term is written with, reset to
+	// the style after it
+	Tags string `json:"tags"`
+	// CaseSensitive matches the term with its case only
+	CaseSensitive bool `json:"caseSensitive"`
+}
+
+// Glossary is a list of terms
+type Glossary []Term
+
+// GlossaryMatch is an occurrence of a term in the dialogue
+type GlossaryMatch struct {
+	// Index is the index of the event
+	Index int    `json:"index"`
+	Term  string `json:"term"`
+	// Text is the matched text and Replacement what it is replaced with
+	Text        string `json:"text"`
+	Replacement string `json:"replacement"`
+}
+
+// LoadGlossary reads a glossary, a term per line with its translation and
+// tags separated by tabs, the translation and tags being optional. Blank
+// lines and lines starting with # are skipped.
+//
+//	# term	translation	tags
+//	Onii-chan	big brother
+//	Excalibur		\i1
+func LoadGlossary(r io.Reader) (Glossary, error) {
+	scanner := bufio.NewScanner(r)
+	var g Glossary
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) > 3 {
+			return nil, fmt.Errorf("Line %d: too many fields", lineNo)
+		}
+		for len(fields) < 3 {
+			fields = append(fields, "")
+		}
+		term := Term{Term: strings.TrimSpace(fields[0]), Translation: strings.TrimSpace(fields[1]), Tags: strings.TrimSpace(fields[2])}
+		if err := term.validate(); err != nil {
+			return nil, fmt.Errorf("Line %d: %v", lineNo, err)
+		}
+		g = append(g, term)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (t Term) validate() error {
+	if t.Term == "" {
+		return fmt.Errorf("Missing term")
+	}
+	if strings.ContainsAny(t.Translation, "{}") {
+		return fmt.Errorf("Invalid translation of %s: %s", t.Term, t.Translation)
+	}
+	if strings.ContainsAny(t.Tags, "{}") || (t.Tags != "" && !strings.HasPrefix(t.Tags, `\`)) {
+		return fmt.Errorf("Invalid tags of %s: %s", t.Term, t.Tags)
+	}
+	return nil
+}
+
+// replacement returns what the matched text of the term is replaced with
+func (t Term) replacement(text string) string {
+	if t.Translation != "" {
+		text = t.Translation
+	}
+	if t.Tags == "" {
+		return text
+	}
+	return "{" + t.Tags + "}" + text + "{" + resetTags(t.Tags) + "}"
+}
+
+var tagNameReg = regexp.MustCompile(`^\\[0-9]?[a-z]+`)
+
+// resetTags returns the tags without their arguments, which reset them to
+// the style, e.g. \i for \i1
+func resetTags(tags string) string {
+	var b strings.Builder
+	for _, tag := range splitTags(tags) {
+		b.WriteString(tagNameReg.FindString(tag))
+	}
+	return b.String()
+}
+
+// isWordRune reports whether r is part of a word of a language separating
+// its words with spaces, which terms are only matched as a whole of
+func isWordRune(r rune) bool {
+	if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai) {
+		return false
+	}
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// ApplyGlossary replaces the terms of the glossary in the plain text of the
+// events but comments, whole words only, and returns the matches in order.
+// The longest term wins where terms overlap. With dryRun set the events are
+// left as they are, for a report of what would change.
+func (as *Subtitle) ApplyGlossary(g Glossary, dryRun bool) ([]GlossaryMatch, error) {
+	terms := make(Glossary, len(g))
+	copy(terms, g)
+	sort.SliceStable(terms, func(i, j int) bool {
+		return utf8.RuneCountInString(terms[i].Term) > utf8.RuneCountInString(terms[j].Term)
+	})
+	regs := make([]*regexp.Regexp, len(terms))
+	for i, term := range terms {
+		if err := term.validate(); err != nil {
+			return nil, err
+		}
+		expr := regexp.QuoteMeta(term.Term)
+		if !term.CaseSensitive {
+			expr = "(?i)" + expr
+		}
+		regs[i] = regexp.MustCompile(expr)
+	}
+
+	var matches []GlossaryMatch
+	for index, evt := range as.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		parts := splitText(evt.Text)
+		drawing := false
+		for i, p := range parts {
+			if p.Override {
+				for _, tag := range splitTags(p.Text) {
+					if level, ok := drawingTag(tag); ok {
+						drawing = level > 0
+					}
+				}
+				continue
+			}
+			if drawing {
+				continue
+			}
+			text, found := applyTerms(p.Text, terms, regs)
+			for _, m := range found {
+				m.Index = index
+				matches = append(matches, m)
+			}
+			parts[i].Text = text
+		}
+		if !dryRun {
+			evt.Text = joinText(parts)
+		}
+	}
+	return matches, nil
+}
+
+// applyTerms replaces the terms in plain text, the longest first
+func applyTerms(text string, terms Glossary, regs []*regexp.Regexp) (string, []GlossaryMatch) {
+	type span struct {
+		start, end int
+		term       int
+	}
+	var spans []span
+	taken := func(start, end int) bool {
+		for _, s := range spans {
+			if start < s.end && end > s.start {
+				return true
+			}
+		}
+		return false
+	}
+	for i, reg := range regs {
+		for _, loc := range reg.FindAllStringIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			first, _ := utf8.DecodeRuneInString(text[start:end])
+			last, _ := utf8.DecodeLastRuneInString(text[start:end])
+			before, _ := utf8.DecodeLastRuneInString(text[:start])
+			after, _ := utf8.DecodeRuneInString(text[end:])
+			if (isWordRune(first) && start > 0 && isWordRune(before)) ||
+				(isWordRune(last) && end < len(text) && isWordRune(after)) || taken(start, end) {
+				continue
+			}
+			spans = append(spans, span{start, end, i})
+		}
+	}
+	if len(spans) == 0 {
+		return text, nil
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var b strings.Builder
+	var matches []GlossaryMatch
+	last := 0
+	for _, s := range spans {
+		found := text[s.start:s.end]
+		repl := terms[s.term].replacement(found)
+		b.WriteString(text[last:s.start])
+		b.WriteString(repl)
+		last = s.end
+		matches = append(matches, GlossaryMatch{Term: terms[s.term].Term, Text: found, Replacement: repl})
+	}
+	b.WriteString(text[last:])
+	return b.String(), matches
+}
@@ -0,0 +1,82 @@
+package ass
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadGlossary(t *testing.T) {
+	g, err := LoadGlossary(strings.NewReader("# term\ttranslation\ttags\n\nOnii-chan\tbig brother\nExcalibur\t\t\\i1\r\nSenpai\n"))
+	expect := Glossary{
+		{Term: "Onii-chan", Translation: "big brother"},
+		{Term: "Excalibur", Tags: `\i1`},
+		{Term: "Senpai"},
+	}
+	if err != nil || !reflect.DeepEqual(g, expect) {
+		t.Errorf("Expect %v, got: %v, %v", expect, g, err)
+	}
+
+	cases := []struct {
+		text   string
+		expect string
+	}{
+		{"a\tb\tc\td", "Line 1: too many fields"},
+		{"\tb", "Line 1: Missing term"},
+		{"# x\na\tb\t{\\i1}", "Line 2: Invalid tags of a: {\\i1}"},
+		{"a\tb\ti1", "Line 1: Invalid tags of a: i1"},
+	}
+	for _, c := range cases {
+		if _, err := LoadGlossary(strings.NewReader(c.text)); err == nil || err.Error() != c.expect {
+			t.Errorf("Expect error %q, got: %v", c.expect, err)
+		}
+	}
+}
+
+func TestApplyGlossary(t *testing.T) {
+	g := Glossary{
+		{Term: "New York", Translation: "NY"},
+		{Term: "New York City", Translation: "NYC"},
+		{Term: "Excalibur", Tags: `\i1\c&H00FFFF&`},
+		{Term: "cat", Translation: "dog"},
+		{Term: "Tokyo", Translation: "東京", CaseSensitive: true},
+		{Term: "先輩", Translation: "senpai"},
+	}
+	sub := &Subtitle{Events: []*Event{
+		{Text: `{\pos(10,10)}Welcome to New York City, new york!`},
+		{Text: `The cat, the Cat and the category.`},
+		{Text: `{\b1}excalibur{\b0}, tokyo or Tokyo`},
+		{Text: `cat`, Comment: true},
+		{Text: `{\p1}m 0 0 l cat{\p0}先輩です`},
+	}}
+	matches, err := sub.ApplyGlossary(g, true)
+	if err != nil || len(matches) != 7 || sub.Events[1].Text != `The cat, the Cat and the category.` {
+		t.Fatalf("Expect 7 matches and no change, got: %v, %v", matches, err)
+	}
+	if m := matches[0]; m.Index != 0 || m.Term != "New York City" || m.Text != "New York City" || m.Replacement != "NYC" {
+		t.Errorf("Expect the longest term first, got: %+v", m)
+	}
+	if m := matches[4]; m.Index != 2 || m.Text != "excalibur" || m.Replacement != `{\i1\c&H00FFFF&}excalibur{\i\c}` {
+		t.Errorf("Expect the tagged term, got: %+v", m)
+	}
+
+	if _, err := sub.ApplyGlossary(g, false); err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{
+		`{\pos(10,10)}Welcome to NYC, NY!`,
+		`The dog, the dog and the category.`,
+		`{\b1}{\i1\c&H00FFFF&}excalibur{\i\c}{\b0}, tokyo or 東京`,
+		`cat`,
+		`{\p1}m 0 0 l cat{\p0}senpaiです`,
+	}
+	for i, evt := range sub.Events {
+		if evt.Text != expect[i] {
+			t.Errorf("Expect %q, got: %q", expect[i], evt.Text)
+		}
+	}
+
+	if _, err := sub.ApplyGlossary(Glossary{{Term: ""}}, true); err == nil {
+		t.Errorf("Expect an error for an empty term")
+	}
+}
@@ -1,3 +1,10 @@
 module github.com/apigo/ass
 
-go 1.14
+go 1.16
+
+require (
+	github.com/BurntSushi/toml v1.2.1
+	golang.org/x/image v0.0.0-20220902085622-e7cb96979f69
+	golang.org/x/text v0.3.8
+	gopkg.in/yaml.v3 v3.0.1
+)
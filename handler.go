@@ -0,0 +1,73 @@
+package ass
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// Content types served by Handler
+const (
+	ContentTypeASS = "text/x-ssa; charset=utf-8"
+	ContentTypeVTT = "text/vtt; charset=utf-8"
+)
+
+// Handler serves the subtitle as an ass script, or as WebVTT when the path
+// ends with .vtt or the request accepts text/vtt. The response is rendered
+// per request, has an ETag from its content and supports range requests and
+// conditional requests.
+func Handler(sub *Subtitle) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var (
+			buf         bytes.Buffer
+			err         error
+			contentType = ContentTypeASS
+		)
+		if wantsVTT(r) {
+			contentType = ContentTypeVTT
+			_, err = sub.WriteVTT(&buf)
+		} else {
+			_, err = sub.WriteTo(&buf)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		sum := sha256.Sum256(buf.Bytes())
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+		w.Header().Add("Vary", "Accept")
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(buf.Bytes()))
+	})
+}
+
+// wantsVTT reports whether the request asks for WebVTT
+func wantsVTT(r *http.Request) bool {
+	switch strings.ToLower(path.Ext(r.URL.Path)) {
+	case ".vtt":
+		return true
+	case ".ass", ".ssa":
+		return false
+	}
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(accept, ";", 2)[0])
+		switch strings.ToLower(mediaType) {
+		case "text/vtt":
+			return true
+		case "text/x-ssa", "text/x-ass":
+			return false
+		}
+	}
+	return false
+}
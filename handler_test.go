@@ -0,0 +1,68 @@
+package ass
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	sub := &Subtitle{Events: []*Event{{Start: "0:00:01.00", End: "0:00:02.00", Text: "served"}}}
+	handler := Handler(sub)
+
+	cases := []struct {
+		path        string
+		header      map[string]string
+		status      int
+		contentType string
+		prefix      string
+	}{
+		{"/sub.ass", nil, http.StatusOK, ContentTypeASS, "\n[Script Info]"},
+		{"/sub.vtt", nil, http.StatusOK, ContentTypeVTT, "WEBVTT\n"},
+		{"/sub", map[string]string{"Accept": "text/vtt;q=0.9, */*"}, http.StatusOK, ContentTypeVTT, "WEBVTT\n"},
+		{"/sub.vtt", map[string]string{"Range": "bytes=0-5"}, http.StatusPartialContent, ContentTypeVTT, "WEBVTT"},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, c.path, nil)
+		for k, v := range c.header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != c.status {
+			t.Errorf("%s: expect status %d, got: %d", c.path, c.status, rec.Code)
+		}
+		if got := rec.Header().Get("Content-Type"); got != c.contentType {
+			t.Errorf("%s: expect content type %s, got: %s", c.path, c.contentType, got)
+		}
+		if !strings.HasPrefix(rec.Body.String(), c.prefix) {
+			t.Errorf("%s: expect body starting with %q, got: %q", c.path, c.prefix, rec.Body.String())
+		}
+		if c.status == http.StatusPartialContent && rec.Body.Len() != 6 {
+			t.Errorf("%s: expect 6 bytes, got: %d", c.path, rec.Body.Len())
+		}
+	}
+
+	// conditional request
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/sub.ass", nil))
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("Expect an ETag")
+	}
+	req := httptest.NewRequest(http.MethodGet, "/sub.ass", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("Expect status %d, got: %d", http.StatusNotModified, rec.Code)
+	}
+
+	sub.Events[0].Text = "changed"
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("Expect a new ETag after a change, got: %d %s", rec.Code, rec.Header().Get("ETag"))
+	}
+}
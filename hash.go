@@ -0,0 +1,145 @@
+package ass
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+// Hash returns a SHA-256 hash of the content of the subtitle, in hex, which
+// is the same for two subtitles with the same content whatever their
+// formatting: the title, original script and the editor state of the
+// [Aegisub Project Garbage] section are left out, the defaults are applied,
+// timestamps are compared by value, colors ignoring the case and the text
+// without empty override blocks, with the adjacent ones merged. The other
+// raw sections, e.g. embedded fonts, count as they are. It is a content
+// hash, not a rendering one: the comments, actors, effects and extra data of
+// the events count although they don't change the rendering. The order of
+// the styles and events matters.
+func (as *Subtitle) Hash() string {
+	h := sha256.New()
+	w := hashWriter{h}
+
+	width, height := as.PlayRes()
+	w.uint(uint64(width), uint64(height), uint64(as.PlayDepth), uint64(as.LayoutResX), uint64(as.LayoutResY))
+	w.string(strconv.FormatFloat(float64(as.Timer), 'f', 4, 32))
+	w.int(int64(as.WrapStyle))
+	w.bool(as.ScaledBorderAndShadow, as.Kerning)
+	collisions := as.Collisions
+	if collisions == "" {
+		collisions = CollisionsNormal
+	}
+	w.string(string(as.YCbCrMatrix), string(collisions), as.Language)
+
+	w.uint(uint64(len(as.Styles)))
+	for _, style := range as.Styles {
+		w.bool(style != nil)
+		if style == nil {
+			continue
+		}
+		font := style.FontName
+		if font == "" {
+			font = defFontName
+		}
+		w.string(style.Name, font)
+		w.int(int64(style.FontSize), int64(bold(style.Bold)), int64(style.Italic), int64(style.Underline), int64(style.StrikeOut),
+			int64(scale(style.ScaleX)), int64(scale(style.ScaleY)), int64(borderStyle(style.BorderStyle)), int64(alignment(style.Alignment)),
+			int64(style.Encoding))
+		w.string(formatNumber(style.Spacing), formatNumber(style.Angle), formatNumber(outline(style.Outline)), formatNumber(style.Shadow))
+		l, r, v := style.margins()
+		w.uint(uint64(l), uint64(r), uint64(v))
+		w.string(strings.ToUpper(style.PrimaryColor), strings.ToUpper(style.SecondColor),
+			strings.ToUpper(style.OutlineColor), strings.ToUpper(style.BackColor))
+	}
+
+	w.uint(uint64(len(as.Events)))
+	for _, evt := range as.Events {
+		w.bool(evt != nil)
+		if evt == nil {
+			continue
+		}
+		w.int(int64(evt.Layer))
+		for _, t := range []string{evt.Start, evt.End} {
+			ts, err := ParseTimestamp(t)
+			w.bool(err == nil)
+			if err == nil {
+				w.int(int64(ts))
+			} else {
+				w.string(t)
+			}
+		}
+		w.string(evt.Style, evt.Name, string(evt.Effect), hashText(evt.Text))
+		w.uint(uint64(evt.MarginL), uint64(evt.MarginR), uint64(evt.MarginV))
+		w.bool(evt.Comment, evt.Forced)
+		w.string(encodeExtra(evt.Extra))
+	}
+
+	for _, raw := range as.RawSections {
+		if raw == nil || raw.Name == projectGarbage {
+			continue
+		}
+		w.string(raw.Name)
+		w.uint(uint64(len(raw.Lines)))
+		w.string(raw.Lines...)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// projectGarbage is the section Aegisub keeps its editor state in
+const projectGarbage = "Aegisub Project Garbage"
+
+// hashText returns the text as hashed: raw line breaks as \N, without empty
+// override blocks and with the adjacent ones merged
+func hashText(text string) string {
+	var parts []textPart
+	for _, p := range splitText(sanitizeText(text)) {
+		if p.Override && p.Text == "" {
+			continue
+		}
+		if n := len(parts); n > 0 && p.Override && parts[n-1].Override && strings.HasPrefix(p.Text, `\`) {
+			parts[n-1].Text += p.Text
+			continue
+		}
+		parts = append(parts, p)
+	}
+	return joinText(parts)
+}
+
+// hashWriter writes values to a hash unambiguously
+type hashWriter struct {
+	h hash.Hash
+}
+
+func (w hashWriter) string(values ...string) {
+	for _, v := range values {
+		w.h.Write(strconv.AppendInt(nil, int64(len(v)), 10))
+		w.h.Write([]byte{':'})
+		w.h.Write([]byte(v))
+	}
+}
+
+func (w hashWriter) int(values ...int64) {
+	for _, v := range values {
+		w.h.Write(strconv.AppendInt(nil, v, 10))
+		w.h.Write([]byte{';'})
+	}
+}
+
+func (w hashWriter) uint(values ...uint64) {
+	for _, v := range values {
+		w.h.Write(strconv.AppendUint(nil, v, 10))
+		w.h.Write([]byte{';'})
+	}
+}
+
+func (w hashWriter) bool(values ...bool) {
+	for _, v := range values {
+		if v {
+			w.h.Write([]byte{'1'})
+		} else {
+			w.h.Write([]byte{'0'})
+		}
+	}
+}
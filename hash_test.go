@@ -0,0 +1,55 @@
+package ass
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	a, err := Parse(strings.NewReader(sampleScript))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the same content, formatted differently
+	b, err := Parse(strings.NewReader(strings.NewReplacer(
+		"Title: Sample", "Title: Other",
+		"Original Script: someone", "; no author",
+		"&H00FFFFFF", "&H00ffffff",
+		"0:00:01.00,0:00:02.50", "00:00:01.00,0:00:02.50",
+		"{\\i1}world{\\i0}", "{}{\\i1}world{}{\\i0}",
+		"0010,0000,0000", "10,0,0",
+	).Replace(sampleScript) + "\n[Aegisub Project Garbage]\nActive Line: 2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Hash() != b.Hash() || len(a.Hash()) != 64 {
+		t.Errorf("Expect the same hash, got: %s and %s", a.Hash(), b.Hash())
+	}
+
+	changes := []func(sub *Subtitle){
+		func(sub *Subtitle) { sub.Events[0].Text += "!" },
+		func(sub *Subtitle) { sub.Events[0].End = "0:00:02.51" },
+		func(sub *Subtitle) { sub.Events[1].Comment = false },
+		func(sub *Subtitle) { sub.Styles[0].PrimaryColor = "00FFFFFE" },
+		func(sub *Subtitle) { sub.PlayerWidth = 1920 },
+		func(sub *Subtitle) { sub.Events[0], sub.Events[2] = sub.Events[2], sub.Events[0] },
+		func(sub *Subtitle) { sub.Events = append(sub.Events, nil) },
+		func(sub *Subtitle) { sub.Events[0].Start = "bad" },
+		func(sub *Subtitle) { sub.Events[0].SetExtra("review", "approved") },
+		func(sub *Subtitle) { sub.Events[1].Text += "!" },
+		func(sub *Subtitle) {
+			sub.RawSections = append(sub.RawSections, &RawSection{Name: "Fonts", Lines: []string{"fontname: a.ttf", "AAAA"}})
+		},
+	}
+	for i, change := range changes {
+		c := a.Clone()
+		change(c)
+		if c.Hash() == a.Hash() {
+			t.Errorf("Expect change %d to change the hash", i)
+		}
+	}
+
+	if (&Subtitle{}).Hash() != (&Subtitle{PlayerWidth: 1920, PlayerHeight: 1080, Collisions: CollisionsNormal}).Hash() {
+		t.Errorf("Expect the defaults applied")
+	}
+}
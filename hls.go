@@ -0,0 +1,142 @@
+package ass
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// HLSOptions configures the WebVTT segments of an HLS subtitle track
+type HLSOptions struct {
+	// SegmentDuration is the duration of the segments, 6 seconds by default
+	SegmentDuration time.Duration
+	// Duration is the duration of the media, the end of the last event by
+	// default, segments cover it entirely
+	Duration time.Duration
+	// MPEGTS is the MPEG-TS timestamp, in 90 kHz units, the cue time 0
+	// maps to in X-TIMESTAMP-MAP
+	MPEGTS int64
+	// SegmentName formats the name of a segment from its index,
+	// "segment%d.vtt" by default
+	SegmentName string
+	// PlaylistURI is the URI of the media playlist used in EXT-X-MEDIA,
+	// "subtitles.m3u8" by default
+	PlaylistURI string
+	// GroupID, Name and Language describe the track in EXT-X-MEDIA,
+	// GroupID is "subs", Name is "Subtitles" and Language the language of
+	// the subtitle by default
+	GroupID  string
+	Name     string
+	Language string
+}
+
+// HLSSegment is a WebVTT media segment
+type HLSSegment struct {
+	Name     string
+	Start    time.Duration
+	Duration time.Duration
+	Data     []byte
+}
+
+// HLSTrack is a subtitle split for HLS
+type HLSTrack struct {
+	Segments []HLSSegment
+	// Playlist is the media playlist listing the segments
+	Playlist []byte
+	// Media is the EXT-X-MEDIA tag to add to the master playlist
+	Media string
+}
+
+// HLS splits the subtitle into WebVTT segments and their playlist. An event
+// spanning several segments is repeated in each of them.
+func (as Subtitle) HLS(opts HLSOptions) (*HLSTrack, error) {
+	if err := as.validate(); err != nil {
+		return nil, err
+	}
+	if opts.SegmentDuration <= 0 {
+		opts.SegmentDuration = 6 * time.Second
+	}
+	if opts.SegmentName == "" {
+		opts.SegmentName = "segment%d.vtt"
+	}
+	if opts.PlaylistURI == "" {
+		opts.PlaylistURI = "subtitles.m3u8"
+	}
+	if opts.GroupID == "" {
+		opts.GroupID = "subs"
+	}
+	if opts.Name == "" {
+		opts.Name = "Subtitles"
+	}
+	if opts.Language == "" {
+		opts.Language = as.Language
+	}
+
+	events := sortedEvents(as.Events)
+	type cue struct {
+		start, end time.Duration
+		text       string
+	}
+	cues := make([]cue, 0, len(events))
+	duration := opts.Duration
+	for _, evt := range events {
+		if evt.Comment {
+			continue
+		}
+		start, end, err := evt.times()
+		if err != nil {
+			return nil, err
+		}
+		cues = append(cues, cue{start.Duration(), end.Duration(), evt.Text})
+		if opts.Duration <= 0 && end.Duration() > duration {
+			duration = end.Duration()
+		}
+	}
+
+	track := &HLSTrack{}
+	header := fmt.Sprintf("WEBVTT\nX-TIMESTAMP-MAP=MPEGTS:%d,LOCAL:00:00:00.000\n", opts.MPEGTS)
+	first := 0
+	for start := time.Duration(0); start < duration; start += opts.SegmentDuration {
+		end := start + opts.SegmentDuration
+		if end > duration {
+			end = duration
+		}
+		data := []byte(header)
+		for first < len(cues) && cues[first].end <= start {
+			first++
+		}
+		for _, c := range cues[first:] {
+			if c.start >= end {
+				break
+			}
+			if c.end > start {
+				data = appendVTTCue(data, c.start, c.end, c.text)
+			}
+		}
+		track.Segments = append(track.Segments, HLSSegment{
+			Name:     fmt.Sprintf(opts.SegmentName, len(track.Segments)),
+			Start:    start,
+			Duration: end - start,
+			Data:     data,
+		})
+	}
+
+	var playlist bytes.Buffer
+	playlist.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	fmt.Fprintf(&playlist, "#EXT-X-TARGETDURATION:%d\n", int64(math.Ceil(opts.SegmentDuration.Seconds())))
+	playlist.WriteString("#EXT-X-MEDIA-SEQUENCE:0\n#EXT-X-PLAYLIST-TYPE:VOD\n")
+	for _, seg := range track.Segments {
+		fmt.Fprintf(&playlist, "#EXTINF:%.3f,\n%s\n", seg.Duration.Seconds(), seg.Name)
+	}
+	playlist.WriteString("#EXT-X-ENDLIST\n")
+	track.Playlist = playlist.Bytes()
+
+	track.Media = "#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID=" + strconv.Quote(opts.GroupID) + ",NAME=" + strconv.Quote(opts.Name)
+	if opts.Language != "" {
+		track.Media += ",LANGUAGE=" + strconv.Quote(opts.Language)
+	}
+	track.Media += ",DEFAULT=NO,AUTOSELECT=YES,URI=" + strconv.Quote(opts.PlaylistURI)
+	return track, nil
+}
@@ -0,0 +1,47 @@
+package ass
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHLS(t *testing.T) {
+	sub := Subtitle{Events: []*Event{
+		{Start: "0:00:01.00", End: "0:00:02.00", Text: "one"},
+		{Start: "0:00:03.00", End: "0:00:05.00", Text: "across"},
+		{Start: "0:00:08.00", End: "0:00:09.00", Text: "three"},
+	}}
+	track, err := sub.HLS(HLSOptions{SegmentDuration: 4 * time.Second, MPEGTS: 900000, Language: "en"})
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	if len(track.Segments) != 3 {
+		t.Fatalf("Expect 3 segments, got: %d", len(track.Segments))
+	}
+	header := "WEBVTT\nX-TIMESTAMP-MAP=MPEGTS:900000,LOCAL:00:00:00.000\n"
+	expects := []string{
+		header + "\n00:00:01.000 --> 00:00:02.000\none\n\n00:00:03.000 --> 00:00:05.000\nacross\n",
+		header + "\n00:00:03.000 --> 00:00:05.000\nacross\n",
+		header + "\n00:00:08.000 --> 00:00:09.000\nthree\n",
+	}
+	for i, seg := range track.Segments {
+		if string(seg.Data) != expects[i] {
+			t.Errorf("Segment %d: expect %q, got: %q", i, expects[i], seg.Data)
+		}
+	}
+	if seg := track.Segments[2]; seg.Name != "segment2.vtt" || seg.Start != 8*time.Second || seg.Duration != time.Second {
+		t.Errorf("Unexpected last segment: %+v", seg)
+	}
+
+	playlist := string(track.Playlist)
+	for _, line := range []string{"#EXT-X-TARGETDURATION:4\n", "#EXTINF:4.000,\nsegment0.vtt\n", "#EXTINF:1.000,\nsegment2.vtt\n#EXT-X-ENDLIST\n"} {
+		if !strings.Contains(playlist, line) {
+			t.Errorf("Expect playlist to contain %q, got: %s", line, playlist)
+		}
+	}
+	expectMedia := `#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="subs",NAME="Subtitles",LANGUAGE="en",DEFAULT=NO,AUTOSELECT=YES,URI="subtitles.m3u8"`
+	if track.Media != expectMedia {
+		t.Errorf("Expect %s, got: %s", expectMedia, track.Media)
+	}
+}
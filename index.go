@@ -0,0 +1,119 @@
+package ass
+
+import "sort"
+
+// EventIndex is an interval index over events, answering which events are
+// on screen at a given time in O(log n + k)
+type EventIndex struct {
+	items  []indexItem // sorted by start
+	maxEnd []Timestamp // max end of the subtree rooted at each item
+	events []*Event    // the indexed slice, to detect a replaced slice
+}
+
+type indexItem struct {
+	evt        *Event
+	start, end Timestamp
+}
+
+// NewEventIndex indexes the events, events with invalid timestamps are skipped.
+// The index must be rebuilt when the events are retimed.
+func NewEventIndex(events []*Event) *EventIndex {
+	idx := &EventIndex{events: events}
+	for _, evt := range events {
+		if evt == nil {
+			continue
+		}
+		start, end, err := evt.times()
+		if err != nil || end <= start {
+			continue
+		}
+		idx.items = append(idx.items, indexItem{evt, start, end})
+	}
+	sort.SliceStable(idx.items, func(i, j int) bool { return idx.items[i].start < idx.items[j].start })
+	idx.maxEnd = make([]Timestamp, len(idx.items))
+	idx.build(0, len(idx.items))
+	return idx
+}
+
+// build fills maxEnd of the implicit tree over items[lo:hi], rooted at the middle
+func (idx *EventIndex) build(lo, hi int) Timestamp {
+	if lo >= hi {
+		return -1
+	}
+	mid := (lo + hi) / 2
+	max := idx.items[mid].end
+	if end := idx.build(lo, mid); end > max {
+		max = end
+	}
+	if end := idx.build(mid+1, hi); end > max {
+		max = end
+	}
+	idx.maxEnd[mid] = max
+	return max
+}
+
+// ActiveAt returns the events with start <= t < end, in order of start time
+func (idx *EventIndex) ActiveAt(t Timestamp) []*Event {
+	var active []*Event
+	idx.query(0, len(idx.items), t, &active)
+	return active
+}
+
+func (idx *EventIndex) query(lo, hi int, t Timestamp, active *[]*Event) {
+	if lo >= hi {
+		return
+	}
+	mid := (lo + hi) / 2
+	if idx.maxEnd[mid] <= t {
+		return
+	}
+	idx.query(lo, mid, t, active)
+	item := idx.items[mid]
+	if item.start > t {
+		return
+	}
+	if t < item.end {
+		*active = append(*active, item.evt)
+	}
+	idx.query(mid+1, hi, t, active)
+}
+
+// ActiveAt returns the events on screen at t, in order of start time. It
+// uses the index built by Reindex, in O(log n + k), as long as the events are
+// the same slice of the same length, or scans every event otherwise. Call
+// Reindex again after changing events in place. ActiveAt itself never
+// changes the subtitle.
+func (as *Subtitle) ActiveAt(t Timestamp) []*Event {
+	if as.index.covers(as.Events) {
+		return as.index.ActiveAt(t)
+	}
+	var items []indexItem
+	for _, evt := range as.Events {
+		if evt == nil {
+			continue
+		}
+		if start, end, err := evt.times(); err == nil && start <= t && t < end {
+			items = append(items, indexItem{evt, start, end})
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].start < items[j].start })
+	var active []*Event
+	for _, item := range items {
+		active = append(active, item.evt)
+	}
+	return active
+}
+
+// Reindex builds the index used by ActiveAt
+func (as *Subtitle) Reindex() {
+	as.index = NewEventIndex(as.Events)
+}
+
+// covers reports whether idx was built for events, in O(1): events must be
+// the same slice, not reallocated nor resized since
+func (idx *EventIndex) covers(events []*Event) bool {
+	if idx == nil || len(idx.events) != len(events) {
+		return false
+	}
+	return len(events) == 0 || &idx.events[0] == &events[0]
+}
@@ -0,0 +1,67 @@
+package ass
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestActiveAt(t *testing.T) {
+	sub := &Subtitle{
+		Events: []*Event{
+			{Start: "0:00:05.00", End: "0:00:06.00", Text: "C"},
+			{Start: "0:00:01.00", End: "0:00:10.00", Text: "A"},
+			{Start: "0:00:02.00", End: "0:00:03.00", Text: "B"},
+			{Start: "0:00:07.00", End: "0:00:08.00", Text: "D"},
+			{Start: "bad", End: "0:00:08.00", Text: "X"},
+		},
+	}
+
+	cases := []struct {
+		at     time.Duration
+		expect string
+	}{
+		{0, ""},
+		{time.Second, "A"},
+		{2500 * time.Millisecond, "AB"},
+		{3 * time.Second, "A"},
+		{5 * time.Second, "AC"},
+		{7500 * time.Millisecond, "AD"},
+		{10 * time.Second, ""},
+	}
+
+	for _, indexed := range []bool{false, true} {
+		if indexed {
+			sub.Reindex()
+		}
+		for _, c := range cases {
+			got := ""
+			for _, evt := range sub.ActiveAt(Timestamp(c.at)) {
+				got += evt.Text
+			}
+			if got != c.expect {
+				t.Errorf("At %v, indexed %v: expect %q, got: %q", c.at, indexed, c.expect, got)
+			}
+		}
+		if (sub.index != nil) != indexed {
+			t.Errorf("Expect ActiveAt not to build the index")
+		}
+	}
+
+	sub.Events = append(sub.Events, &Event{Start: "0:00:00.00", End: "0:00:00.50", Text: "E"})
+	if active := sub.ActiveAt(0); len(active) != 1 || active[0].Text != "E" {
+		t.Errorf("Expect the index rebuilt after adding events, got: %v", active)
+	}
+
+	// the slice is compacted in place, then refilled to the same length
+	sub.Select().WithTextMatching(regexp.MustCompile(`^E$`)).Delete()
+	sub.Events = append(sub.Events, &Event{Start: "0:00:00.00", End: "0:00:00.50", Text: "F"})
+	if active := sub.ActiveAt(0); len(active) != 1 || active[0].Text != "F" {
+		t.Errorf("Expect the index rebuilt after a delete and an append, got: %v", active)
+	}
+	sub.Events[0] = &Event{Start: "0:00:00.00", End: "0:00:00.50", Text: "G"}
+	sub.Reindex()
+	if active := sub.ActiveAt(0); len(active) != 2 || active[0].Text != "G" {
+		t.Errorf("Expect the index rebuilt by Reindex after replacing an event, got: %v", active)
+	}
+}
@@ -0,0 +1,138 @@
+package ass
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// The JSON Lines form of a subtitle is a header record, the JSON form of the
+// subtitle without its events, followed by one event per line in its JSON
+// form. It can be produced and consumed incrementally.
+
+// JSONLinesEncoder writes the JSON Lines form of a subtitle
+type JSONLinesEncoder struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewJSONLinesEncoder writes the header record of sub to w, its events are
+// ignored, use Encode to stream them
+func NewJSONLinesEncoder(w io.Writer, sub Subtitle) (*JSONLinesEncoder, error) {
+	sub.Events = nil
+	if err := sub.validate(); err != nil {
+		return nil, err
+	}
+	e := &JSONLinesEncoder{w: bufio.NewWriter(w)}
+	e.enc = json.NewEncoder(e.w)
+	if err := e.enc.Encode(sub); err != nil {
+		return nil, err
+	}
+	return e, e.w.Flush()
+}
+
+// Encode validates the event, writes it on its own line and flushes
+func (e *JSONLinesEncoder) Encode(evt *Event) error {
+	if err := e.write(evt); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+// write validates and buffers the event
+func (e *JSONLinesEncoder) write(evt *Event) error {
+	if evt == nil {
+		return fmt.Errorf("Event cannot be nil")
+	}
+	if err := evt.validate(); err != nil {
+		return err
+	}
+	return e.enc.Encode(evt)
+}
+
+// JSONLinesDecoder reads the JSON Lines form of a subtitle
+type JSONLinesDecoder struct {
+	r      *bufio.Reader
+	header *Subtitle
+	lineNo int
+}
+
+// NewJSONLinesDecoder reads the header record from r
+func NewJSONLinesDecoder(r io.Reader) (*JSONLinesDecoder, error) {
+	d := &JSONLinesDecoder{r: bufio.NewReader(r), header: &Subtitle{}}
+	if err := d.decode(d.header); err == io.EOF {
+		return nil, fmt.Errorf("Missing JSON Lines header")
+	} else if err != nil {
+		return nil, err
+	}
+	d.header.Events = nil
+	return d, nil
+}
+
+// Header returns the subtitle of the header record, without events
+func (d *JSONLinesDecoder) Header() *Subtitle {
+	return d.header
+}
+
+// Decode reads the next event, io.EOF at the end of the stream
+func (d *JSONLinesDecoder) Decode() (*Event, error) {
+	evt := &Event{}
+	if err := d.decode(evt); err != nil {
+		return nil, err
+	}
+	return evt, nil
+}
+
+// decode reads the next non blank line into v
+func (d *JSONLinesDecoder) decode(v interface{}) error {
+	for {
+		line, err := d.r.ReadBytes('\n')
+		if len(line) > 0 {
+			d.lineNo++
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				if err := json.Unmarshal(line, v); err != nil {
+					return fmt.Errorf("Line %d: %v", d.lineNo, err)
+				}
+				return nil
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// WriteJSONLines writes the subtitle in the JSON Lines form
+func (as Subtitle) WriteJSONLines(w io.Writer) error {
+	e, err := NewJSONLinesEncoder(w, as)
+	if err != nil {
+		return err
+	}
+	for _, evt := range as.Events {
+		if err := e.write(evt); err != nil {
+			return err
+		}
+	}
+	return e.w.Flush()
+}
+
+// ParseJSONLines reads a whole subtitle in the JSON Lines form
+func ParseJSONLines(r io.Reader) (*Subtitle, error) {
+	d, err := NewJSONLinesDecoder(r)
+	if err != nil {
+		return nil, err
+	}
+	sub := d.Header()
+	for {
+		evt, err := d.Decode()
+		if err == io.EOF {
+			return sub, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		sub.Events = append(sub.Events, evt)
+	}
+}
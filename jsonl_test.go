@@ -0,0 +1,70 @@
+package ass
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONLines(t *testing.T) {
+	sub := Subtitle{
+		Title:  "lines",
+		Styles: []*Style{{Name: "Default", FontSize: 20}},
+		Events: []*Event{
+			{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "first"},
+			{Start: "0:00:02.00", End: "0:00:03.00", Style: "Default", Text: "second", Comment: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := sub.WriteJSONLines(&buf); err != nil {
+		t.Fatalf("Expect write success, got: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], `"title":"lines"`) || !strings.Contains(lines[1], `"first"`) {
+		t.Errorf("Expect a header and 2 events, got:\n%s", buf.String())
+	}
+
+	parsed, err := ParseJSONLines(&buf)
+	if err != nil {
+		t.Fatalf("Expect parse success, got: %v", err)
+	}
+	if !reflect.DeepEqual(*parsed, sub) {
+		t.Errorf("Expect %+v, got: %+v", sub, *parsed)
+	}
+
+	// streamed event by event, blank lines are skipped
+	var stream bytes.Buffer
+	enc, err := NewJSONLinesEncoder(&stream, sub)
+	if err != nil {
+		t.Fatalf("Expect header written, got: %v", err)
+	}
+	if err := enc.Encode(sub.Events[0]); err != nil {
+		t.Fatalf("Expect event written, got: %v", err)
+	}
+	if err := enc.Encode(&Event{Start: "bad"}); err == nil {
+		t.Errorf("Expect invalid event error, but passed")
+	}
+	stream.WriteString("\n")
+	dec, err := NewJSONLinesDecoder(&stream)
+	if err != nil {
+		t.Fatalf("Expect header read, got: %v", err)
+	}
+	if dec.Header().Title != "lines" || len(dec.Header().Events) != 0 {
+		t.Errorf("Unexpected header: %+v", dec.Header())
+	}
+	if evt, err := dec.Decode(); err != nil || !reflect.DeepEqual(*evt, *sub.Events[0]) {
+		t.Errorf("Expect %+v, got: %+v, %v", sub.Events[0], evt, err)
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Errorf("Expect EOF, got: %v", err)
+	}
+
+	for _, c := range []string{"", "{\"title\":\"x\"}\n{bad\n"} {
+		if _, err := ParseJSONLines(strings.NewReader(c)); err == nil {
+			t.Errorf("Expect error for %q, but passed", c)
+		}
+	}
+}
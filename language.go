@@ -0,0 +1,134 @@
+package ass
+
+import (
+	"strings"
+	"unicode"
+)
+
+// LanguageDetector guesses the language of dialogue text, returning a BCP
+// 47 tag or "" when unsure
+type LanguageDetector interface {
+	DetectLanguage(text string) string
+}
+
+// LanguageDetectorFunc adapts a function to a LanguageDetector
+type LanguageDetectorFunc func(text string) string
+
+// DetectLanguage calls fn
+func (fn LanguageDetectorFunc) DetectLanguage(text string) string {
+	return fn(text)
+}
+
+// languageScripts are the scripts telling the language on their own, kana
+// before Han for Japanese
+var languageScripts = []struct {
+	script *unicode.RangeTable
+	lang   string
+}{
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Hangul, "ko"},
+	{unicode.Han, "zh"},
+	{unicode.Thai, "th"},
+	{unicode.Greek, "el"},
+	{unicode.Hebrew, "he"},
+	{unicode.Arabic, "ar"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Cyrillic, "ru"},
+}
+
+// languageWords are some of the most frequent words of the languages
+// written in Latin script
+var languageWords = map[string][]string{
+	"en": {"the", "and", "you", "is", "that", "it", "of", "to", "what", "this", "are", "have", "my", "don't", "i'm"},
+	"fr": {"le", "la", "les", "et", "est", "je", "tu", "vous", "pas", "une", "que", "c'est", "des", "du", "ne"},
+	"de": {"der", "die", "das", "und", "ist", "ich", "du", "nicht", "ein", "eine", "sie", "wir", "was", "zu", "mit"},
+	"es": {"el", "la", "los", "y", "es", "que", "yo", "no", "una", "por", "qué", "está", "lo", "pero", "muy"},
+	"it": {"il", "la", "e", "è", "che", "non", "sono", "di", "un", "una", "per", "mi", "ti", "cosa", "ho"},
+	"pt": {"o", "a", "os", "e", "é", "que", "não", "um", "uma", "eu", "você", "do", "da", "está", "isso"},
+	"nl": {"de", "het", "een", "en", "is", "ik", "je", "niet", "dat", "wat", "van", "zijn", "we", "op", "maar"},
+}
+
+// ScriptDetector is a small LanguageDetector working from the scripts of
+// the text, and for the Latin script from the most frequent words of
+// English, French, German, Spanish, Italian, Portuguese and Dutch. It is
+// meant for whole scripts, the guesses on a few words are unreliable.
+type ScriptDetector struct{}
+
+// DetectLanguage guesses the language of the text
+func (ScriptDetector) DetectLanguage(text string) string {
+	text = strings.ToLower(displayText(strings.Replace(text, `\N`, " ", -1)))
+	counts := make(map[string]int)
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, s := range languageScripts {
+			if unicode.Is(s.script, r) {
+				counts[s.lang]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+	// kana among Han characters is Japanese
+	if counts["ja"] > 0 && counts["ja"]+counts["zh"] > letters/2 {
+		return "ja"
+	}
+	if lang := mostFrequent(counts); lang != "" && counts[lang] > letters/2 {
+		return lang
+	}
+
+	counts = make(map[string]int)
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	}) {
+		for lang, words := range languageWords {
+			for _, w := range words {
+				if word == w {
+					counts[lang]++
+				}
+			}
+		}
+	}
+	return mostFrequent(counts)
+}
+
+// mostFrequent returns the key with the highest count, "" on a tie or if
+// there is none
+func mostFrequent(counts map[string]int) string {
+	best, max, tie := "", 0, false
+	for key, n := range counts {
+		switch {
+		case n > max:
+			best, max, tie = key, n, false
+		case n == max:
+			tie = true
+		}
+	}
+	if tie {
+		return ""
+	}
+	return best
+}
+
+// DetectLanguage guesses the language of the dialogue, comments aside, with
+// detector or ScriptDetector if nil. The Language field is left as it is.
+func (as *Subtitle) DetectLanguage(detector LanguageDetector) string {
+	if detector == nil {
+		detector = ScriptDetector{}
+	}
+	var b strings.Builder
+	for _, evt := range as.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		b.WriteString(spellText(evt.Text))
+		b.WriteByte('\n')
+	}
+	return detector.DetectLanguage(b.String())
+}
@@ -0,0 +1,61 @@
+package ass
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScriptDetector(t *testing.T) {
+	cases := []struct {
+		text   string
+		expect string
+	}{
+		{"これは何ですか？\\N東京へ行きます", "ja"},
+		{"我们走吧，这是什么？", "zh"},
+		{"안녕하세요, 잘 지냈어요?", "ko"},
+		{"Привет, как дела?", "ru"},
+		{"مرحبا، كيف حالك؟", "ar"},
+		{"{\\i1}What{\\i0} is that? I don't know, and you?", "en"},
+		{"Je ne sais pas, c'est une blague et vous le savez.", "fr"},
+		{"Ich weiß nicht, was du mit der Katze und dem Hund machst.", "de"},
+		{"No sé por qué está aquí, pero es muy raro.", "es"},
+		{"1, 2, 3...", ""},
+		{"", ""},
+	}
+	for _, c := range cases {
+		if got := (ScriptDetector{}).DetectLanguage(c.text); got != c.expect {
+			t.Errorf("Expect %q for %q, got: %q", c.expect, c.text, got)
+		}
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	sub := &Subtitle{Events: []*Event{
+		{Text: "Where are you going?"},
+		{Text: "C'est la vie, et je ne le sais pas.", Comment: true},
+		nil,
+		{Text: "{\\pos(1,1)}I think that it is the end of the road."},
+	}}
+	if lang := sub.DetectLanguage(nil); lang != "en" || sub.Language != "" {
+		t.Errorf("Expect en, got: %q", lang)
+	}
+	var text string
+	lang := sub.DetectLanguage(LanguageDetectorFunc(func(s string) string {
+		text = s
+		return "x-test"
+	}))
+	if lang != "x-test" || strings.Contains(text, "C'est") || strings.Contains(text, "pos") {
+		t.Errorf("Expect the dialogue text passed to the detector, got: %q", text)
+	}
+}
+
+func TestValidateLanguage(t *testing.T) {
+	for _, lang := range []string{"en", "pt-BR", "zh-Hant-TW", ""} {
+		if err := (&Subtitle{Language: lang}).Validate(); err != nil {
+			t.Errorf("Expect %q to be valid, got: %v", lang, err)
+		}
+	}
+	if err := (&Subtitle{Language: "english!"}).Validate(); err == nil || !strings.Contains(err.Error(), "Invalid language: english!") {
+		t.Errorf("Expect an invalid language, got: %v", err)
+	}
+}
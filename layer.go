@@ -0,0 +1,94 @@
+package ass
+
+import "sort"
+
+// SetLayer moves the selected events to layer n
+func (sel *Selection) SetLayer(n int) {
+	for _, evt := range sel.events {
+		evt.Layer = n
+	}
+}
+
+// NormalizeLayers renumbers the layers in use to 0, 1, 2... keeping their
+// order, so that no layer is skipped
+func (as *Subtitle) NormalizeLayers() {
+	renumberLayers(as.Events, 0)
+}
+
+// renumberLayers renumbers the layers of the events from first, keeping
+// their order, and returns the next free layer
+func renumberLayers(events []*Event, first int) int {
+	var layers []int
+	seen := make(map[int]bool)
+	for _, evt := range events {
+		if evt != nil && !seen[evt.Layer] {
+			seen[evt.Layer] = true
+			layers = append(layers, evt.Layer)
+		}
+	}
+	sort.Ints(layers)
+	renumbered := make(map[int]int, len(layers))
+	for i, layer := range layers {
+		renumbered[layer] = first + i
+	}
+	for _, evt := range events {
+		if evt != nil {
+			evt.Layer = renumbered[evt.Layer]
+		}
+	}
+	return first + len(layers)
+}
+
+// IsSign reports whether the event looks like a typeset sign rather than
+// dialogue: it is positioned with \pos or \move, is a drawing or is not
+// bottom aligned. Its style is taken as bottom aligned, see
+// Subtitle.IsSign.
+func IsSign(evt *Event) bool {
+	return isSignAligned(evt, 2)
+}
+
+// IsSign is the same as the IsSign function, with the event aligned as its
+// style by default
+func (as *Subtitle) IsSign(evt *Event) bool {
+	return isSignAligned(evt, as.styleByName(evt.Style).align())
+}
+
+func isSignAligned(evt *Event, align int) bool {
+	if band, positioned := collisionBand(evt.Text, align); positioned || band != 0 {
+		return true
+	}
+	for _, p := range splitText(evt.Text) {
+		if !p.Override {
+			continue
+		}
+		for _, tag := range splitTags(p.Text) {
+			if n, ok := drawingTag(tag); ok && n > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AutoLayer puts the signs above the dialogue, so that they are never
+// hidden by it and don't push it around: the layers of the dialogue are
+// normalized from 0, and the signs get the layers above, keeping the order
+// of their own layers. isSign tells signs from dialogue, Subtitle.IsSign by
+// default.
+func (as *Subtitle) AutoLayer(isSign func(*Event) bool) {
+	if isSign == nil {
+		isSign = as.IsSign
+	}
+	var dialogue, signs []*Event
+	for _, evt := range as.Events {
+		if evt == nil {
+			continue
+		}
+		if isSign(evt) {
+			signs = append(signs, evt)
+		} else {
+			dialogue = append(dialogue, evt)
+		}
+	}
+	renumberLayers(signs, renumberLayers(dialogue, 0))
+}
@@ -0,0 +1,81 @@
+package ass
+
+import "testing"
+
+func TestLayers(t *testing.T) {
+	newSub := func() *Subtitle {
+		return &Subtitle{Events: []*Event{
+			{Layer: 5, Style: "Default", Text: "dialogue"},
+			{Layer: 2, Style: "Default", Text: `{\an8}top`},
+			{Layer: 0, Style: "Signs", Text: `{\pos(10,20)}EXIT`},
+			{Layer: 9, Style: "Signs", Text: `{\p1}m 0 0 l 10 0 10 10{\p0}`},
+			nil,
+			{Layer: 5, Style: "Default", Text: "more dialogue"},
+		}}
+	}
+	layers := func(sub *Subtitle) []int {
+		var l []int
+		for _, evt := range sub.Events {
+			if evt != nil {
+				l = append(l, evt.Layer)
+			}
+		}
+		return l
+	}
+	equal := func(a, b []int) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	sub := newSub()
+	sub.NormalizeLayers()
+	if expect := []int{2, 1, 0, 3, 2}; !equal(layers(sub), expect) {
+		t.Errorf("NormalizeLayers: expect %v, got: %v", expect, layers(sub))
+	}
+
+	sub = newSub()
+	sub.AutoLayer(nil)
+	if expect := []int{0, 2, 1, 3, 0}; !equal(layers(sub), expect) {
+		t.Errorf("AutoLayer: expect %v, got: %v", expect, layers(sub))
+	}
+
+	sub = newSub()
+	sub.AutoLayer(func(evt *Event) bool { return evt.Style == "Signs" })
+	if expect := []int{1, 0, 2, 3, 1}; !equal(layers(sub), expect) {
+		t.Errorf("AutoLayer by style: expect %v, got: %v", expect, layers(sub))
+	}
+
+	sub = newSub()
+	sub.Select().ByStyle("Signs").SetLayer(7)
+	if expect := []int{5, 2, 7, 7, 5}; !equal(layers(sub), expect) {
+		t.Errorf("SetLayer: expect %v, got: %v", expect, layers(sub))
+	}
+}
+
+func TestIsSignStyle(t *testing.T) {
+	sub := &Subtitle{Styles: []*Style{{Name: "Top", Alignment: 8}, {Name: "Default"}}}
+	cases := []struct {
+		evt    *Event
+		expect bool
+	}{
+		{&Event{Style: "Top", Text: "top"}, true},
+		{&Event{Style: "Top", Text: `{\an2}bottom`}, false},
+		{&Event{Style: "Default", Text: "bottom"}, false},
+		{&Event{Style: "Unknown", Text: "bottom"}, false},
+	}
+	for _, c := range cases {
+		if got := sub.IsSign(c.evt); got != c.expect {
+			t.Errorf("Expect %v for %+v, got: %v", c.expect, *c.evt, got)
+		}
+	}
+	if IsSign(&Event{Style: "Top", Text: "top"}) {
+		t.Errorf("Expect the style taken as bottom aligned without the subtitle")
+	}
+}
@@ -0,0 +1,191 @@
+package ass
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LazySubtitle is a subtitle whose sections are parsed on first access,
+// see ParseLazy. It is not safe for concurrent use.
+type LazySubtitle struct {
+	data     []byte
+	options  parseOptions
+	sections []lazySection
+	// parsed are the sections parsed so far, by kind, or raw section index
+	parsed  map[string]*Subtitle
+	charset string
+}
+
+// lazySection is a section of the data of a LazySubtitle
+type lazySection struct {
+	name       string
+	start, end int // offsets of the section, header included
+	line       int // line number of the header
+}
+
+// kind returns the kind of the section: script info, styles, events or
+// raw for the sections not understood
+func (s lazySection) kind() string {
+	switch name := strings.ToLower(s.name); name {
+	case "script info", "events":
+		return name
+	case "v4+ styles", "v4 styles":
+		return "styles"
+	}
+	return "raw"
+}
+
+// ParseLazy reads the input like Parse, but only finds where its sections
+// are. A section is parsed the first time it is accessed, so reading the
+// styles of a huge file doesn't parse its events, nor the fonts embedded.
+// The options apply as for ParseWithOptions.
+func ParseLazy(r io.Reader, opts ...ParseOption) (*LazySubtitle, error) {
+	r, err := decompress(r, ".ass", ".ssa")
+	if err != nil {
+		return nil, err
+	}
+	decoded, charset, err := DetectEncoding(r)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	ls := &LazySubtitle{data: data, parsed: make(map[string]*Subtitle), charset: charset}
+	for _, opt := range opts {
+		opt(&ls.options)
+	}
+	line := 0
+	for off := 0; off < len(data); {
+		end := len(data)
+		if i := bytes.IndexByte(data[off:], '\n'); i >= 0 {
+			end = off + i + 1
+		}
+		line++
+		if header := bytes.TrimSpace(data[off:end]); len(header) >= 2 && header[0] == '[' && header[len(header)-1] == ']' {
+			if n := len(ls.sections); n > 0 {
+				ls.sections[n-1].end = off
+			}
+			ls.sections = append(ls.sections, lazySection{name: string(header[1 : len(header)-1]), start: off, line: line})
+		}
+		off = end
+	}
+	if n := len(ls.sections); n > 0 {
+		ls.sections[n-1].end = len(data)
+	}
+	return ls, nil
+}
+
+// parse parses the sections matching, under key, once
+func (ls *LazySubtitle) parse(key string, match func(i int, s lazySection) bool) (*Subtitle, error) {
+	if sub, ok := ls.parsed[key]; ok {
+		return sub, nil
+	}
+	p := newParser(ls.options)
+	p.sub.charset = ls.charset
+	for i, s := range ls.sections {
+		if !match(i, s) {
+			continue
+		}
+		p.lineNo = s.line - 1
+		if err := p.run(context.Background(), bytes.NewReader(ls.data[s.start:s.end])); err != nil {
+			return nil, err
+		}
+	}
+	ls.parsed[key] = p.sub
+	return p.sub, nil
+}
+
+// parseKind parses the sections of a kind
+func (ls *LazySubtitle) parseKind(kind string) (*Subtitle, error) {
+	return ls.parse(kind, func(_ int, s lazySection) bool { return s.kind() == kind })
+}
+
+// SectionNames returns the names of the sections, in order
+func (ls *LazySubtitle) SectionNames() []string {
+	names := make([]string, len(ls.sections))
+	for i, s := range ls.sections {
+		names[i] = s.name
+	}
+	return names
+}
+
+// Info returns the script info, in a subtitle without styles or events
+func (ls *LazySubtitle) Info() (*Subtitle, error) {
+	return ls.parseKind("script info")
+}
+
+// Styles returns the styles
+func (ls *LazySubtitle) Styles() ([]*Style, error) {
+	sub, err := ls.parseKind("styles")
+	if err != nil {
+		return nil, err
+	}
+	return sub.Styles, nil
+}
+
+// Events returns the events
+func (ls *LazySubtitle) Events() ([]*Event, error) {
+	sub, err := ls.parseKind("events")
+	if err != nil {
+		return nil, err
+	}
+	return sub.Events, nil
+}
+
+// RawSection returns the first section not understood named name, ignoring
+// the case, or nil if there is none
+func (ls *LazySubtitle) RawSection(name string) (*RawSection, error) {
+	for i, s := range ls.sections {
+		if s.kind() == "raw" && strings.EqualFold(s.name, name) {
+			return ls.rawSection(i)
+		}
+	}
+	return nil, nil
+}
+
+func (ls *LazySubtitle) rawSection(index int) (*RawSection, error) {
+	sub, err := ls.parse("raw "+strconv.Itoa(index), func(i int, _ lazySection) bool { return i == index })
+	if err != nil {
+		return nil, err
+	}
+	return sub.RawSections[0], nil
+}
+
+// Subtitle parses the sections not accessed yet and returns the whole
+// subtitle, the same as Parse would. Its styles, events and raw sections
+// are those returned by the other methods, not copies.
+func (ls *LazySubtitle) Subtitle() (*Subtitle, error) {
+	info, err := ls.Info()
+	if err != nil {
+		return nil, err
+	}
+	styles, err := ls.parseKind("styles")
+	if err != nil {
+		return nil, err
+	}
+	events, err := ls.parseKind("events")
+	if err != nil {
+		return nil, err
+	}
+
+	sub := *info
+	sub.Styles, sub.Events = styles.Styles, events.Events
+	sub.lines = &sourceLines{info: info.lines.info, styles: styles.lines.styles, events: events.lines.events}
+	for i, s := range ls.sections {
+		if s.kind() != "raw" {
+			continue
+		}
+		raw, err := ls.rawSection(i)
+		if err != nil {
+			return nil, err
+		}
+		sub.RawSections = append(sub.RawSections, raw)
+	}
+	return &sub, nil
+}
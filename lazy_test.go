@@ -0,0 +1,103 @@
+package ass
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const lazyScript = `[Script Info]
+Title: Lazy
+PlayResX: 1280
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize
+Style: Default,Arial,20
+
+[Events]
+Format: Layer, Start, End, Style, Text
+Dialogue: 0,0:00:01.00,0:00:02.00,Default,one
+Comment: 0,0:00:02.00,0:00:03.00,Default,note
+
+[Fonts]
+fontname: a.ttf
+M4I2
+
+[Aegisub Project Garbage]
+Video File: a.mkv
+`
+
+func TestParseLazy(t *testing.T) {
+	ls, err := ParseLazy(strings.NewReader(lazyScript))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{"Script Info", "V4+ Styles", "Events", "Fonts", "Aegisub Project Garbage"}
+	if names := ls.SectionNames(); !reflect.DeepEqual(names, expect) {
+		t.Errorf("Expect %v, got: %v", expect, names)
+	}
+
+	styles, err := ls.Styles()
+	if err != nil || len(styles) != 1 || styles[0].FontSize != 20 {
+		t.Errorf("Expect the style, got: %v, %v", styles, err)
+	}
+	if len(ls.parsed) != 1 {
+		t.Errorf("Expect only the styles parsed, got: %v", ls.parsed)
+	}
+	fonts, err := ls.RawSection("fonts")
+	if err != nil || fonts == nil || !reflect.DeepEqual(fonts.Lines, []string{"fontname: a.ttf", "M4I2"}) {
+		t.Errorf("Expect the fonts section, got: %v, %v", fonts, err)
+	}
+	if raw, err := ls.RawSection("Graphics"); raw != nil || err != nil {
+		t.Errorf("Expect no section, got: %v, %v", raw, err)
+	}
+
+	sub, err := ls.Subtitle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _ := Parse(strings.NewReader(lazyScript))
+	var a, b bytes.Buffer
+	sub.WriteTo(&a)
+	parsed.WriteTo(&b)
+	if a.String() != b.String() {
+		t.Errorf("Expect the same as Parse:\n%s\ngot:\n%s", b.String(), a.String())
+	}
+	if sub.Styles[0] != styles[0] || sub.RawSections[0] != fonts {
+		t.Errorf("Expect the sections already parsed to be reused")
+	}
+	if sub.InfoLine("Title") != 2 || sub.StyleLine(sub.Styles[0]) != 7 || sub.EventLine(sub.Events[1]) != 12 {
+		t.Errorf("Expect the source lines, got: %d, %d, %d", sub.InfoLine("Title"), sub.StyleLine(sub.Styles[0]), sub.EventLine(sub.Events[1]))
+	}
+}
+
+func TestParseLazyError(t *testing.T) {
+	script := strings.Replace(lazyScript, "Dialogue: 0,", "Dialogue: x,", 1)
+	ls, err := ParseLazy(strings.NewReader(script))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ls.Styles(); err != nil {
+		t.Errorf("Expect the styles parsed, got: %v", err)
+	}
+	if _, err := ls.Subtitle(); err == nil || err.Error() != "Line 11: Invalid event layer: x" {
+		t.Errorf("Expect event error, got: %v", err)
+	}
+}
+
+func BenchmarkParseLazyStyles(b *testing.B) {
+	sub := newBenchSubtitle(200000)
+	var buf bytes.Buffer
+	sub.WriteTo(&buf)
+	data := buf.Bytes()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ls, err := ParseLazy(bytes.NewReader(data))
+		if err != nil {
+			b.Fatal(err)
+		}
+		ls.Styles()
+	}
+}
@@ -0,0 +1,7 @@
+// Package libass checks the scripts written by package ass with libass, the
+// renderer of most players, to catch what the library accepts but libass
+// rejects. It needs cgo, the libass development files and the libass build
+// tag:
+//
+//	go test -tags libass ./libass
+package libass
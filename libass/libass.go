@@ -0,0 +1,164 @@
+//go:build libass
+// +build libass
+
+package libass
+
+/*
+#cgo pkg-config: libass
+#include <stdint.h>
+#include <stdlib.h>
+#include <ass/ass.h>
+
+void set_message_cb(ASS_Library *lib, uintptr_t id);
+*/
+import "C"
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"unsafe"
+
+	"github.com/apigo/ass"
+)
+
+// Levels of the libass messages
+const (
+	LevelFatal = 0
+	LevelError = 1
+	LevelWarn  = 2
+	LevelInfo  = 4
+)
+
+// Message is a message logged by libass
+type Message struct {
+	Level int
+	Text  string
+	// Index is the index of the event starting at the frame being rendered
+	// when the message was logged, -1 while loading the script
+	Index int
+}
+
+// the messages of the running checks, by id, as the libass callback can't
+// carry Go pointers
+var (
+	mu       sync.Mutex
+	nextID   uintptr
+	messages = make(map[uintptr]*[]Message)
+	current  = make(map[uintptr]int)
+)
+
+//export goMessage
+func goMessage(level C.int, msg *C.char, id C.uintptr_t) {
+	if int(level) > LevelWarn {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if list, ok := messages[uintptr(id)]; ok {
+		text := string(bytes.TrimRight([]byte(C.GoString(msg)), "\n"))
+		*list = append(*list, Message{Level: int(level), Text: text, Index: current[uintptr(id)]})
+	}
+}
+
+// Check writes the subtitle, loads it in libass and renders a frame at the
+// start of each event, to collect the errors and warnings of libass
+func Check(sub *ass.Subtitle) ([]Message, error) {
+	var script bytes.Buffer
+	if _, err := sub.WriteTo(&script); err != nil {
+		return nil, err
+	}
+
+	var list []Message
+	mu.Lock()
+	nextID++
+	id := nextID
+	messages[id], current[id] = &list, -1
+	mu.Unlock()
+	defer func() {
+		mu.Lock()
+		delete(messages, id)
+		delete(current, id)
+		mu.Unlock()
+	}()
+
+	lib := C.ass_library_init()
+	if lib == nil {
+		return nil, fmt.Errorf("Cannot initialize libass")
+	}
+	defer C.ass_library_done(lib)
+	C.set_message_cb(lib, C.uintptr_t(id))
+
+	data := C.CBytes(script.Bytes())
+	defer C.free(data)
+	track := C.ass_read_memory(lib, (*C.char)(data), C.size_t(script.Len()), nil)
+	if track == nil {
+		return list, fmt.Errorf("libass cannot read the script")
+	}
+	defer C.ass_free_track(track)
+
+	renderer := C.ass_renderer_init(lib)
+	if renderer == nil {
+		return nil, fmt.Errorf("Cannot initialize the libass renderer")
+	}
+	defer C.ass_renderer_done(renderer)
+	width, height := sub.PlayRes()
+	C.ass_set_frame_size(renderer, C.int(width), C.int(height))
+	family := C.CString("sans-serif")
+	defer C.free(unsafe.Pointer(family))
+	C.ass_set_fonts(renderer, nil, family, C.ASS_FONTPROVIDER_AUTODETECT, nil, 1)
+
+	// the first event of each start time
+	starts := make(map[int64]int)
+	for i, evt := range sub.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		start, err := evt.StartTime()
+		if err != nil {
+			return nil, err
+		}
+		ms := start.Duration().Milliseconds()
+		if _, ok := starts[ms]; !ok {
+			starts[ms] = i
+		}
+	}
+	times := make([]int64, 0, len(starts))
+	for ms := range starts {
+		times = append(times, ms)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	for _, ms := range times {
+		mu.Lock()
+		current[id] = starts[ms]
+		mu.Unlock()
+		var changed C.int
+		C.ass_render_frame(renderer, track, C.longlong(ms), &changed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return list, nil
+}
+
+// Rule is a validation rule reporting the libass errors as errors and its
+// warnings as warnings
+func Rule(sub *ass.Subtitle) []ass.Issue {
+	list, err := Check(sub)
+	if err != nil {
+		return []ass.Issue{{Severity: ass.SeverityError, Rule: "libass", Index: -1, Message: err.Error()}}
+	}
+	issues := make([]ass.Issue, 0, len(list))
+	for _, msg := range list {
+		issue := ass.Issue{Severity: ass.SeverityWarning, Rule: "libass", Index: msg.Index, Message: msg.Text}
+		if msg.Level <= LevelError {
+			issue.Severity = ass.SeverityError
+		}
+		if msg.Index >= 0 {
+			issue.Section = "Events"
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
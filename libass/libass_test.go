@@ -0,0 +1,38 @@
+//go:build libass
+// +build libass
+
+package libass
+
+import (
+	"testing"
+
+	"github.com/apigo/ass"
+)
+
+func TestCheck(t *testing.T) {
+	sub := &ass.Subtitle{
+		PlayerWidth:  640,
+		PlayerHeight: 360,
+		Styles:       []*ass.Style{{Name: "Default", FontSize: 20}},
+		Events: []*ass.Event{
+			{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "Hello"},
+			{Start: "0:00:03.00", End: "0:00:04.00", Style: "Default", Text: `{\pos(10,10)}World`},
+		},
+	}
+	list, err := Check(sub)
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	for _, msg := range list {
+		if msg.Level <= LevelError {
+			t.Errorf("Expect no libass error, got: %v", msg)
+		}
+	}
+
+	v := ass.NewValidator(ass.Standard).AddRule(Rule)
+	for _, issue := range v.Check(sub) {
+		if issue.Rule == "libass" && issue.Severity == ass.SeverityError {
+			t.Errorf("Expect no libass error, got: %v", issue)
+		}
+	}
+}
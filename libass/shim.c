@@ -0,0 +1,19 @@
+//go:build libass
+// +build libass
+
+#include <stdarg.h>
+#include <stdint.h>
+#include <stdio.h>
+#include <ass/ass.h>
+
+void goMessage(int level, char *msg, uintptr_t id);
+
+static void message_cb(int level, const char *fmt, va_list args, void *data) {
+	char msg[1024];
+	vsnprintf(msg, sizeof(msg), fmt, args);
+	goMessage(level, msg, (uintptr_t)data);
+}
+
+void set_message_cb(ASS_Library *lib, uintptr_t id) {
+	ass_set_message_cb(lib, message_cb, (void *)id);
+}
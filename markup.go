@@ -0,0 +1,161 @@
+package ass
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var htmlTagReg = regexp.MustCompile(`<(/?)([a-zA-Z]+)[^>]*>`)
+
+var htmlSpaceReplacer = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// htmlTags maps the HTML elements to the override tags they become
+var htmlTags = map[string]string{
+	"i": "i", "em": "i",
+	"b": "b", "strong": "b",
+	"u": "u", "ins": "u",
+	"s": "s", "del": "s", "strike": "s",
+}
+
+// HTMLToText converts a small subset of HTML, e.g. from a CMS, to dialogue
+// text: <i>, <em>, <b>, <strong>, <u>, <s> and <del> become override tags
+// and <br> a line break, the other elements are dropped and the entities
+// decoded. Line breaks in the HTML are spaces.
+func HTMLToText(s string) string {
+	var b strings.Builder
+	plain := func(text string) {
+		b.WriteString(EscapeText(html.UnescapeString(htmlSpaceReplacer.Replace(text))))
+	}
+	last := 0
+	for _, m := range htmlTagReg.FindAllStringSubmatchIndex(s, -1) {
+		plain(s[last:m[0]])
+		last = m[1]
+		closing, name := s[m[2]:m[3]] == "/", strings.ToLower(s[m[4]:m[5]])
+		if name == "br" {
+			b.WriteString(`\N`)
+			continue
+		}
+		tag, ok := htmlTags[name]
+		if !ok {
+			continue
+		}
+		if closing {
+			b.WriteString(`{\` + tag + `0}`)
+		} else {
+			b.WriteString(`{\` + tag + `1}`)
+		}
+	}
+	plain(s[last:])
+	return b.String()
+}
+
+// TextToHTML converts dialogue text to HTML: italic, bold and underline
+// become <i>, <b> and <u>, line breaks <br>, the other tags are dropped
+func TextToHTML(text string) string {
+	return strings.Replace(markupText(text, true), "\n", "<br>", -1)
+}
+
+// MarkdownToText converts a small subset of Markdown, e.g. from a chat
+// system, to dialogue text: **bold**, __bold__, _italic_ and *italic*
+// become override tags and line breaks \N. Underscores inside words, like
+// in snake_case, and backslash escaped characters are kept as they are.
+func MarkdownToText(s string) string {
+	// the pieces of text, so that the markers left open can be written back
+	var out []string
+	runes := []rune(strings.Replace(s, "\r\n", "\n", -1))
+	bold, italic := "", ""
+	boldAt, italicAt := 0, 0
+	isWord := func(i int) bool {
+		return i >= 0 && i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]))
+	}
+	isSpace := func(i int) bool {
+		return i < 0 || i >= len(runes) || unicode.IsSpace(runes[i])
+	}
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\\' && i+1 < len(runes) && strings.ContainsRune(`\*_`, runes[i+1]) {
+			out = append(out, EscapeText(string(runes[i+1])))
+			i++
+			continue
+		}
+		if r != '*' && r != '_' {
+			out = append(out, EscapeText(string(r)))
+			continue
+		}
+		double := i+1 < len(runes) && runes[i+1] == r
+		marker, n := string(r), 1
+		if double {
+			marker, n = marker+marker, 2
+		}
+		// a marker opens before a word and closes after one, the
+		// underscores only at the word boundaries
+		opens := !isSpace(i+n) && (r == '*' || !isWord(i-1))
+		closes := !isSpace(i-1) && (r == '*' || !isWord(i+n))
+		switch {
+		case double && bold == marker && closes:
+			out = append(out, `{\b0}`)
+			bold = ""
+		case double && bold == "" && opens:
+			boldAt, bold = len(out), marker
+			out = append(out, `{\b1}`)
+		case !double && italic == marker && closes:
+			out = append(out, `{\i0}`)
+			italic = ""
+		case !double && italic == "" && opens:
+			italicAt, italic = len(out), marker
+			out = append(out, `{\i1}`)
+		default:
+			out = append(out, marker)
+		}
+		i += n - 1
+	}
+	if bold != "" {
+		out[boldAt] = bold
+	}
+	if italic != "" {
+		out[italicAt] = italic
+	}
+	return strings.Join(out, "")
+}
+
+var markdownReplacer = strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, "\u00a0",
+	`*`, `\*`, `_`, `\_`)
+
+// TextToMarkdown converts dialogue text to Markdown: italic becomes _,
+// bold **, line breaks new lines, the other tags are dropped and the
+// Markdown markers in the text escaped
+func TextToMarkdown(text string) string {
+	var b strings.Builder
+	open := map[byte]bool{}
+	markers := map[byte]string{'i': "_", 'b': "**"}
+	for _, p := range splitText(text) {
+		if p.Override {
+			for _, tag := range splitTags(p.Text) {
+				for _, c := range []byte("ib") {
+					switch tag {
+					case `\` + string(c) + "1":
+						if !open[c] {
+							b.WriteString(markers[c])
+							open[c] = true
+						}
+					case `\` + string(c) + "0", `\` + string(c):
+						if open[c] {
+							b.WriteString(markers[c])
+							open[c] = false
+						}
+					}
+				}
+			}
+			continue
+		}
+		b.WriteString(markdownReplacer.Replace(p.Text))
+	}
+	for _, c := range []byte("bi") {
+		if open[c] {
+			b.WriteString(markers[c])
+		}
+	}
+	return b.String()
+}
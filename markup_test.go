@@ -0,0 +1,74 @@
+package ass
+
+import "testing"
+
+func TestHTMLToText(t *testing.T) {
+	cases := []struct {
+		html   string
+		expect string
+	}{
+		{"<i>Hello</i>, <B>world</B>", `{\i1}Hello{\i0}, {\b1}world{\b0}`},
+		{`<em>a</em><strong class="x">b</strong><u>c</u><del>d</del>`, `{\i1}a{\i0}{\b1}b{\b0}{\u1}c{\u0}{\s1}d{\s0}`},
+		{"Line one<br/>line\ntwo", `Line one\Nline two`},
+		{`<span style="color:red">Tom &amp; Jerry &lt;3</span>`, `Tom & Jerry <3`},
+		{"{not a tag}", `\{not a tag\}`},
+	}
+	for _, c := range cases {
+		if got := HTMLToText(c.html); got != c.expect {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+	}
+}
+
+func TestTextToHTML(t *testing.T) {
+	cases := []struct {
+		text   string
+		expect string
+	}{
+		{`{\i1}Hello{\i0}, {\b1}world`, "<i>Hello</i>, <b>world</b>"},
+		{`{\pos(1,2)}Tom & Jerry\N<3`, "Tom &amp; Jerry<br>&lt;3"},
+	}
+	for _, c := range cases {
+		if got := TextToHTML(c.text); got != c.expect {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+	}
+}
+
+func TestMarkdownToText(t *testing.T) {
+	cases := []struct {
+		md     string
+		expect string
+	}{
+		{"**Hello** _world_", `{\b1}Hello{\b0} {\i1}world{\i0}`},
+		{"__bold__ and *italic*", `{\b1}bold{\b0} and {\i1}italic{\i0}`},
+		{"snake_case_name", "snake_case_name"},
+		{"2 * 3 * 4", "2 * 3 * 4"},
+		{`\*not\* {x}`, `*not* \{x\}`},
+		{"_a **b**_\nnext", `{\i1}a {\b1}b{\b0}{\i0}\Nnext`},
+		{"un**closed", "un**closed"},
+	}
+	for _, c := range cases {
+		if got := MarkdownToText(c.md); got != c.expect {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+	}
+}
+
+func TestTextToMarkdown(t *testing.T) {
+	cases := []struct {
+		text   string
+		expect string
+	}{
+		{`{\b1}Hello{\b0} {\i1}world`, "**Hello** _world_"},
+		{`{\u1}snake_case{\u0} 2*3\Nnext`, "snake\\_case 2\\*3\nnext"},
+	}
+	for _, c := range cases {
+		if got := TextToMarkdown(c.text); got != c.expect {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+		if back := MarkdownToText(TextToMarkdown(c.text)); TextToMarkdown(back) != c.expect {
+			t.Errorf("Expect %q to round trip, got: %q", c.expect, back)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package ass
+
+import (
+	"strings"
+
+	"golang.org/x/text/width"
+)
+
+// defaultFontSize is the font size of the text without a style
+const defaultFontSize = 20
+
+// TextMeasurer measures a line of plain text, without tags or line breaks,
+// rendered in a style, in script pixels. The style is nil for the default
+// style. Implement it with a font shaper for exact sizes.
+type TextMeasurer interface {
+	Measure(text string, style *Style) (width, height float64)
+}
+
+// TextMeasurerFunc is a function implementing TextMeasurer
+type TextMeasurerFunc func(text string, style *Style) (width, height float64)
+
+// Measure calls f
+func (f TextMeasurerFunc) Measure(text string, style *Style) (width, height float64) {
+	return f(text, style)
+}
+
+// ApproxMeasurer estimates the size of text from the font size and scales
+// of the style, without any font: wide and fullwidth characters are one em
+// wide, narrow punctuation and letters a third of an em, bidi controls and
+// combining marks nothing and the others about half an em. The height is
+// the font size.
+type ApproxMeasurer struct{}
+
+// Measure implements TextMeasurer
+func (ApproxMeasurer) Measure(text string, style *Style) (w, h float64) {
+	size, scaleX, scaleY := float64(defaultFontSize), 1.0, 1.0
+	if style != nil {
+		if style.FontSize > 0 {
+			size = float64(style.FontSize)
+		}
+		if style.ScaleX > 0 {
+			scaleX = float64(style.ScaleX) / 100
+		}
+		if style.ScaleY > 0 {
+			scaleY = float64(style.ScaleY) / 100
+		}
+	}
+	ems := 0.0
+	for _, r := range text {
+		switch kind := width.LookupRune(r).Kind(); {
+		case isZeroWidth(r):
+		case kind == width.EastAsianWide || kind == width.EastAsianFullwidth:
+			ems++
+		case strings.ContainsRune(" .,:;'!|iIjlft()[]", r):
+			ems += 0.33
+		case strings.ContainsRune("MWmw@", r):
+			ems += 0.85
+		default:
+			ems += 0.55
+		}
+	}
+	return ems * size * scaleX, size * scaleY
+}
+
+// MeasureText measures dialogue text with m: tags are left out and each \N
+// starts a new line, the width is the widest line and the height the sum
+func MeasureText(m TextMeasurer, text string, style *Style) (w, h float64) {
+	for _, line := range strings.Split(text, `\N`) {
+		line = strings.Replace(StripTags(line), `\h`, " ", -1)
+		lw, lh := m.Measure(strings.Replace(line, `\n`, " ", -1), style)
+		if lw > w {
+			w = lw
+		}
+		h += lh
+	}
+	return w, h
+}
+
+// widthFunc returns the width of plain text in style as measured by m
+func widthFunc(m TextMeasurer, style *Style) func(string) float64 {
+	return func(text string) float64 {
+		w, _ := m.Measure(text, style)
+		return w
+	}
+}
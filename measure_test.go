@@ -0,0 +1,53 @@
+package ass
+
+import "testing"
+
+func TestApproxMeasurer(t *testing.T) {
+	cases := []struct {
+		text  string
+		style *Style
+		w, h  float64
+	}{
+		{"", nil, 0, 20},
+		{"ab", nil, 22, 20},
+		{"日本", &Style{FontSize: 40}, 80, 40},
+		{"ab", &Style{FontSize: 10, ScaleX: 200, ScaleY: 50}, 22, 5},
+	}
+	for _, c := range cases {
+		w, h := ApproxMeasurer{}.Measure(c.text, c.style)
+		if w != c.w || h != c.h {
+			t.Errorf("Expect %v x %v for %q, got: %v x %v", c.w, c.h, c.text, w, h)
+		}
+	}
+}
+
+func TestMeasureText(t *testing.T) {
+	count := TextMeasurerFunc(func(text string, style *Style) (float64, float64) {
+		return countRunes(text), 10
+	})
+	w, h := MeasureText(count, `{\b1}abc\Nab\hcd`, nil)
+	if w != 5 || h != 20 {
+		t.Errorf("Expect 5 x 20, got: %v x %v", w, h)
+	}
+}
+
+func TestWrapMeasurer(t *testing.T) {
+	sub := &Subtitle{
+		Styles: []*Style{{Name: "Big", FontSize: 40}},
+		Events: []*Event{
+			{Style: "Default", Text: "aaaa bbbb"},
+			{Style: "Big", Text: "aaaa bbbb"},
+		},
+	}
+	sub.WrapLines(WrapOptions{Width: 100, Measurer: ApproxMeasurer{}})
+	expects := []string{"aaaa bbbb", `aaaa\Nbbbb`}
+	for i, expect := range expects {
+		if sub.Events[i].Text != expect {
+			t.Errorf("Expect %q, got: %q", expect, sub.Events[i].Text)
+		}
+	}
+
+	if text := Wrap("aaaa bbbb", WrapOptions{Width: 60, Measurer: ApproxMeasurer{}}); text != `aaaa\Nbbbb` {
+		t.Errorf("Expect a break, got: %q", text)
+	}
+}
@@ -0,0 +1,183 @@
+package ass
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MergeOptions configures Merge
+type MergeOptions struct {
+	// StyleSuffix is appended to the names of colliding styles of b,
+	// followed by a number if still colliding. _b by default.
+	StyleSuffix string
+	// SortEvents sorts the merged events by start time, otherwise the
+	// events of b follow those of a
+	SortEvents bool
+}
+
+// Merge combines two subtitles, e.g. a dialogue track and a separately made
+// signs track, into a new one. The script info comes from a. Styles of b
+// with the name of a different style of a are renamed, along with the
+// references to them in the events of b, \r tags included; identical styles
+// are shared. Raw sections, e.g. [Fonts], are merged by name, the lines of
+// b following those of a unless the sections are identical. Styles, events
+// and raw sections are copied, a and b are left untouched.
+func Merge(a, b *Subtitle, opts MergeOptions) *Subtitle {
+	if opts.StyleSuffix == "" {
+		opts.StyleSuffix = "_b"
+	}
+
+	merged := *a
+	merged.index = nil
+	merged.Styles = copyStyles(a.Styles)
+	merged.Events = copyEvents(a.Events)
+	merged.RawSections = copyRawSections(a.RawSections)
+
+	renames := merged.addStyles(b.Styles, opts.StyleSuffix)
+	for _, evt := range copyEvents(b.Events) {
+		if evt != nil {
+			evt.renameStyles(renames)
+		}
+		merged.Events = append(merged.Events, evt)
+	}
+
+	merged.addRawSections(b.RawSections)
+
+	if opts.SortEvents {
+		merged.Events = sortedEvents(merged.Events)
+	}
+	return &merged
+}
+
+// addRawSections adds copies of sections, appending the lines of those
+// already there under the same name unless identical
+func (as *Subtitle) addRawSections(sections []*RawSection) {
+	for _, raw := range sections {
+		if raw == nil {
+			continue
+		}
+		existing := as.rawSection(raw.Name)
+		switch {
+		case existing == nil:
+			as.RawSections = append(as.RawSections, &RawSection{Name: raw.Name, Lines: append([]string(nil), raw.Lines...)})
+		case !reflect.DeepEqual(existing.Lines, raw.Lines):
+			existing.Lines = append(existing.Lines, raw.Lines...)
+		}
+	}
+}
+
+// rawSection returns the raw section named name, nil if none
+func (as *Subtitle) rawSection(name string) *RawSection {
+	for _, raw := range as.RawSections {
+		if raw != nil && raw.Name == name {
+			return raw
+		}
+	}
+	return nil
+}
+
+// addStyles adds copies of styles which are not already there. Styles
+// colliding with a different style of the same name are renamed with the
+// suffix, the returned map holds the renames.
+func (as *Subtitle) addStyles(styles []*Style, suffix string) map[string]string {
+	renames := make(map[string]string)
+	for _, style := range styles {
+		if style == nil {
+			continue
+		}
+		existing := as.styleByName(style.Name)
+		if existing != nil && *existing == *style {
+			continue
+		}
+		copied := *style
+		if existing != nil {
+			name := style.Name + suffix
+			for i := 2; as.styleByName(name) != nil; i++ {
+				name = style.Name + suffix + strconv.Itoa(i)
+			}
+			renames[style.Name] = name
+			copied.Name = name
+		}
+		as.Styles = append(as.Styles, &copied)
+	}
+	return renames
+}
+
+// renameStyles updates the style of the event and its \r tags
+func (evt *Event) renameStyles(renames map[string]string) {
+	if len(renames) == 0 {
+		return
+	}
+	if name, ok := renames[evt.Style]; ok {
+		evt.Style = name
+	}
+	evt.Text = renameResets(evt.Text, renames)
+}
+
+// renameResets renames the styles referenced by \r tags
+func renameResets(text string, renames map[string]string) string {
+	if !strings.Contains(text, `\r`) {
+		return text
+	}
+	parts := splitText(text)
+	for i, p := range parts {
+		if !p.Override {
+			continue
+		}
+		tags := splitTags(p.Text)
+		for j, tag := range tags {
+			if !strings.HasPrefix(tag, `\r`) {
+				continue
+			}
+			if name, ok := renames[tag[2:]]; ok {
+				tags[j] = `\r` + name
+			}
+		}
+		// keep the comment before the first tag, if any
+		comment := p.Text[:strings.IndexByte(p.Text+`\`, '\\')]
+		parts[i].Text = comment + strings.Join(tags, "")
+	}
+	return joinText(parts)
+}
+
+func copyStyles(styles []*Style) []*Style {
+	if styles == nil {
+		return nil
+	}
+	copied := make([]*Style, len(styles))
+	for i, style := range styles {
+		if style != nil {
+			s := *style
+			copied[i] = &s
+		}
+	}
+	return copied
+}
+
+func copyRawSections(sections []*RawSection) []*RawSection {
+	if sections == nil {
+		return nil
+	}
+	copied := make([]*RawSection, len(sections))
+	for i, raw := range sections {
+		if raw != nil {
+			copied[i] = &RawSection{Name: raw.Name, Lines: append([]string(nil), raw.Lines...)}
+		}
+	}
+	return copied
+}
+
+func copyEvents(events []*Event) []*Event {
+	if events == nil {
+		return nil
+	}
+	copied := make([]*Event, len(events))
+	for i, evt := range events {
+		if evt != nil {
+			e := evt.clone()
+			copied[i] = &e
+		}
+	}
+	return copied
+}
@@ -0,0 +1,295 @@
+package ass
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Conflict is a change made differently by both sides of a three-way merge.
+// The merged subtitle keeps our side of a conflict, or the modified side when
+// the other side removed the item.
+type Conflict struct {
+	// Section is Styles, Events or RawSections, empty for the script info
+	Section string `json:"section,omitempty"`
+	// Index is the index of the item in the base subtitle, -1 for the script
+	// info and items added by both sides
+	Index int `json:"index"`
+	// Field is the conflicting field, empty when one side removed the item
+	// the other side modified
+	Field  string `json:"field,omitempty"`
+	Base   string `json:"base"`
+	Ours   string `json:"ours"`
+	Theirs string `json:"theirs"`
+}
+
+func (c Conflict) String() string {
+	where := "Script Info"
+	if c.Section != "" {
+		where = fmt.Sprintf("%s[%d]", c.Section, c.Index)
+	}
+	if c.Field == "" {
+		return fmt.Sprintf("%s: removed on one side, modified on the other", where)
+	}
+	return fmt.Sprintf("%s.%s: base %q, ours %q, theirs %q", where, c.Field, c.Base, c.Ours, c.Theirs)
+}
+
+// Merge3 merges the changes made to base in ours and in theirs. Script info
+// and style fields, styles and raw sections matched by name and events
+// aligned like Diff does are merged field by field, the events added on both
+// sides are kept in place. The fields changed differently on both sides are
+// reported as conflicts.
+func Merge3(base, ours, theirs *Subtitle) (*Subtitle, []Conflict) {
+	m := &merger3{}
+	merged := &Subtitle{}
+	m.fields(merged, base, ours, theirs, "", -1)
+	merged.Styles = m.styles(base.Styles, ours.Styles, theirs.Styles)
+	merged.Events = m.events(base.Events, ours.Events, theirs.Events)
+	merged.RawSections = m.rawSections(base.RawSections, ours.RawSections, theirs.RawSections)
+	return merged, m.conflicts
+}
+
+type merger3 struct {
+	conflicts []Conflict
+}
+
+// fields sets the exported fields of dst, a pointer to a struct, from the
+// three versions of it
+func (m *merger3) fields(dst, base, ours, theirs interface{}, section string, index int) {
+	vd := reflect.ValueOf(dst).Elem()
+	vb, vo, vt := reflect.ValueOf(base).Elem(), reflect.ValueOf(ours).Elem(), reflect.ValueOf(theirs).Elem()
+	for i := 0; i < vd.NumField(); i++ {
+		field := vd.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Ptr {
+			continue
+		}
+		b, o, t := vb.Field(i).Interface(), vo.Field(i).Interface(), vt.Field(i).Interface()
+		switch {
+		case reflect.DeepEqual(b, o):
+			vd.Field(i).Set(vt.Field(i))
+		case reflect.DeepEqual(b, t), reflect.DeepEqual(o, t):
+			vd.Field(i).Set(vo.Field(i))
+		default:
+			vd.Field(i).Set(vo.Field(i))
+			m.conflicts = append(m.conflicts, Conflict{Section: section, Index: index, Field: field.Name,
+				Base: fmt.Sprint(b), Ours: fmt.Sprint(o), Theirs: fmt.Sprint(t)})
+		}
+	}
+}
+
+func stylesByName(styles []*Style) map[string]*Style {
+	byName := make(map[string]*Style, len(styles))
+	for _, style := range styles {
+		if style != nil {
+			byName[style.Name] = style
+		}
+	}
+	return byName
+}
+
+// styles merges the styles by name, in the order of base, then ours, then
+// theirs
+func (m *merger3) styles(base, ours, theirs []*Style) []*Style {
+	baseByName, oursByName, theirsByName := stylesByName(base), stylesByName(ours), stylesByName(theirs)
+	var merged []*Style
+	for i, b := range base {
+		if b == nil {
+			continue
+		}
+		o, t := oursByName[b.Name], theirsByName[b.Name]
+		if style := m.item("Styles", i, b, o, t); style != nil {
+			merged = append(merged, style.(*Style))
+		}
+	}
+	for _, o := range ours {
+		if o == nil || baseByName[o.Name] != nil {
+			continue
+		}
+		style := *o
+		if t := theirsByName[o.Name]; t != nil {
+			// added on both sides, ours wins
+			for _, f := range diffFields(o, t) {
+				m.conflicts = append(m.conflicts, Conflict{Section: "Styles", Index: -1, Field: f.Field, Ours: f.Old, Theirs: f.New})
+			}
+		}
+		merged = append(merged, &style)
+	}
+	for _, t := range theirs {
+		if t == nil || baseByName[t.Name] != nil || oursByName[t.Name] != nil {
+			continue
+		}
+		style := *t
+		merged = append(merged, &style)
+	}
+	return merged
+}
+
+func rawSectionsByName(sections []*RawSection) map[string]*RawSection {
+	byName := make(map[string]*RawSection, len(sections))
+	for _, s := range sections {
+		if s != nil {
+			byName[s.Name] = s
+		}
+	}
+	return byName
+}
+
+// rawSections merges the raw sections by name, in the order of base, then
+// ours, then theirs
+func (m *merger3) rawSections(base, ours, theirs []*RawSection) []*RawSection {
+	baseByName, oursByName, theirsByName := rawSectionsByName(base), rawSectionsByName(ours), rawSectionsByName(theirs)
+	var merged []*RawSection
+	for i, b := range base {
+		if b == nil {
+			continue
+		}
+		o, t := oursByName[b.Name], theirsByName[b.Name]
+		if s := m.item("RawSections", i, b, o, t); s != nil {
+			merged = append(merged, s.(*RawSection))
+		}
+	}
+	for _, o := range ours {
+		if o == nil || baseByName[o.Name] != nil {
+			continue
+		}
+		if t := theirsByName[o.Name]; t != nil && !reflect.DeepEqual(o.Lines, t.Lines) {
+			// added on both sides, ours wins
+			m.conflicts = append(m.conflicts, Conflict{Section: "RawSections", Index: -1, Field: "Lines",
+				Ours: fmt.Sprint(o.Lines), Theirs: fmt.Sprint(t.Lines)})
+		}
+		merged = append(merged, copyItem(o).(*RawSection))
+	}
+	for _, t := range theirs {
+		if t == nil || baseByName[t.Name] != nil || oursByName[t.Name] != nil {
+			continue
+		}
+		merged = append(merged, copyItem(t).(*RawSection))
+	}
+	return merged
+}
+
+// item merges a style or event of base, o and t being nil when removed on
+// that side. It returns nil when the item is removed.
+func (m *merger3) item(section string, index int, base, o, t interface{}) interface{} {
+	isNil := func(v interface{}) bool {
+		return reflect.ValueOf(v).IsNil()
+	}
+	modified := func(v interface{}) bool {
+		return !reflect.DeepEqual(base, v)
+	}
+	switch {
+	case isNil(o) && isNil(t):
+		return nil
+	case isNil(o) || isNil(t):
+		kept := o
+		if isNil(o) {
+			kept = t
+		}
+		if !modified(kept) {
+			return nil
+		}
+		m.conflicts = append(m.conflicts, Conflict{Section: section, Index: index,
+			Base: fmt.Sprint(base), Ours: fmt.Sprint(o), Theirs: fmt.Sprint(t)})
+		return copyItem(kept)
+	}
+	merged := copyItem(base)
+	m.fields(merged, base, o, t, section, index)
+	unshare(merged)
+	return merged
+}
+
+// copyItem returns a pointer to a copy of the struct v points to
+func copyItem(v interface{}) interface{} {
+	src := reflect.ValueOf(v).Elem()
+	dst := reflect.New(src.Type())
+	dst.Elem().Set(src)
+	unshare(dst.Interface())
+	return dst.Interface()
+}
+
+// unshare copies the extra data of v if it is an event, or its lines if it
+// is a raw section, so the merged items don't share them with the sides
+func unshare(v interface{}) {
+	switch item := v.(type) {
+	case *Event:
+		item.Extra = copyExtra(item.Extra)
+	case *RawSection:
+		item.Lines = append([]string(nil), item.Lines...)
+	}
+}
+
+// events merges the events in the order of ours, the events added by theirs
+// are inserted after the base event preceding them
+func (m *merger3) events(base, ours, theirs []*Event) []*Event {
+	oursPairs, theirsPairs := alignEvents(base, ours), alignEvents(base, theirs)
+	theirsOf := make([]int, len(base))
+	for i := range theirsOf {
+		theirsOf[i] = -1
+	}
+	// events added by theirs, by the index of the preceding base event + 1
+	added := make(map[int][]*Event)
+	prev := 0
+	for _, p := range theirsPairs {
+		switch {
+		case p.old < 0:
+			added[prev] = append(added[prev], theirs[p.new])
+		default:
+			theirsOf[p.old] = p.new
+			prev = p.old + 1
+		}
+	}
+	// events added by ours, to skip the same ones added by theirs
+	oursAdded := make(map[int][]*Event)
+	prev = 0
+	for _, p := range oursPairs {
+		if p.old < 0 {
+			oursAdded[prev] = append(oursAdded[prev], ours[p.new])
+		} else {
+			prev = p.old + 1
+		}
+	}
+
+	var merged []*Event
+	addTheirs := func(at int) {
+	next:
+		for _, evt := range added[at] {
+			for _, o := range oursAdded[at] {
+				if sameEvent(evt, o) {
+					continue next
+				}
+			}
+			if evt != nil {
+				e := evt.clone()
+				merged = append(merged, &e)
+			}
+		}
+	}
+	addTheirs(0)
+	for _, p := range oursPairs {
+		if p.old < 0 {
+			if ours[p.new] != nil {
+				e := ours[p.new].clone()
+				merged = append(merged, &e)
+			}
+			continue
+		}
+		if base[p.old] == nil {
+			addTheirs(p.old + 1)
+			continue
+		}
+		var o, t *Event
+		if p.new >= 0 {
+			o = ours[p.new]
+		}
+		if j := theirsOf[p.old]; j >= 0 {
+			t = theirs[j]
+		}
+		if evt := m.item("Events", p.old, base[p.old], o, t); evt != nil {
+			merged = append(merged, evt.(*Event))
+		}
+		addTheirs(p.old + 1)
+	}
+	return merged
+}
@@ -0,0 +1,106 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	base := &Subtitle{
+		Title:  "v1",
+		Styles: []*Style{{Name: "Default", FontSize: 20}, {Name: "Sign"}},
+		Events: []*Event{
+			{Start: "0:00:01.00", End: "0:00:02.00", Text: "one"},
+			{Start: "0:00:02.00", End: "0:00:03.00", Text: "two"},
+			{Start: "0:00:03.00", End: "0:00:04.00", Text: "three"},
+			{Start: "0:00:04.00", End: "0:00:05.00", Text: "four"},
+		},
+	}
+	ours := base.Clone()
+	ours.Title = "ours"
+	ours.Styles[0].FontSize = 24
+	ours.Events[0].Text = "ONE"
+	ours.Events[1].Text = "deux"
+	ours.Events = append(ours.Events, &Event{Start: "0:00:05.00", End: "0:00:06.00", Text: "five"})
+
+	theirs := base.Clone()
+	theirs.Styles[0].Bold = -1
+	theirs.Styles = theirs.Styles[:1]
+	theirs.Events[0].End = "0:00:01.50"
+	theirs.Events[1].Text = "zwei"
+	theirs.Events = append(theirs.Events[:2], theirs.Events[3:]...)
+	theirs.Events = append([]*Event{{Start: "0:00:00.00", End: "0:00:01.00", Text: "zero"}}, theirs.Events...)
+
+	merged, conflicts := Merge3(base, ours, theirs)
+	if merged.Title != "ours" {
+		t.Errorf("Expect title ours, got: %s", merged.Title)
+	}
+	expectStyles := []*Style{{Name: "Default", FontSize: 24, Bold: -1}}
+	if !reflect.DeepEqual(merged.Styles, expectStyles) {
+		t.Errorf("Expect styles %+v, got: %+v", expectStyles, merged.Styles)
+	}
+	expectEvents := []*Event{
+		{Start: "0:00:00.00", End: "0:00:01.00", Text: "zero"},
+		{Start: "0:00:01.00", End: "0:00:01.50", Text: "ONE"},
+		{Start: "0:00:02.00", End: "0:00:03.00", Text: "deux"},
+		{Start: "0:00:04.00", End: "0:00:05.00", Text: "four"},
+		{Start: "0:00:05.00", End: "0:00:06.00", Text: "five"},
+	}
+	if !reflect.DeepEqual(merged.Events, expectEvents) {
+		t.Errorf("Expect events %v, got: %v", expectEvents, merged.Events)
+	}
+	expectConflicts := []Conflict{
+		{Section: "Events", Index: 1, Field: "Text", Base: "two", Ours: "deux", Theirs: "zwei"},
+	}
+	if !reflect.DeepEqual(conflicts, expectConflicts) {
+		t.Errorf("Expect conflicts %+v, got: %+v", expectConflicts, conflicts)
+	}
+}
+
+func TestMerge3RemovedModified(t *testing.T) {
+	base := &Subtitle{Events: []*Event{
+		{Start: "0:00:01.00", End: "0:00:02.00", Text: "one"},
+		{Start: "0:00:02.00", End: "0:00:03.00", Text: "two"},
+	}}
+	ours := base.Clone()
+	ours.Events = ours.Events[:1]
+	theirs := base.Clone()
+	theirs.Events[1].Text = "TWO"
+
+	merged, conflicts := Merge3(base, ours, theirs)
+	if len(merged.Events) != 2 || merged.Events[1].Text != "TWO" {
+		t.Errorf("Expect the modified event kept, got: %v", merged.Events)
+	}
+	if len(conflicts) != 1 || conflicts[0].Index != 1 || conflicts[0].Field != "" {
+		t.Errorf("Expect a removed/modified conflict, got: %+v", conflicts)
+	}
+}
+
+func TestMerge3RawSections(t *testing.T) {
+	base := &Subtitle{RawSections: []*RawSection{
+		{Name: "Fonts", Lines: []string{"font.ttf"}},
+		{Name: "Aegisub Project Garbage", Lines: []string{"Video File: a.mkv"}},
+		{Name: "Graphics", Lines: []string{"logo.png"}},
+	}}
+	ours := base.Clone()
+	ours.RawSections[1].Lines = []string{"Video File: b.mkv"}
+	ours.RawSections = append(ours.RawSections, &RawSection{Name: "Ours", Lines: []string{"x"}})
+	theirs := base.Clone()
+	theirs.RawSections = theirs.RawSections[:2]
+	theirs.RawSections = append(theirs.RawSections, &RawSection{Name: "Theirs", Lines: []string{"y"}})
+
+	merged, conflicts := Merge3(base, ours, theirs)
+	expect := []*RawSection{
+		{Name: "Fonts", Lines: []string{"font.ttf"}},
+		{Name: "Aegisub Project Garbage", Lines: []string{"Video File: b.mkv"}},
+		{Name: "Ours", Lines: []string{"x"}},
+		{Name: "Theirs", Lines: []string{"y"}},
+	}
+	if !reflect.DeepEqual(merged.RawSections, expect) || len(conflicts) != 0 {
+		t.Errorf("Expect raw sections %v, got: %v, %v", expect, merged.RawSections, conflicts)
+	}
+	merged.RawSections[0].Lines[0] = "changed"
+	if base.RawSections[0].Lines[0] != "font.ttf" {
+		t.Errorf("Expect the merged sections not to share their lines")
+	}
+}
@@ -0,0 +1,71 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	a := &Subtitle{
+		Title:  "Dialogue",
+		Styles: []*Style{{Name: "Default", FontName: "Arial"}, {Name: "Signs", FontName: "Arial"}},
+		Events: []*Event{{Start: "0:00:02.00", End: "0:00:03.00", Style: "Default", Text: "Hi"}},
+		RawSections: []*RawSection{
+			{Name: "Fonts", Lines: []string{"fontname: a.ttf", "AAAA"}},
+			{Name: "Aegisub Project Garbage", Lines: []string{"Video File: a.mkv"}},
+		},
+	}
+	b := &Subtitle{
+		Title:  "Signs",
+		Styles: []*Style{{Name: "Default", FontName: "Arial"}, {Name: "Signs", FontName: "Verdana"}},
+		Events: []*Event{
+			{Start: "0:00:01.00", End: "0:00:02.00", Style: "Signs", Text: `EXIT{\rDefault}now{\rSigns}!`},
+			{Start: "0:00:03.00", End: "0:00:04.00", Style: "Default", Text: "Bye"},
+		},
+		RawSections: []*RawSection{
+			{Name: "Fonts", Lines: []string{"fontname: b.ttf", "BBBB"}},
+			{Name: "Aegisub Project Garbage", Lines: []string{"Video File: a.mkv"}},
+			{Name: "Graphics", Lines: []string{"filename: logo.png", "CCCC"}},
+		},
+	}
+
+	merged := Merge(a, b, MergeOptions{SortEvents: true})
+	if merged.Title != "Dialogue" {
+		t.Errorf("Expect script info of a, got: %s", merged.Title)
+	}
+
+	names := []string{"Default", "Signs", "Signs_b"}
+	if len(merged.Styles) != len(names) {
+		t.Fatalf("Expect %d styles, got: %d", len(names), len(merged.Styles))
+	}
+	for i, name := range names {
+		if merged.Styles[i].Name != name {
+			t.Errorf("Expect style %s, got: %s", name, merged.Styles[i].Name)
+		}
+	}
+
+	events := []Event{
+		{Start: "0:00:01.00", End: "0:00:02.00", Style: "Signs_b", Text: `EXIT{\rDefault}now{\rSigns_b}!`},
+		{Start: "0:00:02.00", End: "0:00:03.00", Style: "Default", Text: "Hi"},
+		{Start: "0:00:03.00", End: "0:00:04.00", Style: "Default", Text: "Bye"},
+	}
+	for i, evt := range events {
+		if !reflect.DeepEqual(*merged.Events[i], evt) {
+			t.Errorf("Expect %+v, got: %+v", evt, *merged.Events[i])
+		}
+	}
+
+	raws := []*RawSection{
+		{Name: "Fonts", Lines: []string{"fontname: a.ttf", "AAAA", "fontname: b.ttf", "BBBB"}},
+		{Name: "Aegisub Project Garbage", Lines: []string{"Video File: a.mkv"}},
+		{Name: "Graphics", Lines: []string{"filename: logo.png", "CCCC"}},
+	}
+	if !reflect.DeepEqual(merged.RawSections, raws) {
+		t.Errorf("Expect raw sections merged by name, got: %v", merged.RawSections)
+	}
+
+	if b.Styles[1].Name != "Signs" || b.Events[0].Style != "Signs" || a.Events[0] == merged.Events[1] ||
+		len(a.RawSections[0].Lines) != 2 || a.RawSections[0] == merged.RawSections[0] {
+		t.Errorf("Expect the inputs left untouched")
+	}
+}
@@ -0,0 +1,474 @@
+package ass
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// Matroska element IDs
+const (
+	mkvSegment              = 0x18538067
+	mkvInfo                 = 0x1549A966
+	mkvTimecodeScale        = 0x2AD7B1
+	mkvTracks               = 0x1654AE6B
+	mkvTrackEntry           = 0xAE
+	mkvTrackNumber          = 0xD7
+	mkvCodecID              = 0x86
+	mkvCodecPrivate         = 0x63A2
+	mkvLanguage             = 0x22B59C
+	mkvLanguageIETF         = 0x22B59D
+	mkvContentEncodings     = 0x6D80
+	mkvContentEncoding      = 0x6240
+	mkvContentEncodingScope = 0x5032
+	mkvContentCompression   = 0x5034
+	mkvContentCompAlgo      = 0x4254
+	mkvContentCompSettings  = 0x4255
+	mkvCluster              = 0x1F43B675
+	mkvTimecode             = 0xE7
+	mkvBlockGroup           = 0xA0
+	mkvBlock                = 0xA1
+	mkvBlockDuration        = 0x9B
+	mkvSimpleBlock          = 0xA3
+)
+
+// mkvMasters are the master elements read through, the others are skipped
+var mkvMasters = map[uint32]bool{
+	mkvSegment: true, mkvInfo: true, mkvTracks: true, mkvTrackEntry: true,
+	mkvContentEncodings: true, mkvContentEncoding: true, mkvContentCompression: true,
+	mkvCluster: true, mkvBlockGroup: true,
+}
+
+// mkvMaxElement limits the size of the elements read in memory
+const mkvMaxElement = 16 << 20
+
+// mkvEventFormat is the field order of the events stored in Matroska blocks
+var mkvEventFormat = []string{"readorder", "layer", "style", "name", "marginl", "marginr", "marginv", "effect", "text"}
+
+// ReadMatroska extracts an ASS or SSA subtitle track from a Matroska (MKV)
+// file. track is the track number, 0 picks the first ASS track. The header
+// of the script is read from the CodecPrivate of the track and the events
+// from its blocks, in their ReadOrder. The language of the track is used
+// when the script info has none.
+func ReadMatroska(r io.Reader, track int) (*Subtitle, error) {
+	m := &mkvReader{r: bufio.NewReader(r), track: track, timecodeScale: uint64(time.Millisecond)}
+	if err := m.read(); err != nil {
+		return nil, err
+	}
+	if m.selected == nil {
+		if err := m.selectTrack(); err != nil {
+			return nil, err
+		}
+	}
+
+	header, err := m.selected.decode(m.selected.private, 2)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := Parse(bytes.NewReader(header))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid track header: %v", err)
+	}
+	if sub.Language == "" {
+		sub.Language = m.selected.language
+	}
+	sort.SliceStable(m.events, func(i, j int) bool { return m.events[i].order < m.events[j].order })
+	sub.Events = sub.Events[:0]
+	for _, e := range m.events {
+		sub.Events = append(sub.Events, e.evt)
+	}
+	return sub, nil
+}
+
+type mkvTrack struct {
+	number   uint64
+	codec    string
+	private  []byte
+	language string
+	compAlgo int64 // -1 when not compressed
+	settings []byte
+	scope    uint64
+}
+
+// decode undoes the compression of the track if it applies to scope,
+// 1 for the blocks and 2 for the codec private
+func (t *mkvTrack) decode(data []byte, scope uint64) ([]byte, error) {
+	if t.compAlgo < 0 || t.scope&scope == 0 {
+		return data, nil
+	}
+	switch t.compAlgo {
+	case 0:
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return ioutil.ReadAll(io.LimitReader(zr, mkvMaxElement))
+	case 3:
+		return append(append([]byte{}, t.settings...), data...), nil
+	}
+	return nil, fmt.Errorf("Unsupported compression: %d", t.compAlgo)
+}
+
+type mkvEvent struct {
+	order int
+	evt   *Event
+}
+
+type mkvReader struct {
+	r             *bufio.Reader
+	track         int
+	timecodeScale uint64
+	tracks        []*mkvTrack
+	selected      *mkvTrack
+	cluster       uint64
+	// the block of the current block group
+	block    []byte
+	duration uint64
+	events   []mkvEvent
+}
+
+func (m *mkvReader) read() error {
+	for {
+		id, err := m.readID()
+		if err == io.EOF {
+			return m.flush()
+		}
+		if err != nil {
+			return err
+		}
+		size, err := m.readSize()
+		if err != nil {
+			return err
+		}
+		if mkvMasters[id] {
+			if err := m.enter(id); err != nil {
+				return err
+			}
+			continue
+		}
+		if size < 0 {
+			return fmt.Errorf("Invalid element %X: unknown size", id)
+		}
+
+		switch id {
+		case mkvTimecodeScale, mkvTrackNumber, mkvContentEncodingScope, mkvContentCompAlgo,
+			mkvTimecode, mkvBlockDuration, mkvCodecID, mkvCodecPrivate, mkvContentCompSettings,
+			mkvLanguage, mkvLanguageIETF, mkvBlock, mkvSimpleBlock:
+		default:
+			if _, err := io.CopyN(ioutil.Discard, m.r, size); err != nil {
+				return unexpectedEOF(err)
+			}
+			continue
+		}
+		if size > mkvMaxElement {
+			return fmt.Errorf("Invalid element %X: too large", id)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(m.r, data); err != nil {
+			return unexpectedEOF(err)
+		}
+		if err := m.element(id, data); err != nil {
+			return err
+		}
+	}
+}
+
+// enter starts a master element
+func (m *mkvReader) enter(id uint32) error {
+	switch id {
+	case mkvTrackEntry:
+		m.tracks = append(m.tracks, &mkvTrack{compAlgo: -1, scope: 1})
+	case mkvContentCompression:
+		if t := m.lastTrack(); t != nil {
+			t.compAlgo = 0
+		}
+	case mkvCluster, mkvBlockGroup:
+		return m.flush()
+	}
+	return nil
+}
+
+func (m *mkvReader) lastTrack() *mkvTrack {
+	if len(m.tracks) == 0 {
+		return nil
+	}
+	return m.tracks[len(m.tracks)-1]
+}
+
+func (m *mkvReader) element(id uint32, data []byte) error {
+	t := m.lastTrack()
+	switch id {
+	case mkvTimecodeScale:
+		m.timecodeScale = readUint(data)
+	case mkvTimecode:
+		if err := m.flush(); err != nil {
+			return err
+		}
+		m.cluster = readUint(data)
+	case mkvBlockDuration:
+		m.duration = readUint(data)
+	case mkvBlock:
+		m.block = data
+	case mkvSimpleBlock:
+		if err := m.flush(); err != nil {
+			return err
+		}
+		return m.addBlock(data, 0)
+	}
+	if t == nil {
+		return nil
+	}
+	switch id {
+	case mkvTrackNumber:
+		t.number = readUint(data)
+	case mkvCodecID:
+		t.codec = string(bytes.TrimRight(data, "\x00"))
+	case mkvCodecPrivate:
+		t.private = data
+	case mkvLanguage:
+		// ISO 639-2, the BCP 47 tag is preferred
+		if t.language == "" {
+			if tag, err := language.Parse(string(bytes.TrimRight(data, "\x00"))); err == nil && tag != language.Und {
+				t.language = tag.String()
+			}
+		}
+	case mkvLanguageIETF:
+		t.language = string(bytes.TrimRight(data, "\x00"))
+	case mkvContentEncodingScope:
+		t.scope = readUint(data)
+	case mkvContentCompAlgo:
+		t.compAlgo = int64(readUint(data))
+	case mkvContentCompSettings:
+		t.settings = data
+	}
+	return nil
+}
+
+// flush adds the block of the current block group
+func (m *mkvReader) flush() error {
+	if m.block == nil {
+		return nil
+	}
+	block, duration := m.block, m.duration
+	m.block, m.duration = nil, 0
+	return m.addBlock(block, duration)
+}
+
+func (m *mkvReader) selectTrack() error {
+	for _, t := range m.tracks {
+		isASS := t.codec == "S_TEXT/ASS" || t.codec == "S_TEXT/SSA" || t.codec == "S_ASS" || t.codec == "S_SSA"
+		if m.track == 0 && isASS || m.track > 0 && t.number == uint64(m.track) {
+			if !isASS {
+				return fmt.Errorf("Track %d is not an ASS track: %s", m.track, t.codec)
+			}
+			m.selected = t
+			return nil
+		}
+	}
+	if m.track > 0 {
+		return fmt.Errorf("Track %d not found", m.track)
+	}
+	return fmt.Errorf("No ASS track found")
+}
+
+func (m *mkvReader) addBlock(block []byte, duration uint64) error {
+	if m.selected == nil {
+		if err := m.selectTrack(); err != nil {
+			return err
+		}
+	}
+	track, n := readVint(block)
+	if n == 0 || len(block) < n+3 {
+		return fmt.Errorf("Invalid block")
+	}
+	if track != m.selected.number {
+		return nil
+	}
+	rel := int16(binary.BigEndian.Uint16(block[n:]))
+	if block[n+2]&0x06 != 0 {
+		return fmt.Errorf("Invalid block: laced subtitle blocks are not supported")
+	}
+	data, err := m.selected.decode(block[n+3:], 1)
+	if err != nil {
+		return err
+	}
+
+	format := mkvEventFormat
+	if strings.HasSuffix(m.selected.codec, "SSA") {
+		format = append([]string{"readorder", "marked"}, mkvEventFormat[2:]...)
+	}
+	evt, err := parseEvent(format, strings.TrimRight(string(data), "\r\n\x00"))
+	if err != nil {
+		return err
+	}
+	start := int64(m.cluster) + int64(rel)
+	if start < 0 {
+		start = 0
+	}
+	scale := time.Duration(m.timecodeScale)
+	evt.Start = Timestamp(time.Duration(start) * scale).String()
+	evt.End = Timestamp(time.Duration(uint64(start)+duration) * scale).String()
+	order, _ := strconv.Atoi(strings.SplitN(string(data), ",", 2)[0])
+	m.events = append(m.events, mkvEvent{order, evt})
+	return nil
+}
+
+// readID reads an element ID, keeping its length marker
+func (m *mkvReader) readID() (uint32, error) {
+	first, err := m.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	n := 1
+	for mask := byte(0x80); n <= 4 && first&mask == 0; mask >>= 1 {
+		n++
+	}
+	if n > 4 {
+		return 0, fmt.Errorf("Invalid element ID")
+	}
+	id := uint32(first)
+	for i := 1; i < n; i++ {
+		b, err := m.r.ReadByte()
+		if err != nil {
+			return 0, unexpectedEOF(err)
+		}
+		id = id<<8 | uint32(b)
+	}
+	return id, nil
+}
+
+// readSize reads an element size, -1 when unknown
+func (m *mkvReader) readSize() (int64, error) {
+	first, err := m.r.ReadByte()
+	if err != nil {
+		return 0, unexpectedEOF(err)
+	}
+	buf := []byte{first}
+	for mask := byte(0x80); mask != 0 && first&mask == 0; mask >>= 1 {
+		b, err := m.r.ReadByte()
+		if err != nil {
+			return 0, unexpectedEOF(err)
+		}
+		buf = append(buf, b)
+	}
+	size, n := readVint(buf)
+	if n == 0 {
+		return 0, fmt.Errorf("Invalid element size")
+	}
+	if size == 1<<(7*uint(n))-1 {
+		return -1, nil
+	}
+	return int64(size), nil
+}
+
+// readVint decodes a variable size integer, returning its length, 0 if invalid
+func readVint(b []byte) (uint64, int) {
+	if len(b) == 0 || b[0] == 0 {
+		return 0, 0
+	}
+	n := 1
+	for mask := byte(0x80); b[0]&mask == 0; mask >>= 1 {
+		n++
+	}
+	if len(b) < n {
+		return 0, 0
+	}
+	v := uint64(b[0] & (0xFF >> uint(n)))
+	for _, c := range b[1:n] {
+		v = v<<8 | uint64(c)
+	}
+	return v, n
+}
+
+func readUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// MatroskaTrack is a subtitle prepared for a Matroska muxer
+type MatroskaTrack struct {
+	CodecID string
+	// CodecPrivate is the header of the script: script info, styles and
+	// the format of the events
+	CodecPrivate []byte
+	// Language is the BCP 47 tag of the LanguageIETF element, if set
+	Language string
+	// Packets are the events in the order of their start time
+	Packets []MatroskaPacket
+}
+
+// MatroskaPacket is the payload of a block, with its timing
+type MatroskaPacket struct {
+	Start    time.Duration
+	Duration time.Duration
+	// Data is ReadOrder,Layer,Style,Name,MarginL,MarginR,MarginV,Effect,Text
+	// without line ending, ReadOrder being the index of the event
+	Data []byte
+}
+
+// Matroska converts the subtitle to the codec private data and block
+// payloads of an S_TEXT/ASS track, see ReadMatroska for the reverse
+func (as Subtitle) Matroska() (*MatroskaTrack, error) {
+	if err := as.validate(); err != nil {
+		return nil, err
+	}
+	as.fulfill()
+
+	var header bytes.Buffer
+	if err := writeSection(&header, &as, "header", true); err != nil {
+		return nil, err
+	}
+	track := &MatroskaTrack{
+		CodecID:      "S_TEXT/ASS",
+		CodecPrivate: bytes.TrimLeft(header.Bytes(), "\n"),
+		Language:     as.Language,
+	}
+	for i, evt := range as.Events {
+		if evt.Comment {
+			continue
+		}
+		start, end, err := evt.times()
+		if err != nil {
+			return nil, err
+		}
+		b := strconv.AppendInt(nil, int64(i), 10)
+		b = append(b, ',')
+		b = strconv.AppendInt(b, int64(evt.Layer), 10)
+		for _, f := range []string{evt.Style, evt.Name} {
+			b = append(append(b, ','), f...)
+		}
+		for _, m := range []uint{evt.MarginL, evt.MarginR, evt.MarginV} {
+			b = appendMargin(append(b, ','), m, true)
+		}
+		b = append(append(b, ','), evt.Effect...)
+		b = appendEventText(append(b, ','), evt)
+		track.Packets = append(track.Packets, MatroskaPacket{
+			Start:    start.Duration(),
+			Duration: time.Duration(end - start),
+			Data:     b,
+		})
+	}
+	sort.SliceStable(track.Packets, func(i, j int) bool { return track.Packets[i].Start < track.Packets[j].Start })
+	return track, nil
+}
@@ -0,0 +1,156 @@
+package ass
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ebml encodes an element, size -1 writes an unknown size
+func ebml(id uint32, size int, children ...[]byte) []byte {
+	var payload []byte
+	for _, c := range children {
+		payload = append(payload, c...)
+	}
+	var b []byte
+	for shift := 24; shift >= 0; shift -= 8 {
+		if c := byte(id >> uint(shift)); c != 0 || len(b) > 0 {
+			b = append(b, c)
+		}
+	}
+	if size < 0 {
+		b = append(b, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF)
+	} else {
+		sz := make([]byte, 8)
+		binary.BigEndian.PutUint64(sz, uint64(len(payload)))
+		sz[0] = 0x01
+		b = append(b, sz...)
+	}
+	return append(b, payload...)
+}
+
+func ebmlElem(id uint32, children ...[]byte) []byte {
+	return ebml(id, 0, children...)
+}
+
+func ebmlUint(id uint32, v uint64) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(v))
+	return ebmlElem(id, b)
+}
+
+func mkvBlockData(track byte, rel int16, data string) []byte {
+	return append([]byte{0x80 | track, byte(uint16(rel) >> 8), byte(rel), 0}, data...)
+}
+
+func TestReadMatroska(t *testing.T) {
+	header := "[Script Info]\nTitle: mkv\n\n[V4+ Styles]\n" +
+		"Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\n" +
+		"Style: Default,Arial,20,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,0,2,20,20,2,0\n\n" +
+		"[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n"
+	file := append(ebmlElem(0x1A45DFA3), ebml(mkvSegment, -1,
+		ebmlElem(mkvInfo, ebmlUint(mkvTimecodeScale, 1000000)),
+		ebmlElem(mkvTracks,
+			ebmlElem(mkvTrackEntry, ebmlUint(mkvTrackNumber, 1), ebmlElem(mkvCodecID, []byte("V_MPEG4/ISO/AVC"))),
+			ebmlElem(mkvTrackEntry, ebmlUint(mkvTrackNumber, 2), ebmlElem(mkvCodecID, []byte("S_TEXT/ASS")),
+				ebmlElem(mkvLanguage, []byte("fre")),
+				ebmlElem(mkvCodecPrivate, []byte(header)),
+				ebmlElem(mkvContentEncodings, ebmlElem(mkvContentEncoding, ebmlElem(mkvContentCompression, ebmlUint(mkvContentCompAlgo, 0))))),
+		),
+		ebml(mkvCluster, -1,
+			ebmlUint(mkvTimecode, 1000),
+			ebmlElem(mkvSimpleBlock, mkvBlockData(1, 0, "video")),
+			ebmlElem(mkvBlockGroup, ebmlElem(mkvBlock, mkvBlockData(2, 500, string(zlibBytes(t, "1,0,Default,,0,0,0,,second")))), ebmlUint(mkvBlockDuration, 1000)),
+		),
+		ebmlElem(mkvCluster,
+			ebmlUint(mkvTimecode, 0),
+			ebmlElem(mkvBlockGroup, ebmlUint(mkvBlockDuration, 500), ebmlElem(mkvBlock, mkvBlockData(2, 100, string(zlibBytes(t, "0,0,Default,Alice,0,0,0,,first, with comma"))))),
+		),
+	)...)
+
+	sub, err := ReadMatroska(bytes.NewReader(file), 0)
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	if sub.Title != "mkv" || len(sub.Styles) != 1 || sub.Language != "fr" {
+		t.Errorf("Expect the header parsed, got: %+v", sub)
+	}
+	expect := []*Event{
+		{Start: "0:00:00.10", End: "0:00:00.60", Style: "Default", Name: "Alice", Text: "first, with comma"},
+		{Start: "0:00:01.50", End: "0:00:02.50", Style: "Default", Text: "second"},
+	}
+	if !reflect.DeepEqual(sub.Events, expect) {
+		t.Errorf("Expect %v, got: %v", expect, sub.Events)
+	}
+
+	if _, err := ReadMatroska(bytes.NewReader(file), 1); err == nil {
+		t.Errorf("Expect error for a video track")
+	}
+	if _, err := ReadMatroska(bytes.NewReader(file[:len(file)-3]), 0); err == nil {
+		t.Errorf("Expect error for a truncated file")
+	}
+}
+
+func zlibBytes(t *testing.T, s string) []byte {
+	var b bytes.Buffer
+	zw := zlib.NewWriter(&b)
+	zw.Write([]byte(s))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestMatroska(t *testing.T) {
+	sub := &Subtitle{
+		Title:    "mux",
+		Language: "de",
+		Styles:   []*Style{{Name: "Default", FontName: "Arial", FontSize: 20, PrimaryColor: "00FFFFFF", SecondColor: "000000FF", OutlineColor: "00000000", BackColor: "00000000"}},
+		Events: []*Event{
+			{Layer: 1, Start: "0:00:02.00", End: "0:00:03.50", Style: "Default", Forced: true, Extra: map[string]string{"k": "v"}, Text: "later, but first"},
+			{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Name: "Bob", MarginL: 10, Text: "earlier\nline"},
+		},
+	}
+	track, err := sub.Matroska()
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	if track.CodecID != "S_TEXT/ASS" || track.Language != "de" || !bytes.HasPrefix(track.CodecPrivate, []byte("[Script Info]\n")) ||
+		!bytes.HasSuffix(track.CodecPrivate, []byte("Effect, Text\n")) {
+		t.Errorf("Expect an ASS header, got: %s", track.CodecPrivate)
+	}
+	if len(track.Packets) != 2 || string(track.Packets[0].Data) != `1,0,Default,Bob,0010,0000,0000,,earlier\Nline` ||
+		track.Packets[0].Start != time.Second || track.Packets[0].Duration != time.Second {
+		t.Errorf("Expect packets in start order, got: %+v", track.Packets)
+	}
+	if len(track.Packets) == 2 && !bytes.HasSuffix(track.Packets[1].Data, []byte(",,"+forcedMarker+extraMarker+"k=v}later, but first")) {
+		t.Errorf("Expect the forced marker and extra data, got: %s", track.Packets[1].Data)
+	}
+
+	// read back
+	var blocks [][]byte
+	for _, p := range track.Packets {
+		blocks = append(blocks, ebmlElem(mkvBlockGroup,
+			ebmlElem(mkvBlock, mkvBlockData(1, int16(p.Start/time.Millisecond), string(p.Data))),
+			ebmlUint(mkvBlockDuration, uint64(p.Duration/time.Millisecond))))
+	}
+	file := ebmlElem(mkvSegment,
+		ebmlElem(mkvTracks, ebmlElem(mkvTrackEntry, ebmlUint(mkvTrackNumber, 1),
+			ebmlElem(mkvCodecID, []byte(track.CodecID)), ebmlElem(mkvCodecPrivate, track.CodecPrivate))),
+		ebmlElem(mkvCluster, append([][]byte{ebmlUint(mkvTimecode, 0)}, blocks...)...),
+	)
+	got, err := ReadMatroska(bytes.NewReader(file), 0)
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	for _, evt := range sub.Events {
+		evt.Text = strings.Replace(evt.Text, "\n", `\N`, -1)
+	}
+	if !reflect.DeepEqual(got.Events, sub.Events) || got.Title != "mux" {
+		t.Errorf("Expect %v, got: %v", sub.Events, got.Events)
+	}
+}
@@ -0,0 +1,276 @@
+package ass
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrackFrame is the state of a tracked sign on one frame of the video
+type TrackFrame struct {
+	Frame int
+	X, Y  float64
+	// ScaleX and ScaleY are in percent, 100 when the data has no scale
+	ScaleX, ScaleY float64
+	// Rotation is clockwise, in degrees
+	Rotation float64
+}
+
+// Track is motion tracking data
+type Track struct {
+	FrameRate     float64
+	Width, Height int
+	Frames        []TrackFrame
+}
+
+// ParseTrack reads motion tracking data in the After Effects keyframe
+// format, as exported by mocha and by the Blender exporters for Aegisub.
+// The frames are those of the position data, the scale and rotation data
+// are optional.
+func ParseTrack(r io.Reader) (*Track, error) {
+	track := &Track{}
+	positions := make(map[int][2]float64)
+	scales := make(map[int][2]float64)
+	rotations := make(map[int]float64)
+
+	scanner := bufio.NewScanner(r)
+	var (
+		section string
+		lineNo  int
+		started bool
+	)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), utf8BOM))
+		if line == "" {
+			continue
+		}
+		if !started {
+			if !strings.HasPrefix(line, "Adobe After Effects") || !strings.HasSuffix(line, "Keyframe Data") {
+				return nil, fmt.Errorf("Line %d: Invalid keyframe data header: %s", lineNo, line)
+			}
+			started = true
+			continue
+		}
+		if line == "End of Keyframe Data" {
+			break
+		}
+		fields := strings.Fields(line)
+		frame, err := strconv.Atoi(fields[0])
+		if err != nil {
+			switch {
+			case fields[0] == "Frame":
+				// the column names
+			case len(fields) > 1 && section == "":
+				if err := track.setHeader(strings.Join(fields[:len(fields)-1], " "), fields[len(fields)-1]); err != nil {
+					return nil, fmt.Errorf("Line %d: %v", lineNo, err)
+				}
+			default:
+				section = line
+			}
+			continue
+		}
+
+		var values []float64
+		for _, f := range fields[1:] {
+			v, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Line %d: Invalid keyframe: %s", lineNo, line)
+			}
+			values = append(values, v)
+		}
+		want := 2
+		if section == "Rotation" {
+			want = 1
+		}
+		if len(values) < want {
+			return nil, fmt.Errorf("Line %d: Invalid keyframe: %s", lineNo, line)
+		}
+		switch section {
+		case "Position":
+			positions[frame] = [2]float64{values[0], values[1]}
+		case "Scale":
+			scales[frame] = [2]float64{values[0], values[1]}
+		case "Rotation":
+			rotations[frame] = values[0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !started {
+		return nil, fmt.Errorf("Missing keyframe data")
+	}
+	if track.FrameRate <= 0 {
+		return nil, fmt.Errorf("Missing frame rate")
+	}
+	if len(positions) == 0 {
+		return nil, fmt.Errorf("Missing position data")
+	}
+
+	for frame, pos := range positions {
+		f := TrackFrame{Frame: frame, X: pos[0], Y: pos[1], ScaleX: 100, ScaleY: 100, Rotation: rotations[frame]}
+		if scale, ok := scales[frame]; ok {
+			f.ScaleX, f.ScaleY = scale[0], scale[1]
+		}
+		track.Frames = append(track.Frames, f)
+	}
+	sort.Slice(track.Frames, func(i, j int) bool { return track.Frames[i].Frame < track.Frames[j].Frame })
+	return track, nil
+}
+
+// setHeader sets a field of the header of the keyframe data
+func (t *Track) setHeader(key, value string) error {
+	var err error
+	switch key {
+	case "Units Per Second":
+		t.FrameRate, err = strconv.ParseFloat(value, 64)
+	case "Source Width":
+		t.Width, err = strconv.Atoi(value)
+	case "Source Height":
+		t.Height, err = strconv.Atoi(value)
+	}
+	if err != nil {
+		return fmt.Errorf("Invalid %s: %s", strings.ToLower(key), value)
+	}
+	return nil
+}
+
+// Keyframes decimates the frames: it keeps the first and the last ones and
+// as few others as possible so that interpolating linearly between them is
+// off by at most tolerance, in pixels for the position, percent for the
+// scale and degrees for the rotation. Tolerance 0 keeps the frames needed
+// to reproduce the track exactly.
+func (t *Track) Keyframes(tolerance float64) []TrackFrame {
+	if len(t.Frames) < 3 {
+		return append([]TrackFrame(nil), t.Frames...)
+	}
+	keep := make([]bool, len(t.Frames))
+	keep[0], keep[len(t.Frames)-1] = true, true
+	var decimate func(i, j int)
+	decimate = func(i, j int) {
+		worst, worstErr := -1, tolerance
+		a, b := t.Frames[i], t.Frames[j]
+		for k := i + 1; k < j; k++ {
+			f := t.Frames[k]
+			at := float64(f.Frame-a.Frame) / float64(b.Frame-a.Frame)
+			lerp := func(x, y float64) float64 { return x + (y-x)*at }
+			e := math.Max(math.Hypot(f.X-lerp(a.X, b.X), f.Y-lerp(a.Y, b.Y)),
+				math.Max(math.Max(math.Abs(f.ScaleX-lerp(a.ScaleX, b.ScaleX)), math.Abs(f.ScaleY-lerp(a.ScaleY, b.ScaleY))),
+					math.Abs(f.Rotation-lerp(a.Rotation, b.Rotation))))
+			if e > worstErr+1e-9 {
+				worst, worstErr = k, e
+			}
+		}
+		if worst >= 0 {
+			keep[worst] = true
+			decimate(i, worst)
+			decimate(worst, j)
+		}
+	}
+	decimate(0, len(t.Frames)-1)
+
+	var frames []TrackFrame
+	for i, f := range t.Frames {
+		if keep[i] {
+			frames = append(frames, f)
+		}
+	}
+	return frames
+}
+
+// TrackOptions configures Track.Apply
+type TrackOptions struct {
+	// Tolerance decimates the frames, see Track.Keyframes
+	Tolerance float64
+	// Move interpolates between the keyframes with \move, and \t for the
+	// scale and rotation, instead of holding each keyframe with \pos
+	Move bool
+}
+
+// Apply makes a sign follow the track, the first frame of the track being
+// shown at the start of evt. It returns copies of evt, one per keyframe
+// until the end of evt, with the position, and the scale and rotation if
+// tracked, replacing their own.
+func (t *Track) Apply(evt *Event, opts TrackOptions) ([]*Event, error) {
+	start, end, err := evt.times()
+	if err != nil {
+		return nil, err
+	}
+	if t.FrameRate <= 0 {
+		return nil, fmt.Errorf("Invalid frame rate: %v", t.FrameRate)
+	}
+	scaled, rotated := false, false
+	for _, f := range t.Frames {
+		scaled = scaled || f.ScaleX != 100 || f.ScaleY != 100
+		rotated = rotated || f.Rotation != 0
+	}
+	text := removeTags(evt.Text, func(tag string) bool {
+		switch {
+		case strings.HasPrefix(tag, `\pos(`), strings.HasPrefix(tag, `\move(`):
+			return true
+		case strings.HasPrefix(tag, `\fscx`), strings.HasPrefix(tag, `\fscy`):
+			return scaled
+		case strings.HasPrefix(tag, `\frz`), strings.HasPrefix(tag, `\fr`) && !strings.HasPrefix(tag, `\frx`) && !strings.HasPrefix(tag, `\fry`):
+			return rotated
+		}
+		return false
+	})
+
+	keys := t.Keyframes(opts.Tolerance)
+	at := func(f TrackFrame) Timestamp {
+		return start + Timestamp(float64(f.Frame-keys[0].Frame)/t.FrameRate*float64(time.Second))
+	}
+	var events []*Event
+	for i, key := range keys {
+		from, to := at(key), end
+		if i+1 < len(keys) && at(keys[i+1]) < end {
+			to = at(keys[i+1])
+		}
+		if from >= end {
+			break
+		}
+		if from.String() == to.String() {
+			continue
+		}
+		next := key
+		if opts.Move && i+1 < len(keys) {
+			next = keys[i+1]
+		}
+
+		var b strings.Builder
+		if next == key {
+			fmt.Fprintf(&b, `\pos(%s,%s)`, formatNumber(key.X), formatNumber(key.Y))
+		} else {
+			fmt.Fprintf(&b, `\move(%s,%s,%s,%s)`, formatNumber(key.X), formatNumber(key.Y), formatNumber(next.X), formatNumber(next.Y))
+		}
+		var animated strings.Builder
+		if scaled {
+			fmt.Fprintf(&b, `\fscx%s\fscy%s`, formatNumber(key.ScaleX), formatNumber(key.ScaleY))
+			if next.ScaleX != key.ScaleX || next.ScaleY != key.ScaleY {
+				fmt.Fprintf(&animated, `\fscx%s\fscy%s`, formatNumber(next.ScaleX), formatNumber(next.ScaleY))
+			}
+		}
+		if rotated {
+			// \frz turns counterclockwise
+			fmt.Fprintf(&b, `\frz%s`, formatNumber(-key.Rotation))
+			if next.Rotation != key.Rotation {
+				fmt.Fprintf(&animated, `\frz%s`, formatNumber(-next.Rotation))
+			}
+		}
+		if animated.Len() > 0 {
+			fmt.Fprintf(&b, `\t(%s)`, animated.String())
+		}
+
+		e := evt.clone()
+		e.Start, e.End = from.String(), to.String()
+		e.Text = "{" + b.String() + "}" + text
+		events = append(events, &e)
+	}
+	return events, nil
+}
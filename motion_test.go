@@ -0,0 +1,99 @@
+package ass
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const mochaData = "Adobe After Effects 6.0 Keyframe Data\n\n" +
+	"\tUnits Per Second\t25\n\tSource Width\t1920\n\tSource Height\t1080\n" +
+	"\tSource Pixel Aspect Ratio\t1\n\tComp Pixel Aspect Ratio\t1\n\n" +
+	"Anchor Point\n\tFrame\tX pixels\tY pixels\tZ pixels\n\t0\t960\t540\t0\n\n" +
+	"Position\n\tFrame\tX pixels\tY pixels\tZ pixels\n" +
+	"\t0\t100\t200\t0\n\t1\t110\t200\t0\n\t2\t120\t200\t0\n\t3\t120\t210\t0\n\n" +
+	"Scale\n\tFrame\tX percent\tY percent\tZ percent\n" +
+	"\t0\t100\t100\t100\n\t1\t100\t100\t100\n\t2\t100\t100\t100\n\t3\t100\t100\t100\n\n" +
+	"Rotation\n\tFrame\tDegrees\n\t0\t0\n\t1\t0\n\t2\t0\n\t3\t10\n\n" +
+	"End of Keyframe Data\n"
+
+func TestParseTrack(t *testing.T) {
+	track, err := ParseTrack(strings.NewReader(mochaData))
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	if track.FrameRate != 25 || track.Width != 1920 || track.Height != 1080 || len(track.Frames) != 4 {
+		t.Fatalf("Expect the header and 4 frames, got: %+v", track)
+	}
+	expect := TrackFrame{Frame: 3, X: 120, Y: 210, ScaleX: 100, ScaleY: 100, Rotation: 10}
+	if track.Frames[3] != expect {
+		t.Errorf("Expect %+v, got: %+v", expect, track.Frames[3])
+	}
+
+	invalids := []string{
+		"",
+		"Position\n\t0\t1\t2\n",
+		"Adobe After Effects 6.0 Keyframe Data\nPosition\n\t0\t1\t2\n",
+		"Adobe After Effects 6.0 Keyframe Data\n\tUnits Per Second\t25\n",
+		"Adobe After Effects 6.0 Keyframe Data\n\tUnits Per Second\tx\n",
+		"Adobe After Effects 6.0 Keyframe Data\n\tUnits Per Second\t25\nPosition\n\t0\t1\n",
+	}
+	for _, data := range invalids {
+		if _, err := ParseTrack(strings.NewReader(data)); err == nil {
+			t.Errorf("Expect an error for %q", data)
+		}
+	}
+}
+
+func TestTrackKeyframes(t *testing.T) {
+	track, _ := ParseTrack(strings.NewReader(mochaData))
+	cases := []struct {
+		tolerance float64
+		frames    []int
+	}{
+		{0, []int{0, 2, 3}},
+		{20, []int{0, 3}},
+	}
+	for _, c := range cases {
+		var frames []int
+		for _, f := range track.Keyframes(c.tolerance) {
+			frames = append(frames, f.Frame)
+		}
+		if !reflect.DeepEqual(frames, c.frames) {
+			t.Errorf("Expect keyframes %v with tolerance %v, got: %v", c.frames, c.tolerance, frames)
+		}
+	}
+}
+
+func TestTrackApply(t *testing.T) {
+	track, _ := ParseTrack(strings.NewReader(mochaData))
+	evt := &Event{Start: "0:00:01.00", End: "0:00:02.00", Style: "Sign", Text: `{\pos(1,1)\frz5\b1}Exit`}
+	cases := []struct {
+		opts   TrackOptions
+		expect []string
+	}{
+		{TrackOptions{}, []string{
+			`0:00:01.00 0:00:01.08 {\pos(100,200)\frz0}{\b1}Exit`,
+			`0:00:01.08 0:00:01.12 {\pos(120,200)\frz0}{\b1}Exit`,
+			`0:00:01.12 0:00:02.00 {\pos(120,210)\frz-10}{\b1}Exit`,
+		}},
+		{TrackOptions{Move: true}, []string{
+			`0:00:01.00 0:00:01.08 {\move(100,200,120,200)\frz0}{\b1}Exit`,
+			`0:00:01.08 0:00:01.12 {\move(120,200,120,210)\frz0\t(\frz-10)}{\b1}Exit`,
+			`0:00:01.12 0:00:02.00 {\pos(120,210)\frz-10}{\b1}Exit`,
+		}},
+	}
+	for _, c := range cases {
+		events, err := track.Apply(evt, c.opts)
+		if err != nil {
+			t.Fatalf("Expect no error, got: %v", err)
+		}
+		var got []string
+		for _, e := range events {
+			got = append(got, e.Start+" "+e.End+" "+e.Text)
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package ass
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormForm is a Unicode normalization form
+type NormForm int
+
+// The normalization forms
+const (
+	// NormNone leaves the text as it is
+	NormNone NormForm = iota
+	// NormNFC composes the characters, e.g. e and an acute accent into é
+	NormNFC
+	// NormNFKC also replaces the compatibility characters, e.g. ligatures
+	// and full-width letters, by their usual form
+	NormNFKC
+)
+
+// QuotePolicy is how NormalizeText writes the quotes
+type QuotePolicy int
+
+// The quote policies
+const (
+	// QuotesKeep leaves the quotes as they are
+	QuotesKeep QuotePolicy = iota
+	// QuotesStraight replaces the curly quotes by " and '
+	QuotesStraight
+	// QuotesCurly replaces the straight quotes by curly ones, opening after
+	// a space or an opening bracket and closing otherwise
+	QuotesCurly
+)
+
+// NormalizeOptions configures NormalizeText
+type NormalizeOptions struct {
+	Form NormForm
+	// HalfWidth replaces the full-width ASCII characters, e.g. Ａ, by their
+	// half-width form, except the full-width backslash
+	HalfWidth bool
+	// CollapseSpaces replaces the runs of spaces by a single space and
+	// trims the lines
+	CollapseSpaces bool
+	Quotes         QuotePolicy
+}
+
+// NormalizeText normalizes the plain text of dialogue text, the override
+// blocks are left as they are
+func NormalizeText(text string, opts NormalizeOptions) string {
+	parts := splitText(text)
+	var prev rune // the last character, for the quotes
+	for i, p := range parts {
+		if p.Override {
+			continue
+		}
+		t := p.Text
+		switch opts.Form {
+		case NormNFC:
+			t = norm.NFC.String(t)
+		case NormNFKC:
+			t = norm.NFKC.String(t)
+		}
+		if opts.HalfWidth || opts.Quotes != QuotesKeep || opts.CollapseSpaces {
+			var b strings.Builder
+			for _, r := range t {
+				if opts.HalfWidth && r >= '！' && r <= '～' && r != '＼' {
+					r -= '！' - '!'
+				}
+				switch opts.Quotes {
+				case QuotesStraight:
+					r = straightQuote(r)
+				case QuotesCurly:
+					r = curlyQuote(r, prev)
+				}
+				if opts.CollapseSpaces && unicode.IsSpace(r) {
+					if unicode.IsSpace(prev) {
+						continue
+					}
+					r = ' '
+				}
+				b.WriteRune(r)
+				if prev == '\\' && (r == 'N' || r == 'n' || r == 'h') {
+					// a line break or hard space
+					r = ' '
+				}
+				prev = r
+			}
+			t = b.String()
+		}
+		parts[i].Text = t
+	}
+	text = joinText(parts)
+	if opts.CollapseSpaces {
+		lines := strings.Split(text, `\N`)
+		for i, line := range lines {
+			lines[i] = trimPlain(line)
+		}
+		text = strings.Join(lines, `\N`)
+	}
+	return text
+}
+
+// NormalizeText normalizes the text of all the events but comments, see
+// NormalizeText, and returns the number of changed events
+func (as *Subtitle) NormalizeText(opts NormalizeOptions) int {
+	n := 0
+	for _, evt := range as.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		if text := NormalizeText(evt.Text, opts); text != evt.Text {
+			evt.Text = text
+			n++
+		}
+	}
+	return n
+}
+
+func straightQuote(r rune) rune {
+	switch r {
+	case '“', '”', '„', '‟':
+		return '"'
+	case '‘', '’', '‚', '‛':
+		return '\''
+	}
+	return r
+}
+
+// curlyQuote returns the curly form of a straight quote following prev
+func curlyQuote(r, prev rune) rune {
+	opening := prev == 0 || unicode.IsSpace(prev) || strings.ContainsRune("([{“‘-—", prev)
+	switch {
+	case r == '"' && opening:
+		return '“'
+	case r == '"':
+		return '”'
+	case r == '\'' && opening:
+		return '‘'
+	case r == '\'':
+		// closing quote or apostrophe
+		return '’'
+	}
+	return r
+}
@@ -0,0 +1,28 @@
+package ass
+
+import "testing"
+
+func TestNormalizeText(t *testing.T) {
+	cases := []struct {
+		text   string
+		opts   NormalizeOptions
+		expect string
+	}{
+		{"e\u0301te\u0301", NormalizeOptions{Form: NormNFC}, "\u00e9t\u00e9"},
+		{"ﬁne ①", NormalizeOptions{Form: NormNFKC}, "fine 1"},
+		{`Ｈｅｌｌｏ！＼{\fnＡ}　ｘ`, NormalizeOptions{HalfWidth: true}, `Hello!＼{\fnＡ}　x`},
+		{`  a   b \N  c{\i1}  d  `, NormalizeOptions{CollapseSpaces: true}, `a b\Nc{\i1} d`},
+		{"“Hi” ‘there’", NormalizeOptions{Quotes: QuotesStraight}, `"Hi" 'there'`},
+		{`"Hi," he said. "It's {\i1}'ok'"\N"Bye"`, NormalizeOptions{Quotes: QuotesCurly}, `“Hi,” he said. “It’s {\i1}‘ok’”\N“Bye”`},
+	}
+	for _, c := range cases {
+		if got := NormalizeText(c.text, c.opts); got != c.expect {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+	}
+
+	sub := &Subtitle{Events: []*Event{{Text: "a  b"}, {Text: "a b"}, {Text: "a  b", Comment: true}, nil}}
+	if n := sub.NormalizeText(NormalizeOptions{CollapseSpaces: true}); n != 1 || sub.Events[0].Text != "a b" || sub.Events[2].Text != "a  b" {
+		t.Errorf("Expect 1 changed event, got: %d", n)
+	}
+}
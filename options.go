@@ -0,0 +1,226 @@
+package ass
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Line endings of the output
+const (
+	LF   = "\n"
+	CRLF = "\r\n"
+)
+
+// Sections of an ass subtitle, in the default output order
+const (
+	SectionScriptInfo = "Script Info"
+	SectionStyles     = "V4+ Styles"
+	SectionEvents     = "Events"
+)
+
+const utf8BOM = "\xEF\xBB\xBF"
+
+// WriteOption configures how a subtitle is written
+type WriteOption func(*writeOptions)
+
+type writeOptions struct {
+	lineEnding string
+	bom        bool
+	padding    bool
+	sections   []string
+	sortEvents bool
+	canonical  bool
+	encoding   Encoding
+	template   string
+	funcs      template.FuncMap
+}
+
+func defaultWriteOptions() writeOptions {
+	return writeOptions{
+		lineEnding: LF,
+		padding:    true,
+		sections:   []string{SectionScriptInfo, SectionStyles, SectionEvents},
+	}
+}
+
+func (opts writeOptions) validate() error {
+	if opts.lineEnding != LF && opts.lineEnding != CRLF {
+		return fmt.Errorf("Invalid line ending: %q", opts.lineEnding)
+	}
+	if len(opts.sections) != 3 {
+		return fmt.Errorf("Invalid section order: %v", opts.sections)
+	}
+	seen := make(map[string]bool)
+	for _, section := range opts.sections {
+		switch section {
+		case SectionScriptInfo, SectionStyles, SectionEvents:
+		default:
+			return fmt.Errorf("Unknown section: %s", section)
+		}
+		if seen[section] {
+			return fmt.Errorf("Duplicated section: %s", section)
+		}
+		seen[section] = true
+	}
+	return nil
+}
+
+// WithLineEnding sets the line ending, LF or CRLF, LF by default
+func WithLineEnding(ending string) WriteOption {
+	return func(opts *writeOptions) {
+		opts.lineEnding = ending
+	}
+}
+
+// WithBOM writes an UTF-8 byte order mark before the subtitle
+func WithBOM(bom bool) WriteOption {
+	return func(opts *writeOptions) {
+		opts.bom = bom
+	}
+}
+
+// WithPadding pads the event margins with zeros to 4 digits, on by default
+func WithPadding(padding bool) WriteOption {
+	return func(opts *writeOptions) {
+		opts.padding = padding
+	}
+}
+
+// WithSectionOrder sets the order of the sections, all of them must be given
+func WithSectionOrder(sections ...string) WriteOption {
+	return func(opts *writeOptions) {
+		opts.sections = sections
+	}
+}
+
+// WithSortedEvents writes the events in order of start time
+func WithSortedEvents(sorted bool) WriteOption {
+	return func(opts *writeOptions) {
+		opts.sortEvents = sorted
+	}
+}
+
+// WithCanonical writes the subtitle in a canonical form, so the same
+// content always gives the same output: the sections in the default order,
+// the styles sorted by name, the events by start and end time, timestamps
+// formatted as h:mm:ss.cc, colors in upper case and margins padded.
+func WithCanonical(canonical bool) WriteOption {
+	return func(opts *writeOptions) {
+		opts.canonical = canonical
+	}
+}
+
+// WithTemplate customizes the output template. The text is parsed over the
+// default template, so it can redefine any of its named templates, which are
+// "Script Info", "V4+ Styles", "events header" and "event". The data of the
+// templates is the Subtitle, except for "event" which gets an Event.
+func WithTemplate(text string) WriteOption {
+	return func(opts *writeOptions) {
+		opts.template = text
+	}
+}
+
+// WithFuncs adds functions to the output template, to be used by the
+// template given by WithTemplate. The default functions, text and margin,
+// can be replaced too.
+func WithFuncs(funcs template.FuncMap) WriteOption {
+	return func(opts *writeOptions) {
+		if opts.funcs == nil {
+			opts.funcs = make(template.FuncMap)
+		}
+		for name, fn := range funcs {
+			opts.funcs[name] = fn
+		}
+	}
+}
+
+// sortedEvents returns a copy of events sorted by start time, the order
+// of events starting at the same time is kept
+func sortedEvents(events []*Event) []*Event {
+	sorted := append([]*Event{}, events...)
+	starts := make(map[*Event]Timestamp, len(sorted))
+	for _, evt := range sorted {
+		starts[evt], _ = evt.StartTime()
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return starts[sorted[i]] < starts[sorted[j]]
+	})
+	return sorted
+}
+
+// canonicalize puts the styles and events of the subtitle in canonical
+// form, see WithCanonical. They are copied, the originals are left as is.
+func (as *Subtitle) canonicalize() {
+	as.Styles = copyStyles(as.Styles)
+	for _, style := range as.Styles {
+		style.PrimaryColor = strings.ToUpper(style.PrimaryColor)
+		style.SecondColor = strings.ToUpper(style.SecondColor)
+		style.OutlineColor = strings.ToUpper(style.OutlineColor)
+		style.BackColor = strings.ToUpper(style.BackColor)
+	}
+	sort.SliceStable(as.Styles, func(i, j int) bool { return as.Styles[i].Name < as.Styles[j].Name })
+
+	as.Events = copyEvents(as.Events)
+	for _, evt := range as.Events {
+		if start, err := evt.StartTime(); err == nil {
+			evt.Start = start.String()
+		}
+		if end, err := evt.EndTime(); err == nil {
+			evt.End = end.String()
+		}
+	}
+	sort.SliceStable(as.Events, func(i, j int) bool {
+		a, b := as.Events[i], as.Events[j]
+		startA, _ := a.StartTime()
+		startB, _ := b.StartTime()
+		if startA != startB {
+			return startA < startB
+		}
+		endA, _ := a.EndTime()
+		endB, _ := b.EndTime()
+		switch {
+		case endA != endB:
+			return endA < endB
+		case a.Layer != b.Layer:
+			return a.Layer < b.Layer
+		case a.Style != b.Style:
+			return a.Style < b.Style
+		case a.Name != b.Name:
+			return a.Name < b.Name
+		case a.Text != b.Text:
+			return a.Text < b.Text
+		}
+		// the other fields, as written
+		return a.String() < b.String()
+	})
+}
+
+// lineEndingWriter replaces \n with another line ending
+type lineEndingWriter struct {
+	w      io.Writer
+	ending []byte
+}
+
+func (lw *lineEndingWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			n, err := lw.w.Write(p)
+			return written + n, err
+		}
+		if n, err := lw.w.Write(p[:i]); err != nil {
+			return written + n, err
+		}
+		if _, err := lw.w.Write(lw.ending); err != nil {
+			return written + i, err
+		}
+		written += i + 1
+		p = p[i+1:]
+	}
+	return written, nil
+}
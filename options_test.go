@@ -0,0 +1,152 @@
+package ass
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestWriteToWithOptions(t *testing.T) {
+	sub := Subtitle{
+		Events: []*Event{
+			{Start: "0:00:02.00", End: "0:00:03.00", Text: "second", MarginL: 5},
+			{Start: "0:00:01.00", End: "0:00:02.00", Text: "first"},
+		},
+	}
+
+	cases := []struct {
+		opts  []WriteOption
+		check func(string) bool
+	}{
+		{nil, func(s string) bool { return strings.Contains(s, ",0005,0000,0000,,second\n") }},
+		{[]WriteOption{WithPadding(false)}, func(s string) bool { return strings.Contains(s, ",5,0,0,,second\n") }},
+		{[]WriteOption{WithBOM(true)}, func(s string) bool { return strings.HasPrefix(s, utf8BOM+"\n[Script Info]") }},
+		{[]WriteOption{WithLineEnding(CRLF)}, func(s string) bool {
+			return strings.Count(s, "\r\n") == strings.Count(s, "\n") && strings.Contains(s, "second\r\n")
+		}},
+		{[]WriteOption{WithSortedEvents(true)}, func(s string) bool {
+			return strings.Index(s, "first") < strings.Index(s, "second")
+		}},
+		{[]WriteOption{WithSectionOrder(SectionEvents, SectionScriptInfo, SectionStyles)}, func(s string) bool {
+			return strings.HasPrefix(s, "\n[Events]") && strings.Index(s, "[Script Info]") < strings.Index(s, "[V4+ Styles]")
+		}},
+	}
+
+	for i, c := range cases {
+		var buf bytes.Buffer
+		if _, err := sub.WriteToWithOptions(&buf, c.opts...); err != nil {
+			t.Errorf("Case %d: expect write success, got: %v", i, err)
+			continue
+		}
+		if !c.check(buf.String()) {
+			t.Errorf("Case %d: unexpected output:\n%q", i, buf.String())
+		}
+	}
+
+	if sub.Events[0].Text != "second" {
+		t.Errorf("Expect events of the subtitle not sorted in place")
+	}
+
+	invalid := [][]WriteOption{
+		{WithLineEnding("\r")},
+		{WithSectionOrder(SectionEvents, SectionEvents, SectionStyles)},
+		{WithSectionOrder(SectionEvents)},
+	}
+	for _, opts := range invalid {
+		if _, err := sub.WriteToWithOptions(&bytes.Buffer{}, opts...); err == nil {
+			t.Errorf("Expect invalid options error, but passed")
+		}
+	}
+}
+
+func TestWriteToWithTemplate(t *testing.T) {
+	sub := Subtitle{
+		Title:  "Custom",
+		Events: []*Event{{Start: "0:00:00.00", End: "0:00:01.00", Text: "hello"}},
+	}
+
+	tpl := `{{define "Script Info"}}
+[Script Info]
+Title: {{upper .Title}}
+ScriptType: v4.00+
+YCbCr Matrix: TV.709
+{{end}}`
+	var buf bytes.Buffer
+	_, err := sub.WriteToWithOptions(&buf, WithFuncs(template.FuncMap{"upper": strings.ToUpper}), WithTemplate(tpl))
+	if err != nil {
+		t.Fatalf("Expect write success, got: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "\n[Script Info]\nTitle: CUSTOM\nScriptType: v4.00+\nYCbCr Matrix: TV.709\n\n[V4+ Styles]") {
+		t.Errorf("Expect custom script info, got: %s", out)
+	}
+	if !strings.Contains(out, ",hello\n") {
+		t.Errorf("Expect default events, got: %s", out)
+	}
+
+	if _, err := sub.WriteToWithOptions(&buf, WithTemplate(`{{define "event"}}{{unknown .}}{{end}}`)); err == nil {
+		t.Errorf("Expect template parse error, but passed")
+	}
+}
+
+func TestWriteToCanonical(t *testing.T) {
+	a := Subtitle{
+		Styles: []*Style{{Name: "Sign", PrimaryColor: "00ffffff"}, {Name: "Default"}},
+		Events: []*Event{
+			{Start: "0:00:02.00", End: "0:00:03.00", Style: "Default", Text: "second"},
+			{Start: "0:00:01.00", End: "0:00:04.00", Style: "Sign", Text: "sign"},
+			{Start: "00:00:01.00", End: "0:00:02:00", Style: "Default", Text: "first"},
+		},
+	}
+	b := Subtitle{
+		Styles: []*Style{{Name: "Default"}, {Name: "Sign", PrimaryColor: "00FFFFFF"}},
+		Events: []*Event{
+			{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "first"},
+			{Start: "0:00:01.00", End: "0:00:04.00", Style: "Sign", Text: "sign"},
+			{Start: "0:00:02.00", End: "0:00:03.00", Style: "Default", Text: "second"},
+		},
+	}
+	var bufA, bufB bytes.Buffer
+	if _, err := a.WriteToWithOptions(&bufA, WithCanonical(true), WithPadding(false), WithSectionOrder(SectionEvents, SectionStyles, SectionScriptInfo)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.WriteToWithOptions(&bufB, WithCanonical(true)); err != nil {
+		t.Fatal(err)
+	}
+	if bufA.String() != bufB.String() {
+		t.Errorf("Expect the same canonical output:\n%s\ngot:\n%s", bufB.String(), bufA.String())
+	}
+	if !strings.HasPrefix(bufA.String(), "\n[Script Info]") || !strings.Contains(bufA.String(), "0:00:01.00,0:00:02.00,Default,,0000,") {
+		t.Errorf("Expect the default order, padding and timestamps, got:\n%s", bufA.String())
+	}
+	if a.Styles[0].Name != "Sign" || a.Styles[0].PrimaryColor != "00ffffff" || a.Events[2].Start != "00:00:01.00" {
+		t.Errorf("Expect the subtitle left unchanged")
+	}
+}
+
+func TestWriteToCanonicalTies(t *testing.T) {
+	events := []*Event{
+		{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "b"},
+		{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "a"},
+		{Layer: 1, Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "a"},
+		{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Name: "Ann", Text: "a"},
+		{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "a", MarginV: 10},
+		{Start: "0:00:01.00", End: "0:00:02.00", Style: "Alt", Text: "z"},
+	}
+	a := Subtitle{Styles: []*Style{{Name: "Default"}, {Name: "Alt"}}, Events: events}
+	b := Subtitle{Styles: a.Styles}
+	for i := len(events) - 1; i >= 0; i-- {
+		b.Events = append(b.Events, events[i])
+	}
+	var bufA, bufB bytes.Buffer
+	if _, err := a.WriteToWithOptions(&bufA, WithCanonical(true)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.WriteToWithOptions(&bufB, WithCanonical(true)); err != nil {
+		t.Fatal(err)
+	}
+	if bufA.String() != bufB.String() {
+		t.Errorf("Expect the same canonical output whatever the order of the events:\n%s\ngot:\n%s", bufA.String(), bufB.String())
+	}
+}
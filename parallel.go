@@ -0,0 +1,66 @@
+package ass
+
+import (
+	"runtime"
+	"sync"
+)
+
+// parallelEvents is the number of events from which the checks of the
+// events are split across workers
+const parallelEvents = 4096
+
+// eventRange is a chunk of events, from lo to hi excluded
+type eventRange struct {
+	lo, hi int
+}
+
+// workerCount returns workers, or GOMAXPROCS if workers <= 0
+func workerCount(workers int) int {
+	if workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return workers
+}
+
+// eventRanges splits n events into a chunk per worker, a single chunk if
+// there are too few events to be worth it
+func eventRanges(n, workers int) []eventRange {
+	if n < parallelEvents || workers <= 1 {
+		return []eventRange{{0, n}}
+	}
+	size := (n + workers - 1) / workers
+	ranges := make([]eventRange, 0, workers)
+	for lo := 0; lo < n; lo += size {
+		hi := lo + size
+		if hi > n {
+			hi = n
+		}
+		ranges = append(ranges, eventRange{lo, hi})
+	}
+	return ranges
+}
+
+// parallel calls fn for each i in [0, n), running at most workers at once,
+// and returns when they are all done. fn is called in place if n is 1.
+func parallel(n, workers int, fn func(i int)) {
+	if n == 1 || workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, workers)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,105 @@
+package ass
+
+import (
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEventRanges(t *testing.T) {
+	cases := []struct {
+		n, workers int
+		expect     []eventRange
+	}{
+		{0, 4, []eventRange{{0, 0}}},
+		{100, 4, []eventRange{{0, 100}}},
+		{10000, 1, []eventRange{{0, 10000}}},
+		{10000, 4, []eventRange{{0, 2500}, {2500, 5000}, {5000, 7500}, {7500, 10000}}},
+		{10001, 3, []eventRange{{0, 3334}, {3334, 6668}, {6668, 10001}}},
+	}
+	for _, c := range cases {
+		if ranges := eventRanges(c.n, c.workers); !reflect.DeepEqual(ranges, c.expect) {
+			t.Errorf("Expect %v for %d events on %d workers, got: %v", c.expect, c.n, c.workers, ranges)
+		}
+	}
+}
+
+func TestParallel(t *testing.T) {
+	for _, workers := range []int{1, 3} {
+		var sum int64
+		done := make([]bool, 100)
+		parallel(len(done), workers, func(i int) {
+			done[i] = true
+			atomic.AddInt64(&sum, int64(i))
+		})
+		if sum != 4950 {
+			t.Errorf("Expect every index done once on %d workers, got sum: %d", workers, sum)
+		}
+	}
+}
+
+func newInvalidSubtitle(n int) *Subtitle {
+	sub := newBenchSubtitle(n)
+	for i := 0; i < n; i += 97 {
+		sub.Events[i] = nil
+	}
+	for i := 1; i < n; i += 89 {
+		if sub.Events[i] != nil {
+			sub.Events[i].Start = "bad"
+		}
+	}
+	return &sub
+}
+
+func TestParallelValidation(t *testing.T) {
+	sub := newInvalidSubtitle(3 * parallelEvents)
+	expect := sub.validateWith(1)
+	if err := sub.validateWith(4); !reflect.DeepEqual(err, expect) {
+		t.Errorf("Expect the same errors in parallel")
+	}
+	if errs, ok := expect.(ValidationErrors); !ok || len(errs) != 127+138 {
+		t.Errorf("Expect %d errors, got: %v", 127+138, expect)
+	}
+
+	v := NewValidator(Standard).AddRule(MaxLinesRule(1, SeverityWarning), ForbiddenTextRule(regexp.MustCompile(`Line 1\d\b`), SeverityInfo))
+	v.Workers = 1
+	issues := v.Check(sub)
+	v.Workers = 0
+	if !reflect.DeepEqual(v.Check(sub), issues) {
+		t.Errorf("Expect the same issues in parallel")
+	}
+}
+
+func BenchmarkValidator(b *testing.B) {
+	sub := newInvalidSubtitle(200000)
+	v := NewValidator(Standard).AddRule(InvalidTagRule(SeverityWarning), MaxLinesRule(2, SeverityWarning))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.Check(sub)
+	}
+}
+
+func TestParallelRulesWriting(t *testing.T) {
+	// the rules writing the subtitle must not change it while the built-in
+	// checks read it, run with -race
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	sub := newBenchSubtitle(2 * parallelEvents)
+	write := func(sub *Subtitle) []Issue {
+		if _, err := sub.WriteTo(ioutil.Discard); err != nil {
+			return []Issue{{Severity: SeverityError, Message: err.Error()}}
+		}
+		return nil
+	}
+	v := NewValidator(Standard).AddRule(write, write, write)
+	v.Workers = 4
+	if issues := v.Check(&sub); len(issues) != 0 {
+		t.Errorf("Expect no issues, got: %v", issues)
+	}
+	if sub.Styles[0].FontName != "" {
+		t.Errorf("Expect the styles unchanged by writing, got: %s", sub.Styles[0].FontName)
+	}
+}
@@ -0,0 +1,469 @@
+package ass
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// default field orders, used when a section has no Format line
+var (
+	defStyleFormat = []string{"name", "fontname", "fontsize", "primarycolour", "secondarycolour", "outlinecolour", "backcolour",
+		"bold", "italic", "underline", "strikeout", "scalex", "scaley", "spacing", "angle", "borderstyle", "outline", "shadow",
+		"alignment", "marginl", "marginr", "marginv", "encoding"}
+	defEventFormat = []string{"layer", "start", "end", "style", "name", "marginl", "marginr", "marginv", "effect", "text"}
+)
+
+// ctxCheckLines is how often, in lines, the parser checks its context
+const ctxCheckLines = 256
+
+// Parse reads an ass subtitle, the charset of the input is detected and
+// converted to UTF-8, see Subtitle.Charset. Gzip compressed input is
+// decompressed, and the first ass or ssa file of a zip archive is read.
+func Parse(r io.Reader) (*Subtitle, error) {
+	return ParseContext(context.Background(), r)
+}
+
+// ParseWithOptions is the same as Parse, configured by opts
+func ParseWithOptions(r io.Reader, opts ...ParseOption) (*Subtitle, error) {
+	return ParseContext(context.Background(), r, opts...)
+}
+
+// ParseContext is the same as ParseWithOptions, but gives up when ctx is
+// done
+func ParseContext(ctx context.Context, r io.Reader, opts ...ParseOption) (*Subtitle, error) {
+	var options parseOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	r, err := decompress(r, ".ass", ".ssa")
+	if err != nil {
+		return nil, err
+	}
+	decoded, charset, err := DetectEncoding(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newParser(options)
+	p.sub.charset = charset
+	if err := p.run(ctx, decoded); err != nil {
+		return nil, err
+	}
+	return p.sub, nil
+}
+
+// Charset returns the charset the subtitle was parsed from, as detected by
+// DetectEncoding, empty if it was not parsed. The subtitle itself is always
+// in UTF-8.
+func (as *Subtitle) Charset() string {
+	return as.charset
+}
+
+type parser struct {
+	sub         *Subtitle
+	section     string
+	raw         *RawSection // the current section, if not understood
+	styleFormat []string
+	eventFormat []string
+	lineNo      int
+	pool        *parsePool // nil unless pooling
+}
+
+func newParser(options parseOptions) *parser {
+	p := &parser{
+		sub:         &Subtitle{lines: newSourceLines()},
+		styleFormat: defStyleFormat,
+		eventFormat: defEventFormat,
+	}
+	if options.pooling {
+		p.pool = newParsePool()
+	}
+	return p
+}
+
+// run parses the lines of r, numbered from p.lineNo+1
+func (p *parser) run(ctx context.Context, r io.Reader) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			p.lineNo++
+			if p.lineNo%ctxCheckLines == 0 {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+			if err := p.parseLine(strings.TrimRight(line, "\r\n")); err != nil {
+				return fmt.Errorf("Line %d: %v", p.lineNo, err)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p.endRaw()
+	return nil
+}
+
+// ParseOption configures how a subtitle is parsed
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	pooling bool
+}
+
+// WithPooling allocates the events in blocks and shares the repeated style,
+// actor and effect strings, which cuts allocations and GC pressure when
+// parsing transcripts of hundreds of thousands of events. An event then
+// keeps its whole block in memory as long as it is referenced, so it is best
+// used when the events are kept together.
+func WithPooling(pooling bool) ParseOption {
+	return func(opts *parseOptions) {
+		opts.pooling = pooling
+	}
+}
+
+func (p *parser) parseLine(line string) error {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+		p.endRaw()
+		name := trimmed[1 : len(trimmed)-1]
+		p.section = strings.ToLower(name)
+		switch p.section {
+		case "script info", "v4+ styles", "v4 styles", "events":
+		default:
+			p.raw = &RawSection{Name: name}
+			p.sub.RawSections = append(p.sub.RawSections, p.raw)
+		}
+		return nil
+	}
+	if p.raw != nil {
+		p.raw.Lines = append(p.raw.Lines, line)
+		return nil
+	}
+	if trimmed == "" || strings.HasPrefix(trimmed, ";") {
+		return nil
+	}
+
+	colon := strings.IndexByte(line, ':')
+	if colon < 0 {
+		// not a key: value line, ignored like renderers do
+		return nil
+	}
+	key := strings.TrimSpace(line[:colon])
+	value := strings.TrimLeft(line[colon+1:], " \t")
+
+	switch p.section {
+	case "script info":
+		return p.parseInfo(key, value)
+	case "v4+ styles", "v4 styles":
+		switch strings.ToLower(key) {
+		case "format":
+			p.styleFormat = parseFormat(value)
+		case "style":
+			style, err := parseStyle(p.styleFormat, value)
+			if err != nil {
+				return err
+			}
+			p.sub.Styles = append(p.sub.Styles, style)
+			p.sub.lines.styles[style] = p.lineNo
+		}
+	case "events":
+		switch strings.ToLower(key) {
+		case "format":
+			p.eventFormat = parseFormat(value)
+		case "dialogue", "comment":
+			var evt *Event
+			var err error
+			if p.pool != nil {
+				evt, err = p.pool.parseEvent(p.eventFormat, value)
+			} else {
+				evt, err = parseEvent(p.eventFormat, value)
+			}
+			if err != nil {
+				return err
+			}
+			evt.Comment = strings.EqualFold(key, "comment")
+			p.sub.Events = append(p.sub.Events, evt)
+			p.sub.lines.events[evt] = p.lineNo
+		}
+	}
+	return nil
+}
+
+// endRaw drops the blank lines at the end of the current raw section
+func (p *parser) endRaw() {
+	if p.raw == nil {
+		return
+	}
+	lines := p.raw.Lines
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	p.raw.Lines, p.raw = lines, nil
+}
+
+func (p *parser) parseInfo(key, value string) error {
+	value = strings.TrimSpace(value)
+	if field, ok := infoFields[strings.ToLower(key)]; ok {
+		p.sub.lines.info[field] = p.lineNo
+	}
+	var err error
+	switch strings.ToLower(key) {
+	case "title":
+		p.sub.Title = value
+	case "original script":
+		p.sub.OriginScript = value
+	case "playresx":
+		p.sub.PlayerWidth, err = parseUint(value)
+	case "playresy":
+		p.sub.PlayerHeight, err = parseUint(value)
+	case "layoutresx":
+		p.sub.LayoutResX, err = parseUint(value)
+	case "layoutresy":
+		p.sub.LayoutResY, err = parseUint(value)
+	case "kerning":
+		p.sub.Kerning, err = parseYesNo(value)
+	case "language":
+		p.sub.Language = value
+	case "playdepth":
+		p.sub.PlayDepth, err = parseUint(value)
+	case "timer":
+		var timer float64
+		timer, err = strconv.ParseFloat(value, 32)
+		p.sub.Timer = float32(timer)
+	case "wrapstyle":
+		var style int
+		style, err = strconv.Atoi(value)
+		p.sub.WrapStyle = WrapStyle(style)
+	case "scaledborderandshadow":
+		p.sub.ScaledBorderAndShadow, err = parseYesNo(value)
+	case "collisions":
+		switch {
+		case strings.EqualFold(value, string(CollisionsNormal)):
+			p.sub.Collisions = CollisionsNormal
+		case strings.EqualFold(value, string(CollisionsReverse)):
+			p.sub.Collisions = CollisionsReverse
+		default:
+			err = fmt.Errorf("Invalid collisions: %s", value)
+		}
+	case "ycbcr matrix":
+		p.sub.YCbCrMatrix = YCbCrMatrix(value)
+		err = p.sub.YCbCrMatrix.validate()
+	}
+	if err != nil {
+		return fmt.Errorf("Invalid %s: %s", key, value)
+	}
+	return nil
+}
+
+// parseFormat parses the field names of a Format line, lower cased
+func parseFormat(value string) []string {
+	fields := strings.Split(value, ",")
+	for i, f := range fields {
+		fields[i] = strings.ToLower(strings.TrimSpace(f))
+	}
+	return fields
+}
+
+// splitFields splits a comma separated line into the fields of format,
+// the last field takes the rest of the line since it may contain commas
+func splitFields(format []string, value string) (map[string]string, error) {
+	fields := make(map[string]string, len(format))
+	if err := splitFieldsInto(fields, format, value); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// splitFieldsInto is splitFields filling a given map, which must be empty
+func splitFieldsInto(fields map[string]string, format []string, value string) error {
+	values := strings.SplitN(value, ",", len(format))
+	if len(values) != len(format) {
+		return fmt.Errorf("Expect %d fields, got: %d", len(format), len(values))
+	}
+	for i, name := range format {
+		if i == len(format)-1 {
+			fields[name] = values[i]
+			continue
+		}
+		fields[name] = strings.TrimSpace(values[i])
+	}
+	return nil
+}
+
+func parseStyle(format []string, value string) (*Style, error) {
+	fields, err := splitFields(format, value)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid style: %v", err)
+	}
+	style := &Style{
+		Name:         fields["name"],
+		FontName:     fields["fontname"],
+		PrimaryColor: parseColor(fields["primarycolour"]),
+		SecondColor:  parseColor(fields["secondarycolour"]),
+		OutlineColor: parseColor(fields["outlinecolour"]),
+		BackColor:    parseColor(fields["backcolour"]),
+		Bold:         parseBold(fields["bold"]),
+		Italic:       parseFlag(fields["italic"]),
+		Underline:    parseFlag(fields["underline"]),
+		StrikeOut:    parseFlag(fields["strikeout"]),
+		MarginL:      parseMargin(fields["marginl"]),
+		MarginR:      parseMargin(fields["marginr"]),
+		MarginV:      parseMargin(fields["marginv"]),
+	}
+	ints := []struct {
+		name string
+		dst  *int
+	}{
+		{"fontsize", &style.FontSize},
+		{"scalex", &style.ScaleX},
+		{"scaley", &style.ScaleY},
+		{"borderstyle", &style.BorderStyle},
+		{"alignment", &style.Alignment},
+		{"encoding", &style.Encoding},
+	}
+	for _, f := range ints {
+		v, ok := fields[f.name]
+		if !ok || v == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid style %s: %s", f.name, v)
+		}
+		*f.dst = int(math.Round(n))
+	}
+	floats := []struct {
+		name string
+		dst  *float64
+	}{
+		{"spacing", &style.Spacing},
+		{"angle", &style.Angle},
+		{"outline", &style.Outline},
+		{"shadow", &style.Shadow},
+	}
+	for _, f := range floats {
+		v, ok := fields[f.name]
+		if !ok || v == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid style %s: %s", f.name, v)
+		}
+		*f.dst = n
+	}
+	return style, nil
+}
+
+// parseColor converts &HAABBGGRR, &HBBGGRR& or a decimal number to an ABGR
+// color, invalid colors are kept so validation reports them
+func parseColor(v string) string {
+	if v == "" {
+		return ""
+	}
+	hex := strings.TrimSuffix(v, "&")
+	if strings.HasPrefix(strings.ToUpper(hex), "&H") {
+		hex = hex[2:]
+	} else if n, err := strconv.ParseInt(hex, 10, 64); err == nil {
+		hex = strconv.FormatUint(uint64(uint32(n)), 16)
+	}
+	if len(hex) < 8 {
+		hex = strings.Repeat("0", 8-len(hex)) + hex
+	}
+	return strings.ToUpper(hex)
+}
+
+// parseFlag converts a boolean style field, any non zero value is true (-1)
+func parseFlag(v string) int {
+	if v == "" || v == "0" {
+		return 0
+	}
+	return -1
+}
+
+// parseBold converts the bold style field, 1 being how an unset one is
+// written
+func parseBold(v string) int {
+	if v == "1" {
+		return 0
+	}
+	return parseFlag(v)
+}
+
+func parseUint(v string) (uint, error) {
+	n, err := strconv.ParseUint(strings.TrimSpace(v), 10, 32)
+	return uint(n), err
+}
+
+// parseYesNo parses the yes or no value of a script info field
+func parseYesNo(v string) (bool, error) {
+	switch strings.ToLower(v) {
+	case "yes":
+		return true, nil
+	case "no":
+		return false, nil
+	}
+	return false, fmt.Errorf("Invalid yes or no: %s", v)
+}
+
+// parseMargin parses an event margin, negative or invalid values become 0
+func parseMargin(v string) uint {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return uint(n)
+}
+
+func parseEvent(format []string, value string) (*Event, error) {
+	fields, err := splitFields(format, value)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid event: %v", err)
+	}
+	evt := &Event{}
+	if err := fillEvent(evt, fields); err != nil {
+		return nil, err
+	}
+	return evt, nil
+}
+
+// fillEvent sets the event from its fields
+func fillEvent(evt *Event, fields map[string]string) (err error) {
+	*evt = Event{
+		Start:   fields["start"],
+		End:     fields["end"],
+		Style:   fields["style"],
+		Name:    fields["name"],
+		MarginL: parseMargin(fields["marginl"]),
+		MarginR: parseMargin(fields["marginr"]),
+		MarginV: parseMargin(fields["marginv"]),
+		Effect:  Effect(fields["effect"]),
+		Text:    fields["text"],
+	}
+	if strings.HasPrefix(evt.Text, forcedMarker) {
+		evt.Forced, evt.Text = true, evt.Text[len(forcedMarker):]
+	}
+	evt.Extra, evt.Text = parseExtra(evt.Text)
+	if actor, ok := fields["actor"]; ok && evt.Name == "" {
+		evt.Name = actor
+	}
+	if layer := fields["layer"]; layer != "" {
+		if evt.Layer, err = strconv.Atoi(layer); err != nil {
+			return fmt.Errorf("Invalid event layer: %s", layer)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package ass
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleScript = `[Script Info]
+; comment
+Title: Sample
+Original Script: someone
+ScriptType: v4.00+
+PlayResX: 1280
+PlayResY: 720
+Timer: 100.0000
+WrapStyle: 2
+ScaledBorderAndShadow: yes
+YCbCr Matrix: TV.709
+Collisions: Reverse
+LayoutResX: 1920
+LayoutResY: 800
+Kerning: yes
+Language: en
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Default,Arial,48,&H00FFFFFF,&H000000FF,&H00000000,&H80000000,-1,0,0,0,100,100,0,0,1,2,2,2,10,10,10,1
+Style: Signs,Verdana,30.5,&HFFFFFF&,&H000000FF,&H00000000,&H00000000,0,1,0,0,90,100,0,0,1,2,2,8,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Actor, MarginL, MarginR, MarginV, Effect, Text
+Dialogue: 1,0:00:01.00,0:00:02.50,Default,Naru,0,0,0,,Hello, {\i1}world{\i0}
+Comment: 0,0:00:01.00,0:00:02.50,Default,,0,0,0,,TL note: a greeting
+Dialogue: 0,0:00:03.00,0:00:04.00,Signs,,0010,0000,0000,,  EXIT
+`
+
+func TestParse(t *testing.T) {
+	sub, err := Parse(strings.NewReader(strings.Replace(sampleScript, "\n", "\r\n", -1)))
+	if err != nil {
+		t.Fatalf("Expect parse success, got: %v", err)
+	}
+
+	if sub.Title != "Sample" || sub.OriginScript != "someone" || sub.PlayerWidth != 1280 || sub.PlayerHeight != 720 || sub.Timer != 100 || sub.WrapStyle != WrapNone || !sub.ScaledBorderAndShadow || sub.YCbCrMatrix != MatrixTV709 || sub.Collisions != CollisionsReverse ||
+		sub.LayoutResX != 1920 || sub.LayoutResY != 800 || !sub.Kerning || sub.Language != "en" {
+		t.Errorf("Unexpected script info: %+v", sub)
+	}
+
+	styles := []Style{
+		{Name: "Default", FontName: "Arial", FontSize: 48, PrimaryColor: "00FFFFFF", SecondColor: "000000FF", OutlineColor: "00000000", BackColor: "80000000", Bold: -1, ScaleX: 100, ScaleY: 100,
+			BorderStyle: 1, Outline: 2, Shadow: 2, Alignment: 2, MarginL: 10, MarginR: 10, MarginV: 10, Encoding: 1},
+		{Name: "Signs", FontName: "Verdana", FontSize: 31, PrimaryColor: "00FFFFFF", SecondColor: "000000FF", OutlineColor: "00000000", BackColor: "00000000", Italic: -1, ScaleX: 90, ScaleY: 100,
+			BorderStyle: 1, Outline: 2, Shadow: 2, Alignment: 8, MarginL: 10, MarginR: 10, MarginV: 10, Encoding: 1},
+	}
+	if len(sub.Styles) != len(styles) {
+		t.Fatalf("Expect %d styles, got: %d", len(styles), len(sub.Styles))
+	}
+	for i, style := range styles {
+		if *sub.Styles[i] != style {
+			t.Errorf("Expect %+v, got: %+v", style, *sub.Styles[i])
+		}
+	}
+
+	events := []Event{
+		{Layer: 1, Start: "0:00:01.00", End: "0:00:02.50", Style: "Default", Name: "Naru", Text: `Hello, {\i1}world{\i0}`},
+		{Start: "0:00:01.00", End: "0:00:02.50", Style: "Default", Text: "TL note: a greeting", Comment: true},
+		{Start: "0:00:03.00", End: "0:00:04.00", Style: "Signs", MarginL: 10, Text: "  EXIT"},
+	}
+	if len(sub.Events) != len(events) {
+		t.Fatalf("Expect %d events, got: %d", len(events), len(sub.Events))
+	}
+	for i, evt := range events {
+		if !reflect.DeepEqual(*sub.Events[i], evt) {
+			t.Errorf("Expect %+v, got: %+v", evt, *sub.Events[i])
+		}
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	sub := newBenchSubtitle(100)
+	var buf bytes.Buffer
+	if _, err := sub.WriteTo(&buf); err != nil {
+		t.Fatalf("Expect write success, got: %v", err)
+	}
+	parsed, err := Parse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Expect parse success, got: %v", err)
+	}
+	var again bytes.Buffer
+	if _, err := parsed.WriteTo(&again); err != nil {
+		t.Fatalf("Expect write success, got: %v", err)
+	}
+	if buf.String() != again.String() {
+		t.Errorf("Expect the same output after a round trip")
+	}
+}
+
+func TestZeroLengthCommentRoundTrip(t *testing.T) {
+	input := strings.Replace(sampleScript, "Comment: 0,0:00:01.00,0:00:02.50,Default,,0,0,0,,TL note: a greeting",
+		"Comment: 0,0:00:00.00,0:00:00.00,Default,,0,0,0,template line,{\\k10}", 1)
+	sub, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expect parse success, got: %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err := sub.WriteTo(&buf); err != nil {
+		t.Fatalf("Expect write success, got: %v", err)
+	}
+	parsed, err := Parse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Expect parse success, got: %v", err)
+	}
+	if !reflect.DeepEqual(parsed.Events, sub.Events) {
+		t.Errorf("Expect the events written back, got: %s", buf.String())
+	}
+
+	if _, err := New("").Comment(0, 0, "note").Build(); err != nil {
+		t.Errorf("Expect a zero-length comment built, got: %v", err)
+	}
+	if _, err := New("").Dialogue(0, 0, "line").Build(); err == nil {
+		t.Errorf("Expect a zero-length dialogue rejected, but passed")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	cases := []string{
+		"[Script Info]\nPlayResX: wide\n",
+		"[Script Info]\nScaledBorderAndShadow: maybe\n",
+		"[Script Info]\nYCbCr Matrix: TV.2020\n",
+		"[Script Info]\nCollisions: Sideways\n",
+		"[Events]\nDialogue: 0,0:00:00.00,0:00:01.00\n",
+		"[Events]\nDialogue: x,0:00:00.00,0:00:01.00,Default,,0,0,0,,text\n",
+		"[V4+ Styles]\nStyle: Default,Arial,big,&H00FFFFFF,&H00FFFFFF,&H00FFFFFF,&H00FFFFFF,0,0,0,0,100,100,0,0,1,2,2,2,10,10,10,1\n",
+	}
+
+	for _, c := range cases {
+		if _, err := Parse(strings.NewReader(c)); err == nil {
+			t.Errorf("Expect parse error for %q, but passed", c)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	sub := newBenchSubtitle(10)
+	if _, err := sub.WriteToContext(ctx, &bytes.Buffer{}); err != context.Canceled {
+		t.Errorf("Expect canceled write, got: %v", err)
+	}
+	if _, err := ParseContext(ctx, strings.NewReader(sampleScript)); err != context.Canceled {
+		t.Errorf("Expect canceled parse, got: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := sub.WriteToContext(context.Background(), &buf); err != nil || buf.Len() == 0 {
+		t.Errorf("Expect write success, got: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package ass
+
+import "fmt"
+
+// PatchOp is the kind of a patch operation
+type PatchOp string
+
+// The patch operations
+const (
+	PatchAddEvent    PatchOp = "addEvent"
+	PatchRemoveEvent PatchOp = "removeEvent"
+	PatchModifyEvent PatchOp = "modifyEvent"
+	PatchAddStyle    PatchOp = "addStyle"
+	PatchRemoveStyle PatchOp = "removeStyle"
+	PatchModifyStyle PatchOp = "modifyStyle"
+)
+
+// PatchOperation is an edit of a single event or style
+type PatchOperation struct {
+	Op PatchOp `json:"op"`
+	// Index is the index of the event, in the events as left by the
+	// previous operations. An added event is inserted at Index.
+	Index int `json:"index,omitempty"`
+	// Name is the name of the style removed or modified
+	Name string `json:"name,omitempty"`
+	// Event and Style are the added or modified item
+	Event *Event `json:"event,omitempty"`
+	Style *Style `json:"style,omitempty"`
+	// OldEvent and OldStyle are the removed or modified item as it was,
+	// if set the patch only applies when it is still the same
+	OldEvent *Event `json:"oldEvent,omitempty"`
+	OldStyle *Style `json:"oldStyle,omitempty"`
+}
+
+// Patch is a list of operations turning a subtitle into another, see
+// MakePatch. It doesn't carry the script info.
+type Patch []PatchOperation
+
+// MakePatch returns the patch turning the styles and events of a into
+// those of b. The styles are matched by name, the added ones appended, and
+// the events aligned like Diff does.
+func MakePatch(a, b *Subtitle) Patch {
+	var patch Patch
+	for _, c := range Diff(a, b) {
+		switch {
+		case c.Section != "Styles":
+		case c.Kind == ChangeRemoved:
+			old := *a.Styles[c.OldIndex]
+			patch = append(patch, PatchOperation{Op: PatchRemoveStyle, Name: old.Name, OldStyle: &old})
+		case c.Kind == ChangeModified:
+			old, style := *a.Styles[c.OldIndex], *b.Styles[c.NewIndex]
+			patch = append(patch, PatchOperation{Op: PatchModifyStyle, Name: old.Name, Style: &style, OldStyle: &old})
+		case c.Kind == ChangeAdded:
+			style := *b.Styles[c.NewIndex]
+			patch = append(patch, PatchOperation{Op: PatchAddStyle, Style: &style})
+		}
+	}
+
+	// the index in the events being patched
+	index := 0
+	for _, p := range alignEvents(a.Events, b.Events) {
+		switch {
+		case p.old < 0:
+			patch = append(patch, PatchOperation{Op: PatchAddEvent, Index: index, Event: copyEvent(b.Events[p.new])})
+			index++
+		case p.new < 0:
+			patch = append(patch, PatchOperation{Op: PatchRemoveEvent, Index: index, OldEvent: copyEvent(a.Events[p.old])})
+		case !sameEvent(a.Events[p.old], b.Events[p.new]):
+			patch = append(patch, PatchOperation{Op: PatchModifyEvent, Index: index,
+				Event: copyEvent(b.Events[p.new]), OldEvent: copyEvent(a.Events[p.old])})
+			index++
+		default:
+			index++
+		}
+	}
+	return patch
+}
+
+func copyEvent(evt *Event) *Event {
+	if evt == nil {
+		return nil
+	}
+	e := evt.clone()
+	return &e
+}
+
+// ApplyPatch applies the operations of the patch in order. The subtitle is
+// left as it is if any operation fails, e.g. when an event was changed
+// since the patch was made.
+func ApplyPatch(sub *Subtitle, patch Patch) error {
+	styles := append([]*Style(nil), sub.Styles...)
+	events := append([]*Event(nil), sub.Events...)
+	styleIndex := func(name string) int {
+		for i, style := range styles {
+			if style != nil && style.Name == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for n, op := range patch {
+		fail := func(format string, args ...interface{}) error {
+			return fmt.Errorf("Patch operation %d: %s", n+1, fmt.Sprintf(format, args...))
+		}
+		switch op.Op {
+		case PatchAddStyle, PatchModifyStyle, PatchRemoveStyle:
+			if op.Op == PatchAddStyle {
+				if op.Style == nil {
+					return fail("Missing style")
+				}
+				if styleIndex(op.Style.Name) >= 0 {
+					return fail("Style %s already exists", op.Style.Name)
+				}
+				s := *op.Style
+				styles = append(styles, &s)
+				continue
+			}
+			i := styleIndex(op.Name)
+			if i < 0 {
+				return fail("Style %s not found", op.Name)
+			}
+			if op.OldStyle != nil && *op.OldStyle != *styles[i] {
+				return fail("Style %s has changed", op.Name)
+			}
+			if op.Op == PatchRemoveStyle {
+				styles = append(styles[:i], styles[i+1:]...)
+				continue
+			}
+			if op.Style == nil {
+				return fail("Missing style")
+			}
+			s := *op.Style
+			styles[i] = &s
+
+		case PatchAddEvent:
+			if op.Index < 0 || op.Index > len(events) {
+				return fail("Invalid event index: %d", op.Index)
+			}
+			events = append(events, nil)
+			copy(events[op.Index+1:], events[op.Index:])
+			events[op.Index] = copyEvent(op.Event)
+
+		case PatchRemoveEvent, PatchModifyEvent:
+			if op.Index < 0 || op.Index >= len(events) {
+				return fail("Invalid event index: %d", op.Index)
+			}
+			if op.OldEvent != nil && !sameEvent(op.OldEvent, events[op.Index]) {
+				return fail("Event %d has changed", op.Index)
+			}
+			if op.Op == PatchRemoveEvent {
+				events = append(events[:op.Index], events[op.Index+1:]...)
+				continue
+			}
+			if op.Event == nil {
+				return fail("Missing event")
+			}
+			events[op.Index] = copyEvent(op.Event)
+
+		default:
+			return fail("Unknown operation: %s", op.Op)
+		}
+	}
+	sub.Styles, sub.Events = styles, events
+	return nil
+}
@@ -0,0 +1,101 @@
+package ass
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMakePatch(t *testing.T) {
+	a := &Subtitle{
+		Styles: []*Style{{Name: "Default", FontSize: 20}, {Name: "Old", FontSize: 20}, {Name: "Sign", FontSize: 30}},
+		Events: []*Event{
+			{Start: "0:00:01.00", End: "0:00:02.00", Text: "one"},
+			{Start: "0:00:02.00", End: "0:00:03.00", Text: "two"},
+			{Start: "0:00:03.00", End: "0:00:04.00", Text: "three"},
+			{Start: "0:00:04.00", End: "0:00:05.00", Text: "four"},
+		},
+	}
+	b := &Subtitle{
+		Styles: []*Style{{Name: "Default", FontSize: 24}, {Name: "Sign", FontSize: 30}, {Name: "New", FontSize: 10}},
+		Events: []*Event{
+			{Start: "0:00:00.50", End: "0:00:01.00", Text: "zero"},
+			{Start: "0:00:01.00", End: "0:00:02.00", Text: "one"},
+			{Start: "0:00:03.00", End: "0:00:04.00", Text: "three!"},
+			{Start: "0:00:04.00", End: "0:00:05.00", Text: "four"},
+			{Start: "0:00:05.00", End: "0:00:06.00", Text: "five"},
+		},
+	}
+	patch := MakePatch(a, b)
+	var ops []string
+	for _, op := range patch {
+		ops = append(ops, string(op.Op))
+	}
+	expect := "modifyStyle removeStyle addStyle addEvent removeEvent modifyEvent addEvent"
+	if strings.Join(ops, " ") != expect {
+		t.Errorf("Expect %s, got: %v", expect, ops)
+	}
+
+	// through JSON, as sent to a collaborator
+	data, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Patch
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyPatch(a, decoded); err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	if !reflect.DeepEqual(a.Events, b.Events) || !reflect.DeepEqual(a.Styles, []*Style{b.Styles[0], b.Styles[1], b.Styles[2]}) {
+		t.Errorf("Expect %v, got: %v", b.Events, a.Events)
+	}
+	b.Events[0].Text = "changed"
+	if a.Events[0].Text != "zero" {
+		t.Errorf("Expect the patched events to be copies")
+	}
+
+	if len(MakePatch(a, a)) != 0 {
+		t.Errorf("Expect an empty patch between equal subtitles")
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	newSub := func() *Subtitle {
+		return &Subtitle{
+			Styles: []*Style{{Name: "Default"}},
+			Events: []*Event{{Text: "one"}, {Text: "two"}},
+		}
+	}
+	cases := []struct {
+		patch  Patch
+		expect string
+	}{
+		{Patch{{Op: PatchRemoveEvent, Index: 2}}, "Patch operation 1: Invalid event index: 2"},
+		{Patch{{Op: PatchAddEvent, Index: 0, Event: &Event{Text: "zero"}}, {Op: PatchModifyEvent, Index: 1, Event: &Event{}, OldEvent: &Event{Text: "two"}}},
+			"Patch operation 2: Event 1 has changed"},
+		{Patch{{Op: PatchModifyEvent, Index: 0}}, "Patch operation 1: Missing event"},
+		{Patch{{Op: PatchAddStyle, Style: &Style{Name: "Default"}}}, "Patch operation 1: Style Default already exists"},
+		{Patch{{Op: PatchRemoveStyle, Name: "Sign"}}, "Patch operation 1: Style Sign not found"},
+		{Patch{{Op: PatchModifyStyle, Name: "Default", Style: &Style{Name: "Default"}, OldStyle: &Style{Name: "Default", FontSize: 1}}},
+			"Patch operation 1: Style Default has changed"},
+		{Patch{{Op: "rename"}}, "Patch operation 1: Unknown operation: rename"},
+	}
+	for _, c := range cases {
+		sub := newSub()
+		if err := ApplyPatch(sub, c.patch); err == nil || err.Error() != c.expect {
+			t.Errorf("Expect error %q, got: %v", c.expect, err)
+		}
+		if !reflect.DeepEqual(sub, newSub()) {
+			t.Errorf("Expect the subtitle unchanged, got: %v", sub.Events)
+		}
+	}
+
+	sub := newSub()
+	err := ApplyPatch(sub, Patch{{Op: PatchRemoveEvent, Index: 0}, {Op: PatchModifyEvent, Index: 0, Event: &Event{Text: "2"}}})
+	if err != nil || len(sub.Events) != 1 || sub.Events[0].Text != "2" {
+		t.Errorf("Expect the operations applied in order, got: %v, %v", sub.Events, err)
+	}
+}
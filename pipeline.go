@@ -0,0 +1,78 @@
+package ass
+
+import (
+	"fmt"
+	"time"
+)
+
+// Transformer is a processing step of a subtitle
+type Transformer interface {
+	Transform(sub *Subtitle) error
+}
+
+// TransformerFunc adapts a function to a Transformer
+type TransformerFunc func(sub *Subtitle) error
+
+// Transform calls fn
+func (fn TransformerFunc) Transform(sub *Subtitle) error {
+	return fn(sub)
+}
+
+// Pipeline runs transformers in order, stopping at the first error.
+// A pipeline is a transformer itself, so pipelines can be nested.
+type Pipeline struct {
+	steps []Transformer
+}
+
+// NewPipeline creates a pipeline of steps
+func NewPipeline(steps ...Transformer) *Pipeline {
+	return &Pipeline{steps: steps}
+}
+
+// Then appends steps to the pipeline
+func (p *Pipeline) Then(steps ...Transformer) *Pipeline {
+	p.steps = append(p.steps, steps...)
+	return p
+}
+
+// Transform runs the steps on sub
+func (p *Pipeline) Transform(sub *Subtitle) error {
+	for i, step := range p.steps {
+		if err := step.Transform(sub); err != nil {
+			return fmt.Errorf("Step %d: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Shift moves all the events by d, see Selection.Shift
+func Shift(d time.Duration) Transformer {
+	return TransformerFunc(func(sub *Subtitle) error {
+		return sub.Select().Shift(d)
+	})
+}
+
+// ResampleTo changes the script resolution, see Subtitle.Resample
+func ResampleTo(width, height uint) Transformer {
+	return TransformerFunc(func(sub *Subtitle) error {
+		return sub.Resample(width, height)
+	})
+}
+
+// Lint fails if the validator finds issues of error severity
+func Lint(v *Validator) Transformer {
+	return TransformerFunc(v.Validate)
+}
+
+// Restyle moves the events of style from to style to, \r resets included
+func Restyle(from, to string) Transformer {
+	return TransformerFunc(func(sub *Subtitle) error {
+		renames := map[string]string{from: to}
+		for _, evt := range sub.Events {
+			if evt != nil {
+				evt.renameStyles(renames)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,42 @@
+package ass
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPipeline(t *testing.T) {
+	sub := &Subtitle{
+		PlayerWidth:  640,
+		PlayerHeight: 360,
+		Styles:       []*Style{{Name: "Default", FontSize: 20}, {Name: "Sign", FontSize: 20}},
+		Events: []*Event{
+			{Start: "0:00:01.00", End: "0:00:02.00", Style: "Old", Text: `{\pos(10,10)}a{\rOld}b`},
+		},
+	}
+	called := 0
+	p := NewPipeline(Shift(time.Second), ResampleTo(1280, 720)).
+		Then(NewPipeline(Restyle("Old", "Sign"), Lint(NewValidator(Standard)))).
+		Then(TransformerFunc(func(*Subtitle) error {
+			called++
+			return nil
+		}))
+	if err := p.Transform(sub); err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	expect := &Event{Start: "0:00:02.00", End: "0:00:03.00", Style: "Sign", Text: `{\pos(20,20)}a{\rSign}b`}
+	if !reflect.DeepEqual(sub.Events[0], expect) || sub.Styles[0].FontSize != 40 || called != 1 {
+		t.Errorf("Expect %v, got: %v", expect, sub.Events[0])
+	}
+
+	sub.Events[0].End = "0:00:00.50"
+	err := NewPipeline(Lint(NewValidator(Standard)), TransformerFunc(func(*Subtitle) error {
+		called++
+		return nil
+	})).Transform(sub)
+	if err == nil || !strings.HasPrefix(err.Error(), "Step 1: ") || called != 1 {
+		t.Errorf("Expect the pipeline to stop at the failing lint, got: %v", err)
+	}
+}
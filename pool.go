@@ -0,0 +1,55 @@
+package ass
+
+import "fmt"
+
+// eventBlock is the number of events allocated at once by a parsePool
+const eventBlock = 1024
+
+// parsePool is the allocation state of a pooling parser, see WithPooling
+type parsePool struct {
+	events  []Event           // the unused part of the current block
+	strings map[string]string // the styles, actors and effects seen
+	fields  map[string]string // reused for each event line
+}
+
+func newParsePool() *parsePool {
+	return &parsePool{strings: make(map[string]string), fields: make(map[string]string)}
+}
+
+// parseEvent parses an event line like the package level parseEvent, but
+// takes the event from the current block instead of allocating it, reuses
+// the field map and interns the styles, actors and effects
+func (pp *parsePool) parseEvent(format []string, value string) (*Event, error) {
+	for name := range pp.fields {
+		delete(pp.fields, name)
+	}
+	if err := splitFieldsInto(pp.fields, format, value); err != nil {
+		return nil, fmt.Errorf("Invalid event: %v", err)
+	}
+	if len(pp.events) == 0 {
+		pp.events = make([]Event, eventBlock)
+	}
+	evt := &pp.events[0]
+	if err := fillEvent(evt, pp.fields); err != nil {
+		return nil, err
+	}
+	pp.events = pp.events[1:]
+	evt.Style = pp.intern(evt.Style)
+	evt.Name = pp.intern(evt.Name)
+	evt.Effect = Effect(pp.intern(string(evt.Effect)))
+	return evt, nil
+}
+
+// intern returns the first string seen equal to s
+func (pp *parsePool) intern(s string) string {
+	if s == "" {
+		return ""
+	}
+	if interned, ok := pp.strings[s]; ok {
+		return interned
+	}
+	// copied so the line s is cut from is not kept alive
+	s = string(append([]byte(nil), s...))
+	pp.strings[s] = s
+	return s
+}
@@ -0,0 +1,67 @@
+package ass
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseWithPooling(t *testing.T) {
+	sub := newBenchSubtitle(3000)
+	var buf bytes.Buffer
+	if _, err := sub.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expect, err := Parse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pooled, err := ParseWithOptions(bytes.NewReader(buf.Bytes()), WithPooling(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pooled.Events, expect.Events) {
+		t.Errorf("Expect the same events as without pooling")
+	}
+	if pooled.EventLine(pooled.Events[10]) != expect.EventLine(expect.Events[10]) {
+		t.Errorf("Expect the source lines of the events")
+	}
+	pp := newParsePool()
+	for _, line := range []string{"0,0:00:00.00,0:00:01.00,Default,Actor,0,0,0,,one", "0,0:00:01.00,0:00:02.00,Default,,0,0,0,,two"} {
+		if _, err := pp.parseEvent(defEventFormat, line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(pp.strings) != 2 || len(pp.events) != eventBlock-2 {
+		t.Errorf("Expect 2 strings interned and 2 events taken, got: %v, %d", pp.strings, eventBlock-len(pp.events))
+	}
+
+	_, err = ParseWithOptions(strings.NewReader("[Events]\nFormat: Layer, Start, End, Text\nDialogue: x,0:00:00.00,0:00:01.00,a\n"), WithPooling(true))
+	if err == nil || err.Error() != "Line 3: Invalid event layer: x" {
+		t.Errorf("Expect invalid layer error, got: %v", err)
+	}
+}
+
+func benchmarkParse(b *testing.B, opts ...ParseOption) {
+	sub := newBenchSubtitle(200000)
+	var buf bytes.Buffer
+	sub.WriteTo(&buf)
+	data := buf.Bytes()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseWithOptions(bytes.NewReader(data), opts...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	benchmarkParse(b)
+}
+
+func BenchmarkParsePooled(b *testing.B) {
+	benchmarkParse(b, WithPooling(true))
+}
@@ -0,0 +1,96 @@
+package ass
+
+// infoFields maps the script info keys, lower cased, to the Subtitle fields
+var infoFields = map[string]string{
+	"title":                 "Title",
+	"original script":       "OriginScript",
+	"playresx":              "PlayerWidth",
+	"playresy":              "PlayerHeight",
+	"layoutresx":            "LayoutResX",
+	"layoutresy":            "LayoutResY",
+	"kerning":               "Kerning",
+	"language":              "Language",
+	"playdepth":             "PlayDepth",
+	"timer":                 "Timer",
+	"wrapstyle":             "WrapStyle",
+	"scaledborderandshadow": "ScaledBorderAndShadow",
+	"collisions":            "Collisions",
+	"ycbcr matrix":          "YCbCrMatrix",
+}
+
+// sourceLines are the lines of the parsed file the elements come from
+type sourceLines struct {
+	info   map[string]int
+	styles map[*Style]int
+	events map[*Event]int
+}
+
+func newSourceLines() *sourceLines {
+	return &sourceLines{info: make(map[string]int), styles: make(map[*Style]int), events: make(map[*Event]int)}
+}
+
+// InfoLine returns the line number a script info field, by its name in
+// Subtitle, was parsed from, 0 if unknown
+func (as *Subtitle) InfoLine(field string) int {
+	if as.lines == nil {
+		return 0
+	}
+	return as.lines.info[field]
+}
+
+// StyleLine returns the line number the style was parsed from, 0 if unknown
+func (as *Subtitle) StyleLine(style *Style) int {
+	if as.lines == nil {
+		return 0
+	}
+	return as.lines.styles[style]
+}
+
+// EventLine returns the line number the event was parsed from, 0 if unknown
+func (as *Subtitle) EventLine(evt *Event) int {
+	if as.lines == nil {
+		return 0
+	}
+	return as.lines.events[evt]
+}
+
+// sourceLine returns the line number of a problem located as in
+// ValidationError, 0 if unknown
+func (as *Subtitle) sourceLine(section string, index int, field string) int {
+	switch section {
+	case "":
+		return as.InfoLine(field)
+	case "Styles":
+		if index >= 0 && index < len(as.Styles) {
+			return as.StyleLine(as.Styles[index])
+		}
+	case "Events":
+		if index >= 0 && index < len(as.Events) {
+			return as.EventLine(as.Events[index])
+		}
+	}
+	return 0
+}
+
+// clone returns the lines of the cloned styles and events, which are at the
+// same indexes as the originals
+func (lines *sourceLines) clone(as, cloned *Subtitle) *sourceLines {
+	if lines == nil {
+		return nil
+	}
+	c := newSourceLines()
+	for field, line := range lines.info {
+		c.info[field] = line
+	}
+	for i, style := range as.Styles {
+		if line, ok := lines.styles[style]; ok {
+			c.styles[cloned.Styles[i]] = line
+		}
+	}
+	for i, evt := range as.Events {
+		if line, ok := lines.events[evt]; ok {
+			c.events[cloned.Events[i]] = line
+		}
+	}
+	return c
+}
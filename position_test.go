@@ -0,0 +1,56 @@
+package ass
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSourceLines(t *testing.T) {
+	script := `[Script Info]
+Title: lines
+WrapStyle: 7
+
+[V4+ Styles]
+Style: Default,Arial,20,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,2,2,10,10,10,1
+
+[Events]
+Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,Fine
+Dialogue: 0,0:00:03.00,0:00:02.00,Default,,0,0,0,,Ends before it starts
+Dialogue: 0,0:00:04.00,0:00:05.00,Missing,,0,0,0,,Undefined style
+`
+	sub, err := Parse(strings.NewReader(script))
+	if err != nil {
+		t.Fatalf("Expect parse success, got: %v", err)
+	}
+	if line := sub.InfoLine("WrapStyle"); line != 3 {
+		t.Errorf("Expect WrapStyle at line 3, got: %d", line)
+	}
+	if line := sub.StyleLine(sub.Styles[0]); line != 6 {
+		t.Errorf("Expect style at line 6, got: %d", line)
+	}
+	if line := sub.EventLine(sub.Events[2]); line != 11 {
+		t.Errorf("Expect event at line 11, got: %d", line)
+	}
+	if line := sub.EventLine(&Event{}); line != 0 {
+		t.Errorf("Expect no line for a new event, got: %d", line)
+	}
+
+	errs, ok := sub.Validate().(ValidationErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("Expect 2 validation errors, got: %v", errs)
+	}
+	expects := []string{
+		"Line 3: WrapStyle: Invalid wrap style: 7",
+		"Line 10: Events[1].End: End time 0:00:02.00 is not after start time 0:00:03.00",
+	}
+	for i, expect := range expects {
+		if errs[i].Error() != expect {
+			t.Errorf("Expect %q, got: %q", expect, errs[i].Error())
+		}
+	}
+
+	issues := NewValidator(Strict).Check(sub.Clone())
+	if len(issues) != 3 || issues[2].Line != 11 || issues[2].Field != "Style" {
+		t.Errorf("Expect the undefined style at line 11, got: %v", issues)
+	}
+}
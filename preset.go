@@ -0,0 +1,110 @@
+package ass
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Preset is a common typesetting look. Presets are composable, see
+// Subtitle.ApplyPresets.
+type Preset interface {
+	// Expand gives evt the look by changing it and returns the extra events
+	// to draw behind it, from the bottom one
+	Expand(as *Subtitle, evt *Event) []*Event
+}
+
+// SoftShadow is a blurred drop shadow. A copy of the event below it only
+// shows its shadow, so that the text itself stays sharp.
+type SoftShadow struct {
+	// Offset is the distance of the shadow, in script pixels
+	Offset float64
+	// Blur is the strength of \blur
+	Blur float64
+	// Color is the AABBGGRR color of the shadow, black by default
+	Color string
+}
+
+// Expand implements Preset
+func (p SoftShadow) Expand(as *Subtitle, evt *Event) []*Event {
+	shadow := evt.clone()
+	shadow.Text = fmt.Sprintf(`{\1a&HFF&\3a&HFF&\shad%s\blur%s%s}`, formatNumber(p.Offset), formatNumber(p.Blur), presetColor(4, p.Color)) +
+		removeTags(evt.Text, isPresetTag)
+	evt.Text = prependTags(evt.Text, `\shad0`)
+	return []*Event{&shadow}
+}
+
+// Glow is a blurred halo around the text, drawn by a copy of the event
+// below it with a wide blurred border
+type Glow struct {
+	// Size is the width of the halo, in script pixels
+	Size float64
+	// Blur is the strength of \blur
+	Blur float64
+	// Color is the AABBGGRR color of the halo, black by default
+	Color string
+}
+
+// Expand implements Preset
+func (p Glow) Expand(as *Subtitle, evt *Event) []*Event {
+	glow := evt.clone()
+	glow.Text = fmt.Sprintf(`{\bord%s\shad0\blur%s%s%s}`, formatNumber(p.Size), formatNumber(p.Blur), presetColor(1, p.Color), presetColor(3, p.Color)) +
+		removeTags(evt.Text, isPresetTag)
+	return []*Event{&glow}
+}
+
+// Box is an opaque box behind the text, the look of BorderStyle 3 for a
+// single event whatever its style: the box is a drawing of the size of the
+// text measured by Measurer, ApproxMeasurer if nil, on an extra event below
+// it. Wrapping and collisions aren't taken into account.
+type Box struct {
+	// Padding is the space around the text, in script pixels
+	Padding float64
+	// Color is the AABBGGRR color of the box, black by default
+	Color    string
+	Measurer TextMeasurer
+}
+
+// Expand implements Preset
+func (p Box) Expand(as *Subtitle, evt *Event) []*Event {
+	rect, _ := as.textBox(evt, p.Measurer)
+	w, h := formatNumber(rect.Width+2*p.Padding), formatNumber(rect.Height+2*p.Padding)
+	box := evt.clone()
+	box.Text = fmt.Sprintf(`{\an7\pos(%s,%s)\bord0\shad0%s\p1}m 0 0 l %s 0 %s %s 0 %s`,
+		formatNumber(rect.X-p.Padding), formatNumber(rect.Y-p.Padding), presetColor(1, p.Color), w, w, h, h)
+	return []*Event{&box}
+}
+
+// ApplyPresets expands the event with the presets, in order, and returns it
+// with the extra events, from the bottom one: the extra events of a preset
+// are below those of the previous presets. The events are put on
+// consecutive layers from the layer of evt, evt on top.
+func (as *Subtitle) ApplyPresets(evt *Event, presets ...Preset) []*Event {
+	var events []*Event
+	for _, p := range presets {
+		events = append(p.Expand(as, evt), events...)
+	}
+	events = append(events, evt)
+	layer := evt.Layer
+	for i, e := range events {
+		e.Layer = layer + i
+	}
+	return events
+}
+
+// presetColor returns the color and alpha tags of color component n for an
+// AABBGGRR color, black if invalid
+func presetColor(n int, abgr string) string {
+	if !isValidABGR(abgr) {
+		abgr = "00000000"
+	}
+	return fmt.Sprintf(`\%dc&H%s&\%da&H%s&`, n, abgr[2:], n, abgr[:2])
+}
+
+// isPresetTag reports whether the tag is set by the presets on the copies
+// of an event: border, shadow, blur, colors and alpha
+func isPresetTag(tag string) bool {
+	return sizeTagReg.MatchString(tag) && !strings.HasPrefix(tag, `\fs`) ||
+		strings.HasPrefix(tag, `\be`) || strings.HasPrefix(tag, `\alpha`) ||
+		len(tag) > 2 && tag[1] >= '1' && tag[1] <= '4' && (tag[2] == 'c' || tag[2] == 'a') ||
+		strings.HasPrefix(tag, `\c&`) || tag == `\c`
+}
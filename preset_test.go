@@ -0,0 +1,57 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyPresets(t *testing.T) {
+	fixed := TextMeasurerFunc(func(text string, style *Style) (float64, float64) {
+		return float64(10 * len(text)), 20
+	})
+	sub := &Subtitle{PlayerWidth: 640, PlayerHeight: 480}
+	cases := []struct {
+		presets []Preset
+		expect  []string
+	}{
+		{[]Preset{SoftShadow{Offset: 3, Blur: 2, Color: "80000000"}}, []string{
+			`{\1a&HFF&\3a&HFF&\shad3\blur2\4c&H000000&\4a&H80&}{\b1}Hi!`,
+			`{\shad0\b1\bord3}Hi{\c&H0000FF&}!`,
+		}},
+		{[]Preset{Glow{Size: 4, Blur: 3, Color: "00ffffff"}}, []string{
+			`{\bord4\shad0\blur3\1c&Hffffff&\1a&H00&\3c&Hffffff&\3a&H00&}{\b1}Hi!`,
+			`{\b1\bord3}Hi{\c&H0000FF&}!`,
+		}},
+		{[]Preset{Glow{Size: 4}, Box{Padding: 5, Measurer: fixed}}, []string{
+			`{\an7\pos(300,453)\bord0\shad0\1c&H000000&\1a&H00&\p1}m 0 0 l 40 0 40 30 0 30`,
+			`{\bord4\shad0\blur0\1c&H000000&\1a&H00&\3c&H000000&\3a&H00&}{\b1}Hi!`,
+			`{\b1\bord3}Hi{\c&H0000FF&}!`,
+		}},
+	}
+	for _, c := range cases {
+		evt := &Event{Layer: 2, Text: `{\b1\bord3}Hi{\c&H0000FF&}!`}
+		var texts []string
+		for i, e := range sub.ApplyPresets(evt, c.presets...) {
+			texts = append(texts, e.Text)
+			if e.Layer != 2+i {
+				t.Errorf("Expect layer %d, got: %d", 2+i, e.Layer)
+			}
+		}
+		if !reflect.DeepEqual(texts, c.expect) {
+			t.Errorf("Expect %q, got: %q", c.expect, texts)
+		}
+	}
+}
+
+func TestBoxStyleMargins(t *testing.T) {
+	fixed := TextMeasurerFunc(func(text string, style *Style) (float64, float64) {
+		return float64(10 * len(text)), 20
+	})
+	sub := &Subtitle{PlayerWidth: 640, PlayerHeight: 480, Styles: []*Style{{Name: "Default", MarginL: 100, MarginV: 40}}}
+	events := Box{Padding: 5, Measurer: fixed}.Expand(sub, &Event{Style: "Default", Text: "Hi!"})
+	// centered between the margins of the style, 100 and 20 by default
+	expect := `{\an7\pos(340,415)\bord0\shad0\1c&H000000&\1a&H00&\p1}m 0 0 l 40 0 40 30 0 30`
+	if len(events) != 1 || events[0].Text != expect {
+		t.Errorf("Expect %s, got: %+v", expect, events)
+	}
+}
@@ -0,0 +1,171 @@
+//go:build preview
+// +build preview
+
+package ass
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// PreviewOptions configures the preview images
+type PreviewOptions struct {
+	// Width is the width of the image, the script width by default, the
+	// height keeps the aspect ratio of the script
+	Width int
+	// Background fills the image, transparent by default
+	Background color.Color
+}
+
+// Preview rasterizes the events shown at t, an approximate rendering for
+// thumbnails and visual QC: override tags other than \an, \a, \pos and
+// \move are ignored, all the text uses the Go Regular font, and lines are
+// neither wrapped nor moved out of each other's way. It is only built with
+// the preview build tag.
+func (as *Subtitle) Preview(t Timestamp, opts PreviewOptions) (*image.RGBA, error) {
+	w, h := as.PlayRes()
+	scale := 1.0
+	if opts.Width > 0 {
+		scale = float64(opts.Width) / float64(w)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, int(float64(w)*scale+0.5), int(float64(h)*scale+0.5)))
+	if opts.Background != nil {
+		draw.Draw(img, img.Bounds(), image.NewUniform(opts.Background), image.Point{}, draw.Src)
+	}
+
+	ttf, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return nil, err
+	}
+	faces := make(map[float64]font.Face)
+	defer func() {
+		for _, face := range faces {
+			face.Close()
+		}
+	}()
+
+	events := as.ActiveAt(t)
+	order := make(map[*Event]int, len(as.Events))
+	for i, evt := range as.Events {
+		order[evt] = i
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Layer != events[j].Layer {
+			return events[i].Layer < events[j].Layer
+		}
+		return order[events[i]] < order[events[j]]
+	})
+
+	for _, evt := range events {
+		if evt.Comment {
+			continue
+		}
+		style := as.styleByName(evt.Style)
+		if style == nil {
+			style = &Style{}
+		}
+		size := float64(style.FontSize)
+		if size <= 0 {
+			size = defaultFontSize
+		}
+		size *= scale
+		face, ok := faces[size]
+		if !ok {
+			face, err = opentype.NewFace(ttf, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingNone})
+			if err != nil {
+				return nil, err
+			}
+			faces[size] = face
+		}
+		drawPreviewEvent(img, evt, style, face, scale)
+	}
+	return img, nil
+}
+
+// WritePreviewPNG writes the preview of the events shown at t as a PNG
+func (as *Subtitle) WritePreviewPNG(w io.Writer, t Timestamp, opts PreviewOptions) error {
+	img, err := as.Preview(t, opts)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+func drawPreviewEvent(img *image.RGBA, evt *Event, style *Style, face font.Face, scale float64) {
+	align, x, y, positioned := textLayout(evt.Text, style.align())
+	var lines []string
+	for _, line := range strings.Split(evt.Text, `\N`) {
+		line = strings.Replace(StripTags(line), `\h`, " ", -1)
+		lines = append(lines, strings.Replace(line, `\n`, " ", -1))
+	}
+
+	bounds := img.Bounds()
+	lineHeight := face.Metrics().Height.Ceil()
+	ascent := face.Metrics().Ascent.Ceil()
+	total := lineHeight * len(lines)
+	l, r, v := evt.margins(style)
+	marginL, marginR, marginV := int(float64(l)*scale), int(float64(r)*scale), int(float64(v)*scale)
+
+	// the top of the text block
+	var top int
+	band := (align - 1) / 3
+	if positioned {
+		anchorY := int(y * scale)
+		top = [3]int{anchorY - total, anchorY - total/2, anchorY}[band]
+	} else {
+		top = [3]int{bounds.Dy() - marginV - total, (bounds.Dy() - total) / 2, marginV}[band]
+	}
+
+	outline := previewColor(style.OutlineColor, color.NRGBA{A: 0xff})
+	primary := previewColor(style.PrimaryColor, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff})
+	border := int(outline(style.Outline)*scale + 0.5)
+	d := &font.Drawer{Dst: img, Face: face}
+	for i, line := range lines {
+		width := d.MeasureString(line).Ceil()
+		var left int
+		switch column := (align - 1) % 3; {
+		case positioned:
+			left = int(x*scale) - [3]int{0, width / 2, width}[column]
+		case column == 0:
+			left = marginL
+		case column == 1:
+			left = marginL + (bounds.Dx()-marginL-marginR-width)/2
+		default:
+			left = bounds.Dx() - marginR - width
+		}
+		baseline := top + i*lineHeight + ascent
+
+		if border > 0 {
+			d.Src = image.NewUniform(outline)
+			for dx := -border; dx <= border; dx += border {
+				for dy := -border; dy <= border; dy += border {
+					d.Dot = fixed.P(left+dx, baseline+dy)
+					d.DrawString(line)
+				}
+			}
+		}
+		d.Src = image.NewUniform(primary)
+		d.Dot = fixed.P(left, baseline)
+		d.DrawString(line)
+	}
+}
+
+// previewColor converts an AABBGGRR style color, def if empty or invalid
+func previewColor(abgr string, def color.NRGBA) color.NRGBA {
+	if !isValidABGR(abgr) {
+		return def
+	}
+	v, _ := strconv.ParseUint(abgr, 16, 32)
+	return color.NRGBA{R: uint8(v), G: uint8(v >> 8), B: uint8(v >> 16), A: 0xff - uint8(v>>24)}
+}
@@ -0,0 +1,65 @@
+//go:build preview
+// +build preview
+
+package ass
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+)
+
+func TestPreview(t *testing.T) {
+	sub := &Subtitle{
+		PlayerWidth:  640,
+		PlayerHeight: 360,
+		Styles:       []*Style{{Name: "Default", FontSize: 40, PrimaryColor: "0000FFFF"}},
+		Events: []*Event{
+			{Start: "0:00:01.00", End: "0:00:03.00", Style: "Default", Text: `Bottom\Nline`},
+			{Start: "0:00:01.00", End: "0:00:03.00", Style: "Default", Text: `{\an8}Top`},
+			{Start: "0:00:01.00", End: "0:00:03.00", Style: "Default", Text: `{\pos(100,180)}Sign`},
+			{Start: "0:00:05.00", End: "0:00:06.00", Style: "Default", Text: "Later"},
+		},
+	}
+
+	img, err := sub.Preview(2*Timestamp(time.Second), PreviewOptions{Width: 320, Background: color.Black})
+	if err != nil {
+		t.Fatalf("Expect preview rendered, got: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 320 || b.Dy() != 180 {
+		t.Fatalf("Expect a 320x180 image, got: %v", b)
+	}
+	// some yellow text in the bottom, top and middle bands
+	yellow := func(y0, y1 int) bool {
+		for y := y0; y < y1; y++ {
+			for x := 0; x < 320; x++ {
+				if c := img.RGBAAt(x, y); c.R == 0xff && c.G == 0xff && c.B == 0 {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for _, band := range [][2]int{{0, 40}, {70, 110}, {140, 180}} {
+		if !yellow(band[0], band[1]) {
+			t.Errorf("Expect text between %d and %d", band[0], band[1])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := sub.WritePreviewPNG(&buf, 4*Timestamp(time.Second), PreviewOptions{}); err != nil {
+		t.Fatalf("Expect PNG written, got: %v", err)
+	}
+	empty, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Expect a valid PNG, got: %v", err)
+	}
+	if b := empty.Bounds(); b.Dx() != 640 || b.Dy() != 360 {
+		t.Errorf("Expect the script resolution, got: %v", b)
+	}
+	if _, _, _, a := empty.At(320, 300).RGBA(); a != 0 {
+		t.Errorf("Expect a transparent image without events")
+	}
+}
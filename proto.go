@@ -0,0 +1,395 @@
+package ass
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// MarshalProto encodes the subtitle as the Subtitle message of ass.proto
+func (as Subtitle) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = appendProtoString(b, 1, as.Title)
+	b = appendProtoString(b, 2, as.OriginScript)
+	b = appendProtoVarint(b, 3, uint64(as.PlayerWidth))
+	b = appendProtoVarint(b, 4, uint64(as.PlayerHeight))
+	b = appendProtoVarint(b, 5, uint64(as.PlayDepth))
+	if as.Timer != 0 {
+		b = appendProtoTag(b, 6, wireFixed32)
+		var fixed [4]byte
+		binary.LittleEndian.PutUint32(fixed[:], math.Float32bits(as.Timer))
+		b = append(b, fixed[:]...)
+	}
+	b = appendProtoVarint(b, 9, uint64(int64(as.WrapStyle)))
+	if as.ScaledBorderAndShadow {
+		b = appendProtoVarint(b, 10, 1)
+	}
+	b = appendProtoString(b, 11, string(as.YCbCrMatrix))
+	b = appendProtoString(b, 12, string(as.Collisions))
+	b = appendProtoVarint(b, 13, uint64(as.LayoutResX))
+	b = appendProtoVarint(b, 14, uint64(as.LayoutResY))
+	if as.Kerning {
+		b = appendProtoVarint(b, 15, 1)
+	}
+	b = appendProtoString(b, 17, as.Language)
+	for _, style := range as.Styles {
+		if style == nil {
+			return nil, fmt.Errorf("Style cannot be nil")
+		}
+		b = appendProtoBytes(b, 7, style.marshalProto())
+	}
+	for _, evt := range as.Events {
+		if evt == nil {
+			return nil, fmt.Errorf("Event cannot be nil")
+		}
+		b = appendProtoBytes(b, 8, evt.marshalProto())
+	}
+	for _, raw := range as.RawSections {
+		if raw == nil {
+			return nil, fmt.Errorf("Section cannot be nil")
+		}
+		b = appendProtoBytes(b, 16, raw.marshalProto())
+	}
+	return b, nil
+}
+
+func (style *Style) marshalProto() []byte {
+	var b []byte
+	b = appendProtoString(b, 1, style.Name)
+	b = appendProtoString(b, 2, style.FontName)
+	b = appendProtoVarint(b, 3, uint64(int64(style.FontSize)))
+	b = appendProtoString(b, 4, style.PrimaryColor)
+	b = appendProtoString(b, 5, style.SecondColor)
+	b = appendProtoString(b, 6, style.OutlineColor)
+	b = appendProtoString(b, 7, style.BackColor)
+	for i, flag := range []int{style.Bold, style.Italic, style.Underline, style.StrikeOut} {
+		if flag != 0 {
+			b = appendProtoVarint(b, 8+i, 1)
+		}
+	}
+	b = appendProtoVarint(b, 12, uint64(int64(style.ScaleX)))
+	b = appendProtoVarint(b, 13, uint64(int64(style.ScaleY)))
+	b = appendProtoDouble(b, 14, style.Spacing)
+	b = appendProtoDouble(b, 15, style.Angle)
+	b = appendProtoVarint(b, 16, uint64(int64(style.BorderStyle)))
+	b = appendProtoDouble(b, 17, style.Outline)
+	b = appendProtoDouble(b, 18, style.Shadow)
+	b = appendProtoVarint(b, 19, uint64(int64(style.Alignment)))
+	b = appendProtoVarint(b, 20, uint64(style.MarginL))
+	b = appendProtoVarint(b, 21, uint64(style.MarginR))
+	b = appendProtoVarint(b, 22, uint64(style.MarginV))
+	b = appendProtoVarint(b, 23, uint64(int64(style.Encoding)))
+	return b
+}
+
+func (evt *Event) marshalProto() []byte {
+	var b []byte
+	b = appendProtoVarint(b, 1, uint64(int64(evt.Layer)))
+	b = appendProtoString(b, 2, evt.Start)
+	b = appendProtoString(b, 3, evt.End)
+	b = appendProtoString(b, 4, evt.Style)
+	b = appendProtoString(b, 5, evt.Name)
+	b = appendProtoVarint(b, 6, uint64(evt.MarginL))
+	b = appendProtoVarint(b, 7, uint64(evt.MarginR))
+	b = appendProtoVarint(b, 8, uint64(evt.MarginV))
+	b = appendProtoString(b, 9, string(evt.Effect))
+	b = appendProtoString(b, 10, evt.Text)
+	if evt.Comment {
+		b = appendProtoVarint(b, 11, 1)
+	}
+	if evt.Forced {
+		b = appendProtoVarint(b, 12, 1)
+	}
+	keys := make([]string, 0, len(evt.Extra))
+	for k := range evt.Extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		// map entries are messages of key 1 and value 2
+		entry := appendProtoString(appendProtoString(nil, 1, k), 2, evt.Extra[k])
+		b = appendProtoBytes(b, 13, entry)
+	}
+	return b
+}
+
+func (s *RawSection) marshalProto() []byte {
+	b := appendProtoString(nil, 1, s.Name)
+	for _, line := range s.Lines {
+		// the blank lines are repeated values, kept even if empty
+		b = appendProtoBytes(b, 2, []byte(line))
+	}
+	return b
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func appendProtoTag(b []byte, field, wire int) []byte {
+	return appendUvarint(b, uint64(field<<3|wire))
+}
+
+// appendProtoVarint appends a varint field, omitted when 0 like proto3 does
+func appendProtoVarint(b []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	return appendUvarint(appendProtoTag(b, field, wireVarint), v)
+}
+
+func appendProtoDouble(b []byte, field int, v float64) []byte {
+	if v == 0 {
+		return b
+	}
+	var fixed [8]byte
+	binary.LittleEndian.PutUint64(fixed[:], math.Float64bits(v))
+	return append(appendProtoTag(b, field, wireFixed64), fixed[:]...)
+}
+
+func appendProtoString(b []byte, field int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = appendUvarint(appendProtoTag(b, field, wireBytes), uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendProtoBytes(b []byte, field int, data []byte) []byte {
+	b = appendUvarint(appendProtoTag(b, field, wireBytes), uint64(len(data)))
+	return append(b, data...)
+}
+
+// UnmarshalProto decodes a Subtitle message of ass.proto, unknown fields
+// are skipped
+func UnmarshalProto(data []byte) (*Subtitle, error) {
+	sub := &Subtitle{}
+	err := readProto(data, func(field int, v uint64, b []byte) error {
+		switch field {
+		case 1:
+			sub.Title = string(b)
+		case 2:
+			sub.OriginScript = string(b)
+		case 3:
+			sub.PlayerWidth = uint(v)
+		case 4:
+			sub.PlayerHeight = uint(v)
+		case 5:
+			sub.PlayDepth = uint(v)
+		case 6:
+			sub.Timer = math.Float32frombits(uint32(v))
+		case 7:
+			style, err := unmarshalProtoStyle(b)
+			if err != nil {
+				return err
+			}
+			sub.Styles = append(sub.Styles, style)
+		case 8:
+			evt, err := unmarshalProtoEvent(b)
+			if err != nil {
+				return err
+			}
+			sub.Events = append(sub.Events, evt)
+		case 9:
+			sub.WrapStyle = WrapStyle(int32(v))
+		case 10:
+			sub.ScaledBorderAndShadow = v != 0
+		case 11:
+			sub.YCbCrMatrix = YCbCrMatrix(b)
+		case 12:
+			sub.Collisions = Collisions(b)
+		case 13:
+			sub.LayoutResX = uint(v)
+		case 14:
+			sub.LayoutResY = uint(v)
+		case 15:
+			sub.Kerning = v != 0
+		case 17:
+			sub.Language = string(b)
+		case 16:
+			raw := &RawSection{}
+			err := readProto(b, func(field int, v uint64, b []byte) error {
+				switch field {
+				case 1:
+					raw.Name = string(b)
+				case 2:
+					raw.Lines = append(raw.Lines, string(b))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			sub.RawSections = append(sub.RawSections, raw)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func unmarshalProtoStyle(data []byte) (*Style, error) {
+	style := &Style{}
+	err := readProto(data, func(field int, v uint64, b []byte) error {
+		flag := 0
+		if v != 0 {
+			flag = -1
+		}
+		switch field {
+		case 1:
+			style.Name = string(b)
+		case 2:
+			style.FontName = string(b)
+		case 3:
+			style.FontSize = int(int32(v))
+		case 4:
+			style.PrimaryColor = string(b)
+		case 5:
+			style.SecondColor = string(b)
+		case 6:
+			style.OutlineColor = string(b)
+		case 7:
+			style.BackColor = string(b)
+		case 8:
+			style.Bold = flag
+		case 9:
+			style.Italic = flag
+		case 10:
+			style.Underline = flag
+		case 11:
+			style.StrikeOut = flag
+		case 12:
+			style.ScaleX = int(int32(v))
+		case 13:
+			style.ScaleY = int(int32(v))
+		case 14:
+			style.Spacing = math.Float64frombits(v)
+		case 15:
+			style.Angle = math.Float64frombits(v)
+		case 16:
+			style.BorderStyle = int(int32(v))
+		case 17:
+			style.Outline = math.Float64frombits(v)
+		case 18:
+			style.Shadow = math.Float64frombits(v)
+		case 19:
+			style.Alignment = int(int32(v))
+		case 20:
+			style.MarginL = uint(v)
+		case 21:
+			style.MarginR = uint(v)
+		case 22:
+			style.MarginV = uint(v)
+		case 23:
+			style.Encoding = int(int32(v))
+		}
+		return nil
+	})
+	return style, err
+}
+
+func unmarshalProtoEvent(data []byte) (*Event, error) {
+	evt := &Event{}
+	err := readProto(data, func(field int, v uint64, b []byte) error {
+		switch field {
+		case 1:
+			evt.Layer = int(int32(v))
+		case 2:
+			evt.Start = string(b)
+		case 3:
+			evt.End = string(b)
+		case 4:
+			evt.Style = string(b)
+		case 5:
+			evt.Name = string(b)
+		case 6:
+			evt.MarginL = uint(v)
+		case 7:
+			evt.MarginR = uint(v)
+		case 8:
+			evt.MarginV = uint(v)
+		case 9:
+			evt.Effect = Effect(b)
+		case 10:
+			evt.Text = string(b)
+		case 11:
+			evt.Comment = v != 0
+		case 12:
+			evt.Forced = v != 0
+		case 13:
+			var k, value string
+			if err := readProto(b, func(field int, _ uint64, b []byte) error {
+				switch field {
+				case 1:
+					k = string(b)
+				case 2:
+					value = string(b)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			evt.SetExtra(k, value)
+		}
+		return nil
+	})
+	return evt, err
+}
+
+// readProto calls fn for each field of a message, with the value of
+// numeric fields or the content of length delimited ones
+func readProto(data []byte, fn func(field int, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return fmt.Errorf("Invalid protobuf tag")
+		}
+		data = data[n:]
+		field, wire := int(tag>>3), int(tag&7)
+		var (
+			v uint64
+			b []byte
+		)
+		switch wire {
+		case wireVarint:
+			if v, n = binary.Uvarint(data); n <= 0 {
+				return fmt.Errorf("Invalid protobuf varint of field %d", field)
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return fmt.Errorf("Invalid protobuf fixed64 of field %d", field)
+			}
+			v, data = binary.LittleEndian.Uint64(data), data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return fmt.Errorf("Invalid protobuf fixed32 of field %d", field)
+			}
+			v, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < size {
+				return fmt.Errorf("Invalid protobuf length of field %d", field)
+			}
+			b, data = data[n:n+int(size)], data[n+int(size):]
+		default:
+			return fmt.Errorf("Unsupported protobuf wire type %d of field %d", wire, field)
+		}
+		if err := fn(field, v, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package ass
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalProto(t *testing.T) {
+	sub := Subtitle{
+		Title:                 "proto",
+		PlayerWidth:           1280,
+		PlayerHeight:          720,
+		Timer:                 100,
+		WrapStyle:             WrapNone,
+		ScaledBorderAndShadow: true,
+		YCbCrMatrix:           MatrixPC601,
+		Collisions:            CollisionsReverse,
+		LayoutResX:            1920,
+		LayoutResY:            800,
+		Kerning:               true,
+		Language:              "pt-BR",
+		Styles: []*Style{{Name: "Default", FontName: "Arial", FontSize: 20, PrimaryColor: "00FFFFFF", Bold: -1, ScaleX: 100, ScaleY: -100,
+			Spacing: 1.5, Angle: -10, BorderStyle: 3, Outline: 2, Shadow: 0.5, Alignment: 7, MarginL: 20, MarginV: 12, Encoding: 1}},
+		Events: []*Event{
+			{Layer: -1, Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Name: "Ann", MarginL: 10, Text: "héllo, {\\i1}world",
+				Extra: map[string]string{"id": "42", "status": ""}},
+			{Start: "0:00:02.00", End: "0:00:03.00", Comment: true, Forced: true},
+		},
+		RawSections: []*RawSection{{Name: "Fonts", Lines: []string{"font.ttf", "", "M1!7"}}},
+	}
+	data, err := sub.MarshalProto()
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	got, err := UnmarshalProto(data)
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	if !reflect.DeepEqual(*got, sub) {
+		t.Errorf("Expect %+v, got: %+v", sub, *got)
+	}
+
+	// the wire format of a message with one event, layer 1 and text hi
+	evt := Subtitle{Events: []*Event{{Layer: 1, Text: "hi"}}}
+	data, _ = evt.MarshalProto()
+	if expect := []byte{0x42, 0x06, 0x08, 0x01, 0x52, 0x02, 'h', 'i'}; !bytes.Equal(data, expect) {
+		t.Errorf("Expect %x, got: %x", expect, data)
+	}
+
+	if _, err := UnmarshalProto([]byte{0x42, 0x10, 0x08}); err == nil {
+		t.Errorf("Expect error for a truncated message")
+	}
+}
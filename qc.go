@@ -0,0 +1,177 @@
+package ass
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// QCProfile is a set of delivery constraints of a streaming platform
+type QCProfile struct {
+	Name string `json:"name"`
+	// MaxCharsPerLine limits the characters of each line
+	MaxCharsPerLine int `json:"maxCharsPerLine"`
+	// MaxLines limits the lines of an event
+	MaxLines int `json:"maxLines"`
+	// MaxCPS limits the reading speed, in characters per second
+	MaxCPS float64 `json:"maxCPS"`
+	// MinDuration and MaxDuration limit how long an event is on screen
+	MinDuration time.Duration `json:"minDuration"`
+	MaxDuration time.Duration `json:"maxDuration"`
+	// MinGapFrames is the minimal gap between two events, in frames
+	MinGapFrames int `json:"minGapFrames"`
+	// FrameRate is the frame rate of the video, used for the gaps
+	FrameRate float64 `json:"frameRate"`
+}
+
+// Built-in profiles following the common timed text style guides of
+// streaming services, 0 disables a constraint
+var (
+	NetflixProfile = QCProfile{
+		Name:            "netflix",
+		MaxCharsPerLine: 42,
+		MaxLines:        2,
+		MaxCPS:          20,
+		MinDuration:     5 * time.Second / 6,
+		MaxDuration:     7 * time.Second,
+		MinGapFrames:    2,
+		FrameRate:       24000.0 / 1001,
+	}
+	// NetflixChildrenProfile has the lower reading speed for children programs
+	NetflixChildrenProfile = QCProfile{
+		Name:            "netflix-children",
+		MaxCharsPerLine: 42,
+		MaxLines:        2,
+		MaxCPS:          17,
+		MinDuration:     5 * time.Second / 6,
+		MaxDuration:     7 * time.Second,
+		MinGapFrames:    2,
+		FrameRate:       24000.0 / 1001,
+	}
+)
+
+// QCResult is the outcome of checking a subtitle against a profile
+type QCResult struct {
+	Profile string  `json:"profile"`
+	Events  int     `json:"events"`
+	Passed  bool    `json:"passed"`
+	Issues  []Issue `json:"issues"`
+}
+
+// Check validates the subtitle and checks it against the profile, it
+// passes if there is no issue of error severity
+func (p QCProfile) Check(sub *Subtitle) QCResult {
+	issues := NewValidator(Standard).AddRule(p.Rules()...).Check(sub)
+	result := QCResult{Profile: p.Name, Events: len(sub.Events), Passed: true, Issues: issues}
+	for _, issue := range issues {
+		if issue.Severity >= SeverityError {
+			result.Passed = false
+		}
+	}
+	return result
+}
+
+// Rules returns the profile as validation rules
+func (p QCProfile) Rules() []Rule {
+	return []Rule{p.checkText, p.checkTiming, p.checkGaps}
+}
+
+// displayText returns the text as shown: tags removed, hard spaces as spaces
+func displayText(text string) string {
+	text = StripTags(text)
+	return strings.NewReplacer(`\n`, " ", `\h`, " ").Replace(text)
+}
+
+// displayLines returns the lines of the text as shown
+func displayLines(text string) []string {
+	return strings.Split(displayText(text), `\N`)
+}
+
+// countChars counts the characters read, line breaks excluded
+func countChars(text string) int {
+	n := 0
+	for _, line := range displayLines(text) {
+		n += utf8.RuneCountInString(line)
+	}
+	return n
+}
+
+func (p QCProfile) checkText(sub *Subtitle) []Issue {
+	return eventRule(func(evt *Event) *Issue {
+		lines := displayLines(evt.Text)
+		if p.MaxLines > 0 && len(lines) > p.MaxLines {
+			return &Issue{Severity: SeverityError, Rule: "max-lines", Field: "Text",
+				Message: fmt.Sprintf("Too many lines: %d > %d", len(lines), p.MaxLines)}
+		}
+		for _, line := range lines {
+			if n := utf8.RuneCountInString(line); p.MaxCharsPerLine > 0 && n > p.MaxCharsPerLine {
+				return &Issue{Severity: SeverityError, Rule: "max-chars-per-line", Field: "Text",
+					Message: fmt.Sprintf("Line too long: %d > %d characters", n, p.MaxCharsPerLine)}
+			}
+		}
+		return nil
+	})(sub)
+}
+
+func (p QCProfile) checkTiming(sub *Subtitle) []Issue {
+	var issues []Issue
+	for i, evt := range sub.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		start, end, err := evt.times()
+		if err != nil {
+			continue
+		}
+		add := func(rule, msg string) {
+			issues = append(issues, Issue{Severity: SeverityError, Rule: rule, Section: "Events", Index: i, Message: msg})
+		}
+		d := time.Duration(end - start)
+		if p.MinDuration > 0 && d < p.MinDuration {
+			add("min-duration", fmt.Sprintf("Too short: %v < %v", d, p.MinDuration))
+		}
+		if p.MaxDuration > 0 && d > p.MaxDuration {
+			add("max-duration", fmt.Sprintf("Too long: %v > %v", d, p.MaxDuration))
+		}
+		if chars := countChars(evt.Text); p.MaxCPS > 0 && d > 0 {
+			if cps := float64(chars) / d.Seconds(); cps > p.MaxCPS {
+				add("max-cps", fmt.Sprintf("Reading speed too high: %.1f > %.1f characters per second", cps, p.MaxCPS))
+			}
+		}
+	}
+	return issues
+}
+
+func (p QCProfile) checkGaps(sub *Subtitle) []Issue {
+	if p.MinGapFrames <= 0 || p.FrameRate <= 0 {
+		return nil
+	}
+	minGap := Timestamp(float64(p.MinGapFrames) / p.FrameRate * float64(time.Second))
+
+	type timed struct {
+		index      int
+		start, end Timestamp
+	}
+	var list []timed
+	for i, evt := range sub.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		if start, end, err := evt.times(); err == nil {
+			list = append(list, timed{i, start, end})
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].start < list[j].start })
+
+	var issues []Issue
+	for i := 1; i < len(list); i++ {
+		gap := list[i].start - list[i-1].end
+		if gap >= 0 && gap < minGap {
+			issues = append(issues, Issue{Severity: SeverityError, Rule: "min-gap", Section: "Events", Index: list[i].index,
+				Message: fmt.Sprintf("Gap to the previous event too small: %v < %d frames", time.Duration(gap), p.MinGapFrames)})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,50 @@
+package ass
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQCProfile(t *testing.T) {
+	sub := &Subtitle{
+		Events: []*Event{
+			{Start: "0:00:01.00", End: "0:00:03.00", Text: "Fine line"},
+			{Start: "0:00:03.20", End: "0:00:03.50", Text: "Short"},
+			{Start: "0:00:03.55", End: "0:00:06.00", Text: `one\Ntwo\Nthree`},
+			{Start: "0:00:07.00", End: "0:00:09.50", Text: strings.Repeat("x", 43)},
+			{Start: "0:00:10.00", End: "0:00:18.00", Text: "Too long"},
+			{Start: "0:00:20.00", End: "0:00:21.00", Text: `{\i1}Twenty three characters{\i0}`},
+			{Start: "0:00:21.00", End: "0:00:21.10", Text: "Comments are not checked, however long and fast", Comment: true},
+		},
+	}
+
+	result := NetflixProfile.Check(sub)
+	if result.Passed || result.Profile != "netflix" || result.Events != 7 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	expects := map[string][]int{
+		"min-duration":       {1},
+		"min-gap":            {2},
+		"max-lines":          {2},
+		"max-chars-per-line": {3},
+		"max-duration":       {4},
+		"max-cps":            {5},
+	}
+	got := make(map[string][]int)
+	for _, issue := range result.Issues {
+		got[issue.Rule] = append(got[issue.Rule], issue.Index)
+	}
+	for rule, indexes := range expects {
+		if len(got[rule]) != len(indexes) || got[rule][0] != indexes[0] {
+			t.Errorf("Expect %s issues at %v, got: %v", rule, indexes, got[rule])
+		}
+	}
+	if len(result.Issues) != len(expects) {
+		t.Errorf("Expect %d issues, got: %v", len(expects), result.Issues)
+	}
+
+	if !NetflixProfile.Check(&Subtitle{Events: sub.Events[:1]}).Passed {
+		t.Errorf("Expect a fine subtitle to pass")
+	}
+}
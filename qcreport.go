@@ -0,0 +1,192 @@
+package ass
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"time"
+)
+
+// Limits of the suspicious timing checks of QCReport
+const (
+	suspiciousDuration = time.Minute
+	suspiciousStart    = 10 * time.Hour
+	suspiciousBackstep = time.Minute
+)
+
+// QCCategory is a group of issues of a QualityReport
+type QCCategory struct {
+	Name   string  `json:"name"`
+	Issues []Issue `json:"issues"`
+}
+
+// QualityReport is the outcome of the pre-release checks of a subtitle,
+// the issues grouped by category, see QCReport
+type QualityReport struct {
+	Profile string `json:"profile"`
+	Events  int    `json:"events"`
+	// Passed is false if any issue is of error severity
+	Passed     bool         `json:"passed"`
+	Stats      Stats        `json:"stats"`
+	Categories []QCCategory `json:"categories"`
+}
+
+// qcCategories are the categories of a report, in order, and the rules
+// reporting their issues. The other issues are in Other.
+var qcCategories = []struct {
+	name  string
+	rules []string
+}{
+	{"Overlaps", []string{"overlap"}},
+	{"Reading speed", []string{"max-cps"}},
+	{"Gaps", []string{"min-gap"}},
+	{"Line length", []string{"max-chars-per-line", "max-lines"}},
+	{"Duration", []string{"min-duration", "max-duration"}},
+	{"Styles", []string{"missing-style"}},
+	{"Tags", []string{"invalid-tag"}},
+	{"Timestamps", []string{"suspicious-timing"}},
+	{"Other", nil},
+}
+
+// QCReport checks the subtitle before a release: the validation errors and
+// the constraints of the profile, plus overlapping events on a layer,
+// undefined styles, invalid override tags and suspicious timestamps, like
+// events lasting over a minute, starting after 10 hours or more than a
+// minute before the previous one. Overlaps, invalid tags and suspicious
+// timestamps are warnings.
+func QCReport(sub *Subtitle, profile QCProfile) *QualityReport {
+	result := NewValidator(Standard).AddRule(profile.Rules()...).
+		AddRule(overlapRule, missingStyleRule, InvalidTagRule(SeverityWarning), suspiciousTimingRule).
+		Check(sub)
+	report := &QualityReport{Profile: profile.Name, Events: len(sub.Events), Passed: true, Stats: sub.Stats()}
+	byRule := make(map[string]int)
+	for i, c := range qcCategories {
+		report.Categories = append(report.Categories, QCCategory{Name: c.name})
+		for _, rule := range c.rules {
+			byRule[rule] = i
+		}
+	}
+	for _, issue := range result {
+		if issue.Severity >= SeverityError {
+			report.Passed = false
+		}
+		i, ok := byRule[issue.Rule]
+		if !ok && issue.Section == "Events" && (issue.Field == "Start" || issue.Field == "End") {
+			i, ok = byRule["suspicious-timing"], true
+		}
+		if !ok {
+			i = len(qcCategories) - 1
+		}
+		report.Categories[i].Issues = append(report.Categories[i].Issues, issue)
+	}
+	for _, c := range report.Categories {
+		sort.SliceStable(c.Issues, func(i, j int) bool { return c.Issues[i].Index < c.Issues[j].Index })
+	}
+	return report
+}
+
+// overlapRule reports the events starting before an earlier event of the
+// same layer ends
+func overlapRule(sub *Subtitle) []Issue {
+	type timed struct {
+		index      int
+		start, end Timestamp
+	}
+	byLayer := make(map[int][]timed)
+	for i, evt := range sub.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		if start, end, err := evt.times(); err == nil {
+			byLayer[evt.Layer] = append(byLayer[evt.Layer], timed{i, start, end})
+		}
+	}
+	var issues []Issue
+	for _, list := range byLayer {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].start < list[j].start })
+		// the event ending the latest so far
+		last := 0
+		for i := 1; i < len(list); i++ {
+			if list[i].start < list[last].end {
+				issues = append(issues, Issue{Severity: SeverityWarning, Rule: "overlap", Section: "Events", Index: list[i].index,
+					Message: fmt.Sprintf("Overlaps event %d on layer %d", list[last].index, sub.Events[list[i].index].Layer)})
+			}
+			if list[i].end > list[last].end {
+				last = i
+			}
+		}
+	}
+	return issues
+}
+
+// missingStyleRule reports the events of an undefined style
+func missingStyleRule(sub *Subtitle) []Issue {
+	var issues []Issue
+	for i, evt := range sub.Events {
+		if evt == nil || evt.Comment || sub.styleByName(evt.Style) != nil {
+			continue
+		}
+		issues = append(issues, Issue{Severity: SeverityError, Rule: "missing-style", Section: "Events", Index: i, Field: "Style",
+			Message: fmt.Sprintf("Undefined style: %s", evt.Style)})
+	}
+	return issues
+}
+
+// suspiciousTimingRule reports the timestamps likely to be mistakes
+func suspiciousTimingRule(sub *Subtitle) []Issue {
+	var issues []Issue
+	var prev Timestamp
+	for i, evt := range sub.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		start, end, err := evt.times()
+		if err != nil {
+			continue
+		}
+		add := func(field, msg string) {
+			issues = append(issues, Issue{Severity: SeverityWarning, Rule: "suspicious-timing", Section: "Events", Index: i, Field: field, Message: msg})
+		}
+		switch {
+		case start.Duration() >= suspiciousStart:
+			add("Start", fmt.Sprintf("Starts after %v: %s", suspiciousStart, start))
+		case time.Duration(prev-start) > suspiciousBackstep:
+			add("Start", fmt.Sprintf("Starts more than %v before the previous event: %s", suspiciousBackstep, start))
+		}
+		if d := time.Duration(end - start); d > suspiciousDuration {
+			add("End", fmt.Sprintf("Lasts more than %v: %v", suspiciousDuration, d))
+		}
+		prev = start
+	}
+	return issues
+}
+
+// WriteJSON writes the report as JSON
+func (r *QualityReport) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+var qcReportTpl = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>QC report{{with .Profile}} ({{.}}){{end}}</title>
+</head>
+<body>
+<h1>QC report{{with .Profile}} ({{.}}){{end}}: {{if .Passed}}passed{{else}}failed{{end}}</h1>
+<p>{{.Events}} events, {{.Stats.Duration}} of dialogue, {{printf "%.1f" .Stats.AverageCPS}} characters per second on average</p>
+{{range .Categories}}<h2>{{.Name}} ({{len .Issues}})</h2>
+{{if .Issues}}<table>
+<tr><th>Severity</th><th>Event</th><th>Line</th><th>Message</th></tr>
+{{range .Issues}}<tr><td>{{.Severity}}</td><td>{{if eq .Section "Events"}}{{.Index}}{{end}}</td><td>{{with .Line}}{{.}}{{end}}</td><td>{{.Message}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}</body>
+</html>
+`))
+
+// WriteHTML writes the report as an HTML page, a table per category
+func (r *QualityReport) WriteHTML(w io.Writer) error {
+	return qcReportTpl.Execute(w, r)
+}
@@ -0,0 +1,124 @@
+package ass
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestQCReport(t *testing.T) {
+	sub := &Subtitle{
+		Styles: []*Style{{Name: "Default", FontSize: 20}},
+		Events: []*Event{
+			{Start: "0:00:01.00", End: "0:00:03.00", Style: "Default", Text: "Hello"},
+			{Start: "0:00:02.00", End: "0:00:04.00", Style: "Default", Text: "Overlapping, and far too fast for anyone to read it"},
+			{Start: "0:00:02.00", End: "0:00:04.00", Style: "Default", Layer: 1, Text: "Another layer"},
+			{Start: "0:00:05.00", End: "0:00:07.00", Style: "Sign", Text: `{\pos(1,1)\bogus}Exit`},
+			{Start: "0:00:07.01", End: "0:02:00.00", Style: "Default", Text: "A line that is really much longer than forty-two characters"},
+			{Start: "0:00:03.00", End: "0:00:04.00", Style: "Missing", Text: "Back", Comment: true},
+			{Start: "11:00:00.00", End: "11:00:02.00", Style: "Default", Text: "Late"},
+			{Start: "0:00:20.00", End: "0:00:22.00", Style: "Default", Text: `{\c&HZZ&}Back in time`},
+		},
+	}
+	report := QCReport(sub, NetflixProfile)
+	if report.Profile != "netflix" || report.Events != 8 || report.Passed || report.Stats.Events != 7 {
+		t.Errorf("Expect a failed report, got: %+v", report)
+	}
+	expect := map[string][]int{
+		"Overlaps":      {1, 7},
+		"Reading speed": {1},
+		"Gaps":          {4},
+		"Line length":   {1, 4},
+		"Duration":      {4},
+		"Styles":        {3},
+		"Tags":          {3, 7},
+		"Timestamps":    {4, 6, 7},
+		"Other":         nil,
+	}
+	if len(report.Categories) != len(expect) {
+		t.Fatalf("Expect %d categories, got: %d", len(expect), len(report.Categories))
+	}
+	for _, c := range report.Categories {
+		var indexes []int
+		for _, issue := range c.Issues {
+			indexes = append(indexes, issue.Index)
+		}
+		if len(indexes) != len(expect[c.Name]) {
+			t.Errorf("Expect %s issues of events %v, got: %v", c.Name, expect[c.Name], c.Issues)
+			continue
+		}
+		for i := range indexes {
+			if indexes[i] != expect[c.Name][i] {
+				t.Errorf("Expect %s issues of events %v, got: %v", c.Name, expect[c.Name], c.Issues)
+				break
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded QualityReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded.Passed || len(decoded.Categories) != len(expect) {
+		t.Errorf("Expect the report as JSON, got: %s, %v", buf.String(), err)
+	}
+
+	buf.Reset()
+	if err := report.WriteHTML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	for _, s := range []string{"<h1>QC report (netflix): failed</h1>", "<h2>Tags (2)</h2>", `Unknown tag: \bogus`, "<h2>Other (0)</h2>"} {
+		if !strings.Contains(html, s) {
+			t.Errorf("Expect %q in the HTML report, got: %s", s, html)
+		}
+	}
+
+	clean := &Subtitle{
+		Styles: []*Style{{Name: "Default"}},
+		Events: []*Event{{Start: "0:00:01.00", End: "0:00:03.00", Style: "Default", Text: "Fine"}},
+	}
+	if report := QCReport(clean, NetflixProfile); !report.Passed {
+		t.Errorf("Expect a passed report, got: %+v", report.Categories)
+	}
+}
+
+func TestInvalidTagRule(t *testing.T) {
+	cases := []struct {
+		text   string
+		expect string
+	}{
+		{`{\an8\pos(1,2)\fnArial\fs20\c&H00FF00&\1a&H80&\t(0,100,\frz10)\blur1.5\fscx-100\r}ok`, ""},
+		{`{note\i1}ok{\i}`, ""},
+		{`{\foo}x`, `Unknown tag: \foo`},
+		{`{\bord}x{\fs-}`, `Invalid tag argument: \fs-`},
+		{`{\fsbig}x`, `Unknown tag: \fsbig`},
+		{`{\pos 1,2}x`, `Invalid tag argument: \pos 1,2`},
+		{`{\i1 x`, "Unclosed override block"},
+	}
+	rule := InvalidTagRule(SeverityWarning)
+	for _, c := range cases {
+		issues := rule(&Subtitle{Events: []*Event{{Text: c.text}}})
+		switch {
+		case c.expect == "" && len(issues) > 0:
+			t.Errorf("Expect no issue for %s, got: %v", c.text, issues)
+		case c.expect != "" && (len(issues) != 1 || issues[0].Message != c.expect || issues[0].Rule != "invalid-tag"):
+			t.Errorf("Expect %q for %s, got: %v", c.expect, c.text, issues)
+		}
+	}
+}
+
+func TestOverlapRule(t *testing.T) {
+	sub := &Subtitle{Events: []*Event{
+		{Start: "0:00:00.00", End: "0:00:10.00", Text: "A"},
+		{Start: "0:00:01.00", End: "0:00:02.00", Text: "B"},
+		{Start: "0:00:03.00", End: "0:00:04.00", Text: "C"},
+		{Start: "0:00:10.00", End: "0:00:11.00", Text: "D"},
+	}}
+	issues := overlapRule(sub)
+	if len(issues) != 2 || issues[0].Index != 1 || issues[1].Index != 2 || issues[1].Message != "Overlaps event 0 on layer 0" {
+		t.Errorf("Expect B and C overlapping A, got: %v", issues)
+	}
+}
@@ -0,0 +1,134 @@
+package ass
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// ParseReaderAt parses the size bytes of r, e.g. a file, read at once in a
+// buffer which the strings of the subtitle are cut from, see ParseBytes.
+func ParseReaderAt(r io.ReaderAt, size int64, opts ...ParseOption) (*Subtitle, error) {
+	data := make([]byte, size)
+	if n, err := r.ReadAt(data, 0); int64(n) < size {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return ParseBytes(data, opts...)
+}
+
+// ParseBytes parses data, such as a memory-mapped file, without copying
+// it: the strings of the subtitle reference data, which must then not be
+// changed nor unmapped until Materialize is called. That saves the memory
+// and time of allocating each field for read-only analysis. Compressed or
+// non UTF-8 input is converted as by Parse, and copied.
+func ParseBytes(data []byte, opts ...ParseOption) (*Subtitle, error) {
+	sample, partial := data, false
+	if len(sample) > detectSize {
+		sample, partial = sample[:detectSize], true
+	}
+	switch {
+	case bytes.HasPrefix(data, []byte(utf8BOM)):
+		data = data[len(utf8BOM):]
+	case bytes.HasPrefix(data, gzipMagic), bytes.HasPrefix(data, zipMagic),
+		bytes.HasPrefix(data, []byte{0xFF, 0xFE}), bytes.HasPrefix(data, []byte{0xFE, 0xFF}),
+		sniffCharset(sample, partial) != "utf-8":
+		return ParseWithOptions(bytes.NewReader(data), opts...)
+	}
+
+	var options parseOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	p := newParser(options)
+	p.sub.charset = "utf-8"
+	if err := p.runString(context.Background(), bytesToString(data)); err != nil {
+		return nil, err
+	}
+	return p.sub, nil
+}
+
+// bytesToString returns a string sharing the memory of b
+func bytesToString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return *(*string)(unsafe.Pointer(&b))
+}
+
+// runString parses the lines of s, which are not copied
+func (p *parser) runString(ctx context.Context, s string) error {
+	for s != "" {
+		line := s
+		if i := strings.IndexByte(s, '\n'); i >= 0 {
+			line, s = s[:i], s[i+1:]
+		} else {
+			s = ""
+		}
+		p.lineNo++
+		if p.lineNo%ctxCheckLines == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if err := p.parseLine(strings.TrimRight(line, "\r")); err != nil {
+			return fmt.Errorf("Line %d: %v", p.lineNo, err)
+		}
+	}
+	p.endRaw()
+	return nil
+}
+
+// Materialize copies all the strings of the subtitle, so it doesn't
+// reference the data it was parsed from anymore, see ParseBytes.
+func (as *Subtitle) Materialize() {
+	materialize(reflect.ValueOf(as).Elem())
+}
+
+// materialize copies the strings of v, following pointers, slices, maps
+// and the exported fields of structs
+func materialize(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() && v.Len() > 0 {
+			v.SetString(string(append([]byte(nil), v.String()...)))
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			materialize(v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			materialize(v.Index(i))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if t.Field(i).PkgPath == "" {
+				materialize(v.Field(i))
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := reflect.New(v.Type().Key()).Elem()
+			key.Set(iter.Key())
+			materialize(key)
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(iter.Value())
+			materialize(value)
+			copied.SetMapIndex(key, value)
+		}
+		v.Set(copied)
+	}
+}
@@ -0,0 +1,75 @@
+package ass
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBytes(t *testing.T) {
+	data := []byte(utf8BOM + strings.Replace(lazyScript, "\n", "\r\n", -1))
+	sub, err := ParseBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect, _ := Parse(bytes.NewReader(data))
+	if !reflect.DeepEqual(sub.Events, expect.Events) || !reflect.DeepEqual(sub.RawSections, expect.RawSections) ||
+		sub.Title != expect.Title || sub.EventLine(sub.Events[1]) != 12 {
+		t.Errorf("Expect the same subtitle as Parse, got: %v", sub)
+	}
+
+	// the strings reference data until materialized
+	copy(data[bytes.Index(data, []byte("one")):], "ONE")
+	if sub.Events[0].Text != "ONE" {
+		t.Errorf("Expect the text to reference the data, got: %s", sub.Events[0].Text)
+	}
+	sub.Materialize()
+	copy(data[bytes.Index(data, []byte("ONE")):], "two")
+	copy(data[bytes.Index(data, []byte("Lazy")):], "Busy")
+	copy(data[bytes.Index(data, []byte("M4I2")):], "XXXX")
+	if sub.Events[0].Text != "ONE" || sub.Title != "Lazy" || sub.RawSections[0].Lines[1] != "M4I2" {
+		t.Errorf("Expect the subtitle detached from the data, got: %s, %s, %v", sub.Events[0].Text, sub.Title, sub.RawSections[0].Lines)
+	}
+}
+
+func TestParseBytesConverted(t *testing.T) {
+	var utf16 bytes.Buffer
+	w, _ := encodeWriter(&utf16, EncodingUTF16LE)
+	io.WriteString(w, lazyScript)
+	w.Close()
+	sub, err := ParseBytes(utf16.Bytes())
+	if err != nil || sub.Title != "Lazy" || len(sub.Events) != 2 {
+		t.Errorf("Expect the UTF-16 input converted, got: %v, %v", sub, err)
+	}
+}
+
+func TestParseReaderAt(t *testing.T) {
+	r := strings.NewReader(lazyScript)
+	sub, err := ParseReaderAt(r, r.Size())
+	if err != nil || len(sub.Styles) != 1 || len(sub.Events) != 2 {
+		t.Errorf("Expect the subtitle parsed, got: %v, %v", sub, err)
+	}
+	if _, err := ParseReaderAt(r, r.Size()+1); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expect unexpected EOF, got: %v", err)
+	}
+	if _, err := ParseBytes([]byte("[Events]\nDialogue: x,0:00:00.00,0:00:01.00,,,0,0,0,,a")); err == nil || err.Error() != "Line 2: Invalid event layer: x" {
+		t.Errorf("Expect invalid layer error, got: %v", err)
+	}
+}
+
+func BenchmarkParseBytes(b *testing.B) {
+	sub := newBenchSubtitle(200000)
+	var buf bytes.Buffer
+	sub.WriteTo(&buf)
+	data := buf.Bytes()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseBytes(data, WithPooling(true)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
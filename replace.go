@@ -0,0 +1,57 @@
+package ass
+
+import "regexp"
+
+// ReplaceOptions controls how ReplaceText treats the dialogue text
+type ReplaceOptions struct {
+	// SkipOverrides leaves override blocks untouched, only plain text is replaced
+	SkipOverrides bool
+	// SkipDrawings leaves the drawing commands after \p1 and so on untouched
+	SkipDrawings bool
+}
+
+// ReplaceText replaces every match of pattern in the dialogue text with repl,
+// as regexp.ReplaceAllString does, and returns the number of changed events.
+// With SkipOverrides set matches never span override blocks, so tags can't be
+// corrupted.
+func (as *Subtitle) ReplaceText(pattern *regexp.Regexp, repl string, opts ReplaceOptions) int {
+	changed := 0
+	for _, evt := range as.Events {
+		if evt == nil {
+			continue
+		}
+		text := replaceText(evt.Text, pattern, repl, opts)
+		if text != evt.Text {
+			evt.Text = text
+			changed++
+		}
+	}
+	return changed
+}
+
+func replaceText(text string, pattern *regexp.Regexp, repl string, opts ReplaceOptions) string {
+	if !opts.SkipOverrides && !opts.SkipDrawings {
+		return pattern.ReplaceAllString(text, repl)
+	}
+
+	parts := splitText(text)
+	drawing := false
+	for i, p := range parts {
+		if p.Override {
+			for _, tag := range splitTags(p.Text) {
+				if level, ok := drawingTag(tag); ok {
+					drawing = level > 0
+				}
+			}
+			if !opts.SkipOverrides {
+				parts[i].Text = pattern.ReplaceAllString(p.Text, repl)
+			}
+			continue
+		}
+		if drawing && opts.SkipDrawings {
+			continue
+		}
+		parts[i].Text = pattern.ReplaceAllString(p.Text, repl)
+	}
+	return joinText(parts)
+}
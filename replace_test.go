@@ -0,0 +1,33 @@
+package ass
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestReplaceText(t *testing.T) {
+	cases := []struct {
+		text    string
+		pattern string
+		repl    string
+		opts    ReplaceOptions
+		expect  string
+	}{
+		{`{\b1}bold`, `b`, `B`, ReplaceOptions{}, `{\B1}Bold`},
+		{`{\b1}bold`, `b`, `B`, ReplaceOptions{SkipOverrides: true}, `{\b1}Bold`},
+		{`{\p1}m 0 0 l 10 10{\p0}m`, `m`, `M`, ReplaceOptions{SkipDrawings: true}, `{\p1}m 0 0 l 10 10{\p0}M`},
+		{`{\pos(1,2)}m`, `m`, `M`, ReplaceOptions{SkipOverrides: true, SkipDrawings: true}, `{\pos(1,2)}M`},
+		{`Tom and tom`, `(?i)tom`, `Jerry`, ReplaceOptions{SkipOverrides: true}, `Jerry and Jerry`},
+	}
+
+	for _, c := range cases {
+		sub := &Subtitle{Events: []*Event{{Text: c.text}}}
+		n := sub.ReplaceText(regexp.MustCompile(c.pattern), c.repl, c.opts)
+		if got := sub.Events[0].Text; got != c.expect {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+		if n != 1 {
+			t.Errorf("Expect 1 changed event, got: %d", n)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package ass
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	sizeTagReg  = regexp.MustCompile(`^\\(fsp|fs|xbord|ybord|bord|xshad|yshad|shad|blur)(-?[\d.]+)$`)
+	pointTagReg = regexp.MustCompile(`^\\(pos|org|move|i?clip)\((.*)\)$`)
+)
+
+// PlayRes returns the script resolution, PlayResX and PlayResY, with the
+// defaults applied when writing if they are not set
+func (as *Subtitle) PlayRes() (uint, uint) {
+	sub := Subtitle{PlayerWidth: as.PlayerWidth, PlayerHeight: as.PlayerHeight}
+	sub.fulfill()
+	return sub.PlayerWidth, sub.PlayerHeight
+}
+
+// Resample changes the script resolution to newW x newH and rescales
+// everything measured in script pixels: font sizes, margins, \pos, \move,
+// \org and clip coordinates, drawings, border, shadow, blur and spacing.
+// Sizes follow the vertical ratio, coordinates the ratio of their axis.
+// When the aspect ratio changes, the horizontal scale of the styles and of
+// the \fscx tags follows the ratio of the axes, so the text is stretched
+// like the video.
+func (as *Subtitle) Resample(newW, newH uint) error {
+	if newW == 0 || newH == 0 {
+		return fmt.Errorf("Invalid resolution: %dx%d", newW, newH)
+	}
+	oldW, oldH := as.PlayRes()
+	if oldW == 0 || oldH == 0 {
+		return fmt.Errorf("Invalid resolution: %dx%d", oldW, oldH)
+	}
+	r := resampler{sx: float64(newW) / float64(oldW), sy: float64(newH) / float64(oldH)}
+
+	for _, style := range as.Styles {
+		if style == nil {
+			continue
+		}
+		style.FontSize = int(math.Round(float64(style.FontSize) * r.sy))
+		if r.sx != r.sy {
+			style.ScaleX = int(math.Round(float64(scale(style.ScaleX)) * r.sx / r.sy))
+		}
+		style.Spacing *= r.sx
+		// the unset outline and margins are scaled from their defaults
+		style.Outline = outline(style.Outline) * r.sy
+		style.Shadow *= r.sy
+		ml, mr, mv := style.margins()
+		style.MarginL = uint(math.Round(float64(ml) * r.sx))
+		style.MarginR = uint(math.Round(float64(mr) * r.sx))
+		style.MarginV = uint(math.Round(float64(mv) * r.sy))
+	}
+	for _, evt := range as.Events {
+		if evt == nil {
+			continue
+		}
+		evt.MarginL = uint(math.Round(float64(evt.MarginL) * r.sx))
+		evt.MarginR = uint(math.Round(float64(evt.MarginR) * r.sx))
+		evt.MarginV = uint(math.Round(float64(evt.MarginV) * r.sy))
+		evt.Text = r.text(evt.Text)
+	}
+	as.PlayerWidth, as.PlayerHeight = newW, newH
+	return nil
+}
+
+type resampler struct {
+	sx, sy float64
+}
+
+func (r resampler) text(text string) string {
+	parts := splitText(text)
+	drawing := false
+	for i, p := range parts {
+		if !p.Override {
+			if drawing {
+				parts[i].Text = r.drawing(p.Text)
+			}
+			continue
+		}
+		for _, tag := range splitTags(p.Text) {
+			if level, ok := drawingTag(tag); ok {
+				drawing = level > 0
+			}
+		}
+		parts[i].Text = r.block(p.Text)
+	}
+	return joinText(parts)
+}
+
+// block rescales the tags of an override block
+func (r resampler) block(block string) string {
+	first := strings.IndexByte(block, '\\')
+	if first < 0 {
+		return block
+	}
+	tags := splitTags(block)
+	for i, tag := range tags {
+		tags[i] = r.tag(tag)
+	}
+	return block[:first] + strings.Join(tags, "")
+}
+
+func (r resampler) tag(tag string) string {
+	if strings.HasPrefix(tag, `\t(`) && strings.HasSuffix(tag, ")") {
+		// the animated tags are the last argument of \t
+		inner := tag[3 : len(tag)-1]
+		return `\t(` + r.block(inner) + ")"
+	}
+	if m := sizeTagReg.FindStringSubmatch(tag); m != nil {
+		v, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			return tag
+		}
+		scale := r.sy
+		if m[1] == "fsp" || m[1] == "xbord" || m[1] == "xshad" {
+			scale = r.sx
+		}
+		return `\` + m[1] + formatNumber(v*scale)
+	}
+	if strings.HasPrefix(tag, `\fscx`) && r.sx != r.sy {
+		if v, err := strconv.ParseFloat(tag[len(`\fscx`):], 64); err == nil {
+			return `\fscx` + formatNumber(v*r.sx/r.sy)
+		}
+		return tag
+	}
+	if m := pointTagReg.FindStringSubmatch(tag); m != nil {
+		args := strings.Split(m[2], ",")
+		switch {
+		case strings.HasSuffix(m[1], "clip") && len(args) != 4:
+			// vector clip, with an optional scale before the drawing
+			last := len(args) - 1
+			args[last] = r.drawing(args[last])
+		case m[1] == "move" && len(args) >= 4:
+			// the optional times are not coordinates
+			r.points(args[:4])
+		default:
+			r.points(args)
+		}
+		return `\` + m[1] + "(" + strings.Join(args, ",") + ")"
+	}
+	return tag
+}
+
+// points rescales x,y pairs, invalid numbers are kept
+func (r resampler) points(args []string) {
+	for i, arg := range args {
+		v, err := strconv.ParseFloat(strings.TrimSpace(arg), 64)
+		if err != nil {
+			continue
+		}
+		if i%2 == 0 {
+			args[i] = formatNumber(v * r.sx)
+		} else {
+			args[i] = formatNumber(v * r.sy)
+		}
+	}
+}
+
+// drawing rescales the coordinates of drawing commands
+func (r resampler) drawing(cmds string) string {
+	fields := strings.Fields(cmds)
+	n := 0
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			continue
+		}
+		if n%2 == 0 {
+			fields[i] = formatNumber(v * r.sx)
+		} else {
+			fields[i] = formatNumber(v * r.sy)
+		}
+		n++
+	}
+	return strings.Join(fields, " ")
+}
+
+// formatNumber formats a number with at most 3 decimals
+func formatNumber(v float64) string {
+	v = math.Round(v*1000) / 1000
+	if v == 0 {
+		// no -0
+		v = 0
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,87 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResample(t *testing.T) {
+	sub := &Subtitle{
+		PlayerWidth:  640,
+		PlayerHeight: 360,
+		Styles:       []*Style{{Name: "Default", FontSize: 20}},
+		Events: []*Event{
+			{MarginL: 10, MarginR: 5, MarginV: 15, Text: `{\pos(320,180)\fs30\bord2\xshad1\fscx120}Hi`},
+			{Text: `{\move(0,0,100,50,0,500)\org(10,20)\clip(1,2,3,4)\t(0,100,\fs40\blur1)}Move`},
+			{Text: `{\clip(m 0 0 l 10 10)\p1}m 0 0 l 100 50 b 1 2 3 4 5 6{\p0}text 2 3`},
+		},
+	}
+
+	if err := sub.Resample(1920, 1080); err != nil {
+		t.Fatalf("Expect resample success, got: %v", err)
+	}
+	if sub.PlayerWidth != 1920 || sub.PlayerHeight != 1080 || sub.Styles[0].FontSize != 60 {
+		t.Errorf("Unexpected resampled subtitle: %+v %+v", sub, sub.Styles[0])
+	}
+
+	events := []Event{
+		{MarginL: 30, MarginR: 15, MarginV: 45, Text: `{\pos(960,540)\fs90\bord6\xshad3\fscx120}Hi`},
+		{Text: `{\move(0,0,300,150,0,500)\org(30,60)\clip(3,6,9,12)\t(0,100,\fs120\blur3)}Move`},
+		{Text: `{\clip(m 0 0 l 30 30)\p1}m 0 0 l 300 150 b 3 6 9 12 15 18{\p0}text 2 3`},
+	}
+	for i, evt := range events {
+		if !reflect.DeepEqual(*sub.Events[i], evt) {
+			t.Errorf("Expect %+v, got: %+v", evt, *sub.Events[i])
+		}
+	}
+
+	if err := sub.Resample(0, 1080); err == nil {
+		t.Errorf("Expect invalid resolution error, but passed")
+	}
+}
+
+func TestResampleAspectRatio(t *testing.T) {
+	sub := &Subtitle{
+		PlayerWidth:  640,
+		PlayerHeight: 480,
+		Styles: []*Style{{
+			Name: "Default", FontSize: 20, Spacing: 1, Outline: 2, Shadow: 1,
+			MarginL: 10, MarginR: 10, MarginV: 20,
+		}},
+		Events: []*Event{{Text: `{\fs30\fscx90}Wide{\fscx}`}},
+	}
+	if err := sub.Resample(1920, 1080); err != nil {
+		t.Fatalf("Expect resample success, got: %v", err)
+	}
+	// 3 times wider, 2.25 times higher
+	style := Style{
+		Name: "Default", FontSize: 45, ScaleX: 133, Spacing: 3, Outline: 4.5, Shadow: 2.25,
+		MarginL: 30, MarginR: 30, MarginV: 45,
+	}
+	if !reflect.DeepEqual(*sub.Styles[0], style) {
+		t.Errorf("Expect %+v, got: %+v", style, *sub.Styles[0])
+	}
+	if text := `{\fs67.5\fscx120}Wide{\fscx}`; sub.Events[0].Text != text {
+		t.Errorf("Expect %s, got: %s", text, sub.Events[0].Text)
+	}
+}
+
+func TestPlayRes(t *testing.T) {
+	cases := []struct {
+		width, height uint
+		expectW       uint
+		expectH       uint
+	}{
+		{0, 0, 1920, 1080},
+		{1280, 720, 1280, 720},
+		{0, 720, 1280, 720},
+		{1280, 0, 1280, 720},
+		{0, 480, 853, 480},
+	}
+	for _, c := range cases {
+		sub := &Subtitle{PlayerWidth: c.width, PlayerHeight: c.height}
+		if w, h := sub.PlayRes(); w != c.expectW || h != c.expectH {
+			t.Errorf("Expect %dx%d for %dx%d, got: %dx%d", c.expectW, c.expectH, c.width, c.height, w, h)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package ass
+
+import "fmt"
+
+// ReviewState is the progress of an event through the workflow of a team,
+// kept in its extra data under "review", see Event.Extra
+type ReviewState string
+
+// The review states, in the order of the workflow
+const (
+	// ReviewDraft is the state of the events not translated yet, also of
+	// those without a state
+	ReviewDraft ReviewState = "draft"
+	// ReviewTranslated events have been translated
+	ReviewTranslated ReviewState = "translated"
+	// ReviewTimed events have had their timing checked
+	ReviewTimed ReviewState = "timed"
+	// ReviewQCed events have passed the quality checks
+	ReviewQCed ReviewState = "qced"
+	// ReviewApproved events are ready for release
+	ReviewApproved ReviewState = "approved"
+)
+
+// reviewKey is the key of the review state in the extra data of an event
+const reviewKey = "review"
+
+var reviewStates = []ReviewState{ReviewDraft, ReviewTranslated, ReviewTimed, ReviewQCed, ReviewApproved}
+
+// rank returns the position of the state in the workflow, -1 if unknown
+func (s ReviewState) rank() int {
+	for i, state := range reviewStates {
+		if s == state {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s ReviewState) validate() error {
+	if s.rank() < 0 {
+		return fmt.Errorf("Invalid review state: %s", string(s))
+	}
+	return nil
+}
+
+// AtLeast reports whether s is state or a later one in the workflow.
+// Unknown states come before all the others.
+func (s ReviewState) AtLeast(state ReviewState) bool {
+	return s.rank() >= state.rank()
+}
+
+// Review returns the review state of the event, ReviewDraft if it has none
+func (evt *Event) Review() ReviewState {
+	if state, ok := evt.Extra[reviewKey]; ok && state != "" {
+		return ReviewState(state)
+	}
+	return ReviewDraft
+}
+
+// SetReview sets the review state of the event
+func (evt *Event) SetReview(state ReviewState) error {
+	if err := state.validate(); err != nil {
+		return err
+	}
+	evt.SetExtra(reviewKey, string(state))
+	return nil
+}
+
+// ByReview keeps the events in one of the review states
+func (sel *Selection) ByReview(states ...ReviewState) *Selection {
+	return sel.Filter(func(evt *Event) bool {
+		state := evt.Review()
+		for _, s := range states {
+			if state == s {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// ReviewBefore keeps the events which have not reached state yet
+func (sel *Selection) ReviewBefore(state ReviewState) *Selection {
+	return sel.Filter(func(evt *Event) bool {
+		return !evt.Review().AtLeast(state)
+	})
+}
+
+// SetReview sets the review state of the selected events
+func (sel *Selection) SetReview(state ReviewState) error {
+	if err := state.validate(); err != nil {
+		return err
+	}
+	for _, evt := range sel.events {
+		evt.SetExtra(reviewKey, string(state))
+	}
+	return nil
+}
+
+// ReviewReport is the progress of the review of a subtitle, comments left
+// out, see Subtitle.ReviewReport
+type ReviewReport struct {
+	Events  int                 `json:"events"`
+	ByState map[ReviewState]int `json:"byState"`
+	// Untranslated are the indexes of the events still draft
+	Untranslated []int `json:"untranslated"`
+	// Unreviewed are the indexes of the events not QCed yet
+	Unreviewed []int `json:"unreviewed"`
+	// Done is the share of the events approved, from 0 to 1
+	Done float64 `json:"done"`
+}
+
+// ReviewReport counts the events by review state and lists those left to
+// translate or review
+func (as *Subtitle) ReviewReport() ReviewReport {
+	report := ReviewReport{ByState: make(map[ReviewState]int)}
+	for i, evt := range as.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		state := evt.Review()
+		report.Events++
+		report.ByState[state]++
+		if !state.AtLeast(ReviewTranslated) {
+			report.Untranslated = append(report.Untranslated, i)
+		}
+		if !state.AtLeast(ReviewQCed) {
+			report.Unreviewed = append(report.Unreviewed, i)
+		}
+	}
+	if report.Events > 0 {
+		report.Done = float64(report.ByState[ReviewApproved]) / float64(report.Events)
+	}
+	return report
+}
@@ -0,0 +1,80 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReviewState(t *testing.T) {
+	evt := &Event{}
+	if evt.Review() != ReviewDraft {
+		t.Errorf("Expect draft by default, got: %s", evt.Review())
+	}
+	if err := evt.SetReview("done"); err == nil {
+		t.Errorf("Expect an error for an unknown state")
+	}
+	if err := evt.SetReview(ReviewTimed); err != nil || evt.Extra["review"] != "timed" {
+		t.Errorf("Expect the state in the extra data, got: %v, %v", err, evt.Extra)
+	}
+
+	cases := []struct {
+		state, than ReviewState
+		expect      bool
+	}{
+		{ReviewTimed, ReviewTranslated, true},
+		{ReviewTimed, ReviewTimed, true},
+		{ReviewTimed, ReviewQCed, false},
+		{ReviewApproved, ReviewDraft, true},
+		{"unknown", ReviewDraft, false},
+	}
+	for _, c := range cases {
+		if got := c.state.AtLeast(c.than); got != c.expect {
+			t.Errorf("Expect %s at least %s to be %v, got: %v", c.state, c.than, c.expect, got)
+		}
+	}
+}
+
+func TestReviewSelection(t *testing.T) {
+	sub := &Subtitle{Events: []*Event{
+		{Text: "zero"},
+		{Text: "one", Extra: map[string]string{"review": "translated"}},
+		{Text: "two", Extra: map[string]string{"review": "qced"}},
+		{Text: "three", Extra: map[string]string{"review": "approved"}},
+		{Text: "four", Comment: true},
+		{Text: "five", Extra: map[string]string{"review": "timed"}},
+	}}
+
+	if n := sub.Select().ByReview(ReviewTranslated, ReviewTimed).Len(); n != 2 {
+		t.Errorf("Expect 2 translated or timed events, got: %d", n)
+	}
+	if err := sub.Select().ReviewBefore(ReviewTimed).SetReview(ReviewTimed); err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	if sub.Events[0].Review() != ReviewTimed || sub.Events[2].Review() != ReviewQCed {
+		t.Errorf("Expect only the events before timed to be set, got: %s, %s", sub.Events[0].Review(), sub.Events[2].Review())
+	}
+	if err := sub.Select().SetReview("done"); err == nil {
+		t.Errorf("Expect an error for an unknown state")
+	}
+}
+
+func TestReviewReport(t *testing.T) {
+	sub := &Subtitle{Events: []*Event{
+		{Text: "zero"},
+		{Text: "one", Extra: map[string]string{"review": "translated"}},
+		nil,
+		{Text: "three", Extra: map[string]string{"review": "approved"}},
+		{Text: "four", Comment: true},
+		{Text: "five", Extra: map[string]string{"review": "qced"}},
+	}}
+	expect := ReviewReport{
+		Events:       4,
+		ByState:      map[ReviewState]int{ReviewDraft: 1, ReviewTranslated: 1, ReviewQCed: 1, ReviewApproved: 1},
+		Untranslated: []int{0},
+		Unreviewed:   []int{0, 1},
+		Done:         0.25,
+	}
+	if got := sub.ReviewReport(); !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expect %+v, got: %+v", expect, got)
+	}
+}
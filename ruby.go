@@ -0,0 +1,108 @@
+package ass
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Ruby is a piece of base text with its reading, e.g. furigana over kanji.
+// The reading is empty for the base text without ruby.
+type Ruby struct {
+	Base    string `json:"base"`
+	Reading string `json:"reading"`
+}
+
+// RubyMode is how a reading is laid out over its base
+type RubyMode int
+
+// The ruby modes
+const (
+	// RubyPerWord centers the whole reading over the whole base
+	RubyPerWord RubyMode = iota
+	// RubyPerChar splits the reading evenly over the characters of the base
+	RubyPerChar
+)
+
+// RubyOptions configures Subtitle.RubyEvents
+type RubyOptions struct {
+	Mode RubyMode
+	// Scale is the font size of the readings relative to the base, 0.5 by
+	// default
+	Scale float64
+	// Gap is the space between the base and the readings, in script pixels
+	Gap float64
+	// Measurer measures the base text, ApproxMeasurer if nil
+	Measurer TextMeasurer
+}
+
+// RubyEvents lays out a single line of text with ruby: it returns a copy of
+// evt with the bases as text, positioned where the renderer would place
+// it, followed by an event per reading, in a smaller font above its base.
+// The timing, style, position and leading tags come from evt.
+func (as *Subtitle) RubyEvents(evt *Event, rubies []Ruby, opts RubyOptions) ([]*Event, error) {
+	if opts.Scale <= 0 {
+		opts.Scale = 0.5
+	}
+	if opts.Measurer == nil {
+		opts.Measurer = ApproxMeasurer{}
+	}
+	var bases strings.Builder
+	for _, r := range rubies {
+		if strings.ContainsAny(r.Base, "{}") || strings.Contains(r.Base, `\N`) || strings.ContainsAny(r.Reading, "{}") {
+			return nil, fmt.Errorf("Invalid ruby: %s(%s)", r.Base, r.Reading)
+		}
+		bases.WriteString(r.Base)
+	}
+
+	var lead string
+	for _, p := range splitText(evt.Text) {
+		if !p.Override {
+			break
+		}
+		lead += "{" + p.Text + "}"
+	}
+	base := evt.clone()
+	base.Text = lead + bases.String()
+	align, _, _, _ := as.eventLayout(&base)
+	box, _ := as.textBox(&base, opts.Measurer)
+	x, y := anchorPoint(box, align)
+	base.Text = prependTags(removeTags(lead, isFitTag)+bases.String(),
+		fmt.Sprintf(`\an%d\pos(%s,%s)`, align, formatNumber(x), formatNumber(y)))
+	events := []*Event{&base}
+
+	style := as.styleByName(evt.Style)
+	size := float64(defaultFontSize)
+	if style != nil && style.FontSize > 0 {
+		size = float64(style.FontSize)
+	}
+	readingTags := func(cx float64, reading string) *Event {
+		e := evt.clone()
+		e.Text = fmt.Sprintf(`{\an2\pos(%s,%s)\fs%s}%s`, formatNumber(cx), formatNumber(box.Y-opts.Gap), formatNumber(size*opts.Scale), reading)
+		return &e
+	}
+	left := box.X
+	for _, r := range rubies {
+		w, _ := opts.Measurer.Measure(r.Base, style)
+		if r.Reading != "" {
+			n := utf8.RuneCountInString(r.Base)
+			if opts.Mode == RubyPerChar && n > 1 {
+				// the reading characters, shared out from the first base characters
+				reading := []rune(r.Reading)
+				charLeft := left
+				for i, c := range []rune(r.Base) {
+					from, to := i*len(reading)/n, (i+1)*len(reading)/n
+					cw, _ := opts.Measurer.Measure(string(c), style)
+					if to > from {
+						events = append(events, readingTags(charLeft+cw/2, string(reading[from:to])))
+					}
+					charLeft += cw
+				}
+			} else {
+				events = append(events, readingTags(left+w/2, r.Reading))
+			}
+		}
+		left += w
+	}
+	return events, nil
+}
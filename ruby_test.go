@@ -0,0 +1,60 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRubyEvents(t *testing.T) {
+	fixed := TextMeasurerFunc(func(text string, style *Style) (float64, float64) {
+		return float64(20 * len([]rune(text))), 20
+	})
+	sub := &Subtitle{PlayerWidth: 640, PlayerHeight: 480, Styles: []*Style{{Name: "Top", Alignment: 8, MarginV: 40}}}
+	rubies := []Ruby{{Base: "漢字", Reading: "かんじ"}, {Base: "を"}, {Base: "書", Reading: "か"}}
+	cases := []struct {
+		style  string
+		text   string
+		opts   RubyOptions
+		expect []string
+	}{
+		{"", `{\pos(100,100)\b1}x`, RubyOptions{Measurer: fixed, Gap: 2}, []string{
+			`{\an2\pos(100,100)\b1}漢字を書`,
+			`{\an2\pos(80,78)\fs10}かんじ`,
+			`{\an2\pos(130,78)\fs10}か`,
+		}},
+		{"", `{\an7\pos(0,0)}`, RubyOptions{Mode: RubyPerChar, Scale: 0.4, Measurer: fixed}, []string{
+			`{\an7\pos(0,0)}漢字を書`,
+			`{\an2\pos(10,0)\fs8}か`,
+			`{\an2\pos(30,0)\fs8}んじ`,
+			`{\an2\pos(70,0)\fs8}か`,
+		}},
+		{"", `x`, RubyOptions{Measurer: fixed}, []string{
+			`{\an2\pos(320,478)}漢字を書`,
+			`{\an2\pos(300,458)\fs10}かんじ`,
+			`{\an2\pos(350,458)\fs10}か`,
+		}},
+		// aligned and within the margins of its style
+		{"Top", `x`, RubyOptions{Measurer: fixed}, []string{
+			`{\an8\pos(320,40)}漢字を書`,
+			`{\an2\pos(300,40)\fs10}かんじ`,
+			`{\an2\pos(350,40)\fs10}か`,
+		}},
+	}
+	for _, c := range cases {
+		events, err := sub.RubyEvents(&Event{Start: "0:00:01.00", End: "0:00:02.00", Style: c.style, Text: c.text}, rubies, c.opts)
+		if err != nil {
+			t.Fatalf("Expect no error, got: %v", err)
+		}
+		var got []string
+		for _, e := range events {
+			got = append(got, e.Text)
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+	}
+
+	if _, err := sub.RubyEvents(&Event{}, []Ruby{{Base: "{x}"}}, RubyOptions{}); err == nil {
+		t.Errorf("Expect an error for a base with braces")
+	}
+}
@@ -0,0 +1,268 @@
+package ass
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Severity is how serious an issue is
+type Severity int
+
+// The severities
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// Issue is a problem reported by a validation rule
+type Issue struct {
+	Severity Severity `json:"severity"`
+	// Rule is a short name of the rule reporting the issue, if any
+	Rule string `json:"rule,omitempty"`
+	// Section, Index and Field locate the problem, as in ValidationError
+	Section string `json:"section,omitempty"`
+	Index   int    `json:"index"`
+	Field   string `json:"field,omitempty"`
+	// Line is the line of the parsed file, 0 if unknown
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+func (issue Issue) String() string {
+	return issue.Severity.String() + ": " + located(issue.Line, issue.Section, issue.Index, issue.Field, issue.Message)
+}
+
+// Rule checks a subtitle and reports its issues. A Validator passes a
+// shallow copy of the subtitle checked.
+type Rule func(*Subtitle) []Issue
+
+// Level is how strict the built-in checks of a Validator are
+type Level int
+
+// The validation levels
+const (
+	// Lenient only reports as errors the problems that break the file:
+	// nil items, invalid timestamps and fields with commas or line breaks.
+	// The other problems are warnings.
+	Lenient Level = iota
+	// Standard reports the same errors as Subtitle.Validate
+	Standard
+	// Strict adds errors for undefined and duplicated styles
+	Strict
+)
+
+// lenientErrors are the fields whose problems are still errors when lenient
+var lenientErrors = map[string]bool{"": true, "Start": true, "End": true, "Name": true, "Style": true, "Effect": true, "FontName": true}
+
+// Validator runs the built-in checks at a level plus custom rules. The
+// checks run in parallel, so the rules must be safe for concurrent use;
+// the issues are reported in the same order whatever the number of workers.
+type Validator struct {
+	Level Level
+	// Workers is the number of checks run at once, GOMAXPROCS if 0, 1 to
+	// run them in turn
+	Workers int
+	rules   []Rule
+}
+
+// NewValidator creates a validator with the built-in checks at level
+func NewValidator(level Level) *Validator {
+	return &Validator{Level: level}
+}
+
+// AddRule registers custom rules, run after the built-in checks
+func (v *Validator) AddRule(rules ...Rule) *Validator {
+	v.rules = append(v.rules, rules...)
+	return v
+}
+
+// Check returns all the issues of the subtitle
+func (v *Validator) Check(sub *Subtitle) []Issue {
+	workers := workerCount(v.Workers)
+	// the built-in checks run alongside the rules, the results are then
+	// gathered in order
+	var errs ValidationErrors
+	results := make([][]Issue, len(v.rules))
+	// the rules get a copy telling the number of workers
+	checked := *sub
+	checked.workers = v.Workers
+	parallel(len(v.rules)+1, workers, func(i int) {
+		if i == 0 {
+			errs, _ = sub.validateWith(workers).(ValidationErrors)
+		} else {
+			results[i-1] = v.rules[i-1](&checked)
+		}
+	})
+
+	var issues []Issue
+	for _, err := range errs {
+		severity := SeverityError
+		if v.Level == Lenient && !lenientErrors[err.Field] {
+			severity = SeverityWarning
+		}
+		issues = append(issues, Issue{
+			Severity: severity,
+			Section:  err.Section,
+			Index:    err.Index,
+			Field:    err.Field,
+			Line:     err.Line,
+			Message:  err.Err.Error(),
+		})
+	}
+	if v.Level >= Strict {
+		issues = append(issues, strictIssues(sub)...)
+	}
+	for _, result := range results {
+		issues = append(issues, result...)
+	}
+	for i := range issues {
+		if issues[i].Line == 0 {
+			issues[i].Line = sub.sourceLine(issues[i].Section, issues[i].Index, issues[i].Field)
+		}
+	}
+	return issues
+}
+
+// Validate returns ValidationErrors with the issues of error severity,
+// or nil if there is none
+func (v *Validator) Validate(sub *Subtitle) error {
+	var errs ValidationErrors
+	for _, issue := range v.Check(sub) {
+		if issue.Severity < SeverityError {
+			continue
+		}
+		errs = append(errs, &ValidationError{
+			Section: issue.Section,
+			Index:   issue.Index,
+			Field:   issue.Field,
+			Line:    issue.Line,
+			Err:     errors.New(issue.Message),
+		})
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func strictIssues(sub *Subtitle) []Issue {
+	var issues []Issue
+	names := make(map[string]bool)
+	for i, style := range sub.Styles {
+		if style == nil {
+			continue
+		}
+		if names[style.Name] {
+			issues = append(issues, Issue{Severity: SeverityError, Section: "Styles", Index: i, Field: "Name",
+				Message: fmt.Sprintf("Duplicated style: %s", style.Name)})
+		}
+		names[style.Name] = true
+	}
+	for i, evt := range sub.Events {
+		if evt == nil || names[evt.Style] {
+			continue
+		}
+		issues = append(issues, Issue{Severity: SeverityError, Section: "Events", Index: i, Field: "Style",
+			Message: fmt.Sprintf("Undefined style: %s", evt.Style)})
+	}
+	return issues
+}
+
+// eventRule makes a rule checking the events one by one, comments aside,
+// in parallel for large subtitles with the workers of the Validator
+func eventRule(check func(evt *Event) *Issue) Rule {
+	return func(sub *Subtitle) []Issue {
+		workers := workerCount(sub.workers)
+		ranges := eventRanges(len(sub.Events), workers)
+		chunks := make([][]Issue, len(ranges))
+		parallel(len(ranges), workers, func(c int) {
+			for i := ranges[c].lo; i < ranges[c].hi; i++ {
+				evt := sub.Events[i]
+				if evt == nil || evt.Comment {
+					continue
+				}
+				if issue := check(evt); issue != nil {
+					issue.Section, issue.Index = "Events", i
+					chunks[c] = append(chunks[c], *issue)
+				}
+			}
+		})
+		var issues []Issue
+		for _, chunk := range chunks {
+			issues = append(issues, chunk...)
+		}
+		return issues
+	}
+}
+
+// MaxLinesRule reports events with more than n lines, counting \N breaks
+func MaxLinesRule(n int, severity Severity) Rule {
+	return eventRule(func(evt *Event) *Issue {
+		lines := strings.Count(StripTags(evt.Text), `\N`) + 1
+		if lines <= n {
+			return nil
+		}
+		return &Issue{Severity: severity, Rule: "max-lines", Field: "Text", Message: fmt.Sprintf("Too many lines: %d > %d", lines, n)}
+	})
+}
+
+// ForbiddenTextRule reports events whose text, without tags, matches pattern,
+// e.g. TODO markers
+func ForbiddenTextRule(pattern *regexp.Regexp, severity Severity) Rule {
+	return eventRule(func(evt *Event) *Issue {
+		match := pattern.FindString(StripTags(evt.Text))
+		if match == "" {
+			return nil
+		}
+		return &Issue{Severity: severity, Rule: "forbidden-text", Field: "Text", Message: fmt.Sprintf("Forbidden text: %s", match)}
+	})
+}
+
+// MediaDurationRule reports events ending after the end of the media
+func MediaDurationRule(media time.Duration, severity Severity) Rule {
+	return eventRule(func(evt *Event) *Issue {
+		end, err := evt.EndTime()
+		if err != nil || end.Duration() <= media {
+			return nil
+		}
+		return &Issue{Severity: severity, Rule: "media-duration", Field: "End",
+			Message: fmt.Sprintf("Event ends after the media: %s > %s", end, Timestamp(media))}
+	})
+}
+
+// InvalidTagRule reports events with an unclosed override block, an unknown
+// override tag or a tag with an invalid argument
+func InvalidTagRule(severity Severity) Rule {
+	return eventRule(func(evt *Event) *Issue {
+		for _, p := range splitText(evt.Text) {
+			if !p.Override {
+				if strings.Contains(p.Text, "{") {
+					return &Issue{Severity: severity, Rule: "invalid-tag", Field: "Text", Message: "Unclosed override block"}
+				}
+				continue
+			}
+			for _, tag := range splitTags(p.Text) {
+				if err := checkTag(tag); err != nil {
+					return &Issue{Severity: severity, Rule: "invalid-tag", Field: "Text", Message: err.Error()}
+				}
+			}
+		}
+		return nil
+	})
+}
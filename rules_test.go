@@ -0,0 +1,97 @@
+package ass
+
+import (
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestValidator(t *testing.T) {
+	sub := &Subtitle{
+		Styles: []*Style{
+			{Name: "Default", PrimaryColor: "red"},
+			{Name: "Default"},
+		},
+		Events: []*Event{
+			{Start: "0:00:00.00", End: "0:00:01.00", Style: "Default", Text: `one\Ntwo\N{\i1}three`},
+			{Start: "0:00:00.00", End: "0:00:01.00", Style: "Missing", Text: "TODO fix"},
+		},
+	}
+
+	cases := []struct {
+		validator *Validator
+		issues    []string
+		errors    int
+	}{
+		{NewValidator(Lenient), []string{"warning: Styles[0].PrimaryColor: Invalid primary color: red"}, 0},
+		{NewValidator(Standard), []string{"error: Styles[0].PrimaryColor: Invalid primary color: red"}, 1},
+		{NewValidator(Strict), []string{
+			"error: Styles[0].PrimaryColor: Invalid primary color: red",
+			"error: Styles[1].Name: Duplicated style: Default",
+			"error: Events[1].Style: Undefined style: Missing",
+		}, 3},
+		{NewValidator(Lenient).AddRule(MaxLinesRule(2, SeverityError), ForbiddenTextRule(regexp.MustCompile(`TODO`), SeverityWarning)), []string{
+			"warning: Styles[0].PrimaryColor: Invalid primary color: red",
+			"error: Events[0].Text: Too many lines: 3 > 2",
+			"warning: Events[1].Text: Forbidden text: TODO",
+		}, 1},
+	}
+
+	for i, c := range cases {
+		issues := c.validator.Check(sub)
+		if len(issues) != len(c.issues) {
+			t.Errorf("Case %d: expect %d issues, got: %v", i, len(c.issues), issues)
+			continue
+		}
+		for j, issue := range issues {
+			if issue.String() != c.issues[j] {
+				t.Errorf("Case %d: expect %q, got: %q", i, c.issues[j], issue.String())
+			}
+		}
+		err := c.validator.Validate(sub)
+		if errs, _ := err.(ValidationErrors); len(errs) != c.errors {
+			t.Errorf("Case %d: expect %d errors, got: %v", i, c.errors, err)
+		}
+	}
+}
+
+func TestMediaDurationRule(t *testing.T) {
+	sub := &Subtitle{
+		Events: []*Event{
+			{Start: "0:00:00.00", End: "0:00:10.00"},
+			{Start: "0:00:10.00", End: "0:00:10.01"},
+		},
+	}
+	issues := NewValidator(Standard).AddRule(MediaDurationRule(10*time.Second, SeverityError)).Check(sub)
+	if len(issues) != 1 || issues[0].Index != 1 || issues[0].Rule != "media-duration" {
+		t.Errorf("Expect the last event reported, got: %v", issues)
+	}
+}
+
+func TestValidatorWorkers(t *testing.T) {
+	sub := &Subtitle{}
+	for i := 0; i < 2*parallelEvents; i++ {
+		text := "fast"
+		if i%256 == 0 {
+			text = "slow"
+		}
+		sub.Events = append(sub.Events, &Event{Text: text})
+	}
+	var running, most int32
+	rule := eventRule(func(evt *Event) *Issue {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for m := atomic.LoadInt32(&most); n > m && !atomic.CompareAndSwapInt32(&most, m, n); m = atomic.LoadInt32(&most) {
+		}
+		if evt.Text == "slow" {
+			time.Sleep(5 * time.Millisecond)
+		}
+		return nil
+	})
+	v := &Validator{Workers: 1}
+	v.AddRule(rule).Check(sub)
+	if most != 1 {
+		t.Errorf("Expect the events checked in turn, got: %d at once", most)
+	}
+}
@@ -0,0 +1,87 @@
+package ass
+
+import (
+	"io"
+	"sync"
+)
+
+// SafeSubtitle guards a subtitle shared between goroutines, e.g. by the
+// handlers of a web service: any number of readers at once, writers one at
+// a time. The subtitle must only be accessed through it.
+type SafeSubtitle struct {
+	mu  sync.RWMutex
+	sub *Subtitle
+}
+
+// NewSafeSubtitle guards sub, which must not be used directly anymore
+func NewSafeSubtitle(sub *Subtitle) *SafeSubtitle {
+	return &SafeSubtitle{sub: sub}
+}
+
+// View runs fn holding a read lock, along with other readers. fn must not
+// change the subtitle nor keep it, nor call Reindex. SafeSubtitle.ActiveAt
+// keeps the index up to date.
+func (s *SafeSubtitle) View(fn func(sub *Subtitle) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(s.sub)
+}
+
+// Update runs fn holding the write lock, fn may change the subtitle in
+// place but must not keep it
+func (s *SafeSubtitle) Update(fn func(sub *Subtitle) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// events may be retimed in place, which the index cannot tell
+	defer func() { s.sub.index = nil }()
+	return fn(s.sub)
+}
+
+// Apply runs a transformer as an Update
+func (s *SafeSubtitle) Apply(t Transformer) error {
+	return s.Update(t.Transform)
+}
+
+// Snapshot returns a copy of the subtitle, which the caller owns
+func (s *SafeSubtitle) Snapshot() *Subtitle {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sub.Clone()
+}
+
+// Replace swaps the guarded subtitle for sub, e.g. after a reload
+func (s *SafeSubtitle) Replace(sub *Subtitle) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sub = sub
+}
+
+// ActiveAt returns the events on screen at t, see Subtitle.ActiveAt. The
+// events returned must not be changed.
+func (s *SafeSubtitle) ActiveAt(t Timestamp) []*Event {
+	s.mu.RLock()
+	if s.sub.index.covers(s.sub.Events) {
+		defer s.mu.RUnlock()
+		return s.sub.index.ActiveAt(t)
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.sub.index.covers(s.sub.Events) {
+		s.sub.Reindex()
+	}
+	return s.sub.index.ActiveAt(t)
+}
+
+// WriteTo writes a snapshot of the subtitle, so readers and writers are
+// not held while writing
+func (s *SafeSubtitle) WriteTo(w io.Writer) (int64, error) {
+	return s.Snapshot().WriteTo(w)
+}
+
+// WriteToWithOptions writes a snapshot of the subtitle, see
+// Subtitle.WriteToWithOptions
+func (s *SafeSubtitle) WriteToWithOptions(w io.Writer, opts ...WriteOption) (int64, error) {
+	return s.Snapshot().WriteToWithOptions(w, opts...)
+}
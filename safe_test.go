@@ -0,0 +1,76 @@
+package ass
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSafeSubtitle(t *testing.T) {
+	s := NewSafeSubtitle(&Subtitle{
+		Styles: []*Style{{Name: "Default"}},
+		Events: []*Event{{Start: "0:00:00.00", End: "0:00:01.00", Style: "Default", Text: "zero"}},
+	})
+
+	// run with -race to check the locking
+	var wg sync.WaitGroup
+	for i := 1; i <= 20; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			s.Update(func(sub *Subtitle) error {
+				ts := Timestamp(time.Duration(i) * time.Second)
+				sub.Events = append(sub.Events, &Event{Start: ts.String(), End: (ts + Timestamp(time.Second)).String(),
+					Style: "Default", Text: fmt.Sprint(i)})
+				return nil
+			})
+		}(i)
+		go func() {
+			defer wg.Done()
+			if len(s.ActiveAt(Timestamp(500*time.Millisecond))) != 1 {
+				t.Errorf("Expect the first event active")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			var buf bytes.Buffer
+			if _, err := s.WriteTo(&buf); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var n int
+	s.View(func(sub *Subtitle) error {
+		n = len(sub.Events)
+		return nil
+	})
+	if n != 21 {
+		t.Errorf("Expect 21 events, got: %d", n)
+	}
+
+	if err := s.Apply(Shift(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	active := s.ActiveAt(Timestamp(500 * time.Millisecond))
+	if len(active) != 0 {
+		t.Errorf("Expect the index rebuilt after an update, got: %v", active)
+	}
+
+	snapshot := s.Snapshot()
+	snapshot.Events[0].Text = "changed"
+	s.View(func(sub *Subtitle) error {
+		if sub.Events[0].Text != "zero" {
+			t.Errorf("Expect the snapshot to be a copy")
+		}
+		return nil
+	})
+
+	s.Replace(&Subtitle{})
+	if s.Snapshot().Events != nil {
+		t.Errorf("Expect the subtitle replaced")
+	}
+}
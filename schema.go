@@ -0,0 +1,34 @@
+package ass
+
+import (
+	"bytes"
+	_ "embed" // the JSON schema
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONSchema is the JSON Schema of the JSON form of Subtitle, the content of
+// ass.schema.json
+//
+//go:embed ass.schema.json
+var JSONSchema []byte
+
+// UnmarshalStrict decodes the JSON form of a subtitle, such as the body of
+// an API request. Unlike json.Unmarshal it rejects unknown fields and
+// trailing data, and validates the result, timestamps and colors included.
+func UnmarshalStrict(data []byte) (*Subtitle, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	sub := &Subtitle{}
+	if err := dec.Decode(sub); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("Invalid data after the subtitle")
+	}
+	if err := sub.Validate(); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
@@ -0,0 +1,73 @@
+package ass
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	var schema struct {
+		Properties map[string]interface{} `json:"properties"`
+		Defs       map[string]struct {
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(JSONSchema, &schema); err != nil {
+		t.Fatalf("Expect a valid JSON schema, got: %v", err)
+	}
+
+	// the schema must describe every field of the JSON form
+	cases := []struct {
+		typ        reflect.Type
+		properties map[string]interface{}
+	}{
+		{reflect.TypeOf(Subtitle{}), schema.Properties},
+		{reflect.TypeOf(Style{}), schema.Defs["style"].Properties},
+		{reflect.TypeOf(Event{}), schema.Defs["event"].Properties},
+		{reflect.TypeOf(RawSection{}), schema.Defs["rawSection"].Properties},
+	}
+	for _, c := range cases {
+		fields := 0
+		for i := 0; i < c.typ.NumField(); i++ {
+			tag := c.typ.Field(i).Tag.Get("json")
+			if tag == "" || tag == "-" {
+				continue
+			}
+			fields++
+			name := strings.Split(tag, ",")[0]
+			if _, ok := c.properties[name]; !ok {
+				t.Errorf("Expect %s.%s in the schema", c.typ.Name(), name)
+			}
+		}
+		if len(c.properties) != fields {
+			t.Errorf("Expect %d properties for %s, got: %d", fields, c.typ.Name(), len(c.properties))
+		}
+	}
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	sub, err := UnmarshalStrict([]byte(`{"title": "strict", "styles": [{"name": "Default", "primaryColor": "00FFFFFF"}],
+		"events": [{"start": "0:00:01.00", "end": "0:00:02.00", "style": "Default", "text": "Hello"}]}`))
+	if err != nil {
+		t.Fatalf("Expect unmarshal success, got: %v", err)
+	}
+	if sub.Title != "strict" || len(sub.Styles) != 1 || len(sub.Events) != 1 || sub.Events[0].Text != "Hello" {
+		t.Errorf("Unexpected subtitle: %+v", sub)
+	}
+
+	cases := []string{
+		`{"title": "strict", "author": "unknown field"}`,
+		`{"events": [{"start": "0:00:01.00", "end": "0:00:02.00", "txt": "typo"}]}`,
+		`{"events": [{"start": "1s", "end": "0:00:02.00"}]}`,
+		`{"styles": [{"name": "Default", "primaryColor": "white"}]}`,
+		`{"title": "strict"} {"title": "again"}`,
+		`{"title": 1}`,
+	}
+	for _, c := range cases {
+		if _, err := UnmarshalStrict([]byte(c)); err == nil {
+			t.Errorf("Expect error for %s, but passed", c)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package ass
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	sdhAnnotationReg = regexp.MustCompile(`\[[^\[\]]*\]|\([^()]*\)`)
+	sdhSpeakerReg    = regexp.MustCompile(`^(\s*[-–]\s*)?[A-Z][A-Z0-9 .'&-]*:\s*`)
+	sdhSpacesReg     = regexp.MustCompile(`\s{2,}`)
+)
+
+// musicNote marks the music and lyrics in SDH subtitles
+const musicNote = "♪"
+
+// SoundCaption returns the caption of a sound effect: [door slams]
+func SoundCaption(description string) string {
+	return "[" + strings.TrimSpace(description) + "]"
+}
+
+// AddSoundCaption adds an event captioning a sound effect, see SoundCaption,
+// and returns it
+func (as *Subtitle) AddSoundCaption(start Timestamp, dur time.Duration, style, description string) *Event {
+	evt := NewEvent(start, dur, style, SoundCaption(description))
+	as.Events = append(as.Events, evt)
+	return evt
+}
+
+// LabelSpeakers prefixes the text of the events with the name of their
+// speaker, NAME: Text, when it differs from the speaker of the previous
+// event, and returns the number of labeled events. Comments are skipped.
+func (as *Subtitle) LabelSpeakers() int {
+	n, prev := 0, ""
+	for _, evt := range as.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		if evt.Name != "" && evt.Name != prev {
+			evt.Text = insertAfterTags(evt.Text, strings.ToUpper(evt.Name)+": ")
+			n++
+		}
+		prev = evt.Name
+	}
+	return n
+}
+
+// WrapMusic encloses each line of the text in music notes: ♪ lyrics ♪
+func WrapMusic(text string) string {
+	lines := strings.Split(text, `\N`)
+	for i, line := range lines {
+		if strings.TrimSpace(StripTags(line)) == "" {
+			continue
+		}
+		line = insertAfterTags(line, musicNote+" ")
+		if strings.HasSuffix(line, "}") {
+			lines[i] = line[:strings.LastIndexByte(line, '{')] + " " + musicNote + line[strings.LastIndexByte(line, '{'):]
+			continue
+		}
+		lines[i] = line + " " + musicNote
+	}
+	return strings.Join(lines, `\N`)
+}
+
+// StripSDH removes the SDH annotations from dialogue text: sound captions
+// in brackets or parentheses, upper case speaker labels and music notes.
+// Lyrics are kept. The lines left empty are removed, keeping their tags.
+func StripSDH(text string) string {
+	var (
+		kept []string
+		tags string // of the removed lines
+	)
+	for _, line := range strings.Split(text, `\N`) {
+		parts := splitText(line)
+		labeled := false
+		for i := range parts {
+			if parts[i].Override {
+				continue
+			}
+			t := sdhAnnotationReg.ReplaceAllString(parts[i].Text, "")
+			t = strings.Replace(strings.Replace(t, musicNote, "", -1), "♫", "", -1)
+			if !labeled && strings.TrimSpace(t) != "" {
+				t = sdhSpeakerReg.ReplaceAllString(t, "${1}")
+				labeled = true
+			}
+			parts[i].Text = sdhSpacesReg.ReplaceAllString(t, " ")
+		}
+		line = joinText(parts)
+		if strings.TrimSpace(StripTags(line)) == "" {
+			for _, p := range parts {
+				if p.Override {
+					tags += "{" + p.Text + "}"
+				}
+			}
+			continue
+		}
+		kept = append(kept, tags+trimPlain(line))
+		tags = ""
+	}
+	if len(kept) == 0 {
+		return tags
+	}
+	kept[len(kept)-1] += tags
+	return strings.Join(kept, `\N`)
+}
+
+// StripSDH removes the SDH annotations of the events, see StripSDH, to make
+// a non-SDH track from an SDH master. The events left without text are
+// removed, their number is returned.
+func (as *Subtitle) StripSDH() int {
+	events := as.Events[:0]
+	removed := 0
+	for _, evt := range as.Events {
+		if evt != nil && !evt.Comment {
+			evt.Text = StripSDH(evt.Text)
+			if strings.TrimSpace(StripTags(evt.Text)) == "" {
+				removed++
+				continue
+			}
+		}
+		events = append(events, evt)
+	}
+	for i := len(events); i < len(as.Events); i++ {
+		as.Events[i] = nil
+	}
+	as.Events = events
+	return removed
+}
+
+// insertAfterTags inserts s after the leading override blocks of the text
+func insertAfterTags(text, s string) string {
+	i := 0
+	for strings.HasPrefix(text[i:], "{") {
+		end := strings.IndexByte(text[i:], '}')
+		if end < 0 {
+			break
+		}
+		i += end + 1
+	}
+	return text[:i] + s + text[i:]
+}
+
+// trimPlain trims the spaces at the start and the end of the plain text
+func trimPlain(text string) string {
+	parts := splitText(text)
+	for i := 0; i < len(parts); i++ {
+		if !parts[i].Override {
+			if parts[i].Text = strings.TrimLeft(parts[i].Text, " "); parts[i].Text != "" {
+				break
+			}
+		}
+	}
+	for i := len(parts) - 1; i >= 0; i-- {
+		if !parts[i].Override {
+			if parts[i].Text = strings.TrimRight(parts[i].Text, " "); parts[i].Text != "" {
+				break
+			}
+		}
+	}
+	return joinText(parts)
+}
@@ -0,0 +1,73 @@
+package ass
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStripSDH(t *testing.T) {
+	cases := []struct {
+		text, expect string
+	}{
+		{"Hello", "Hello"},
+		{"[door slams]", ""},
+		{`JOHN: Hello (sighs) there`, "Hello there"},
+		{`- JOHN: Hi\N- MARY: Bye`, `- Hi\N- Bye`},
+		{`{\an8}[thunder]\NWhat was that?`, `{\an8}What was that?`},
+		{`{\pos(1,2)}♪ La la la ♪`, `{\pos(1,2)}La la la`},
+		{`♪ [upbeat music] ♪`, ""},
+		{`At 12:30, OK?`, `At 12:30, OK?`},
+		{`Note: (laughs)`, `Note:`},
+	}
+	for _, c := range cases {
+		if text := StripSDH(c.text); text != c.expect {
+			t.Errorf("Expect %q for %q, got: %q", c.expect, c.text, text)
+		}
+	}
+}
+
+func TestSubtitleSDH(t *testing.T) {
+	sub := &Subtitle{Events: []*Event{
+		{Name: "John", Text: "Hi"},
+		{Name: "John", Text: "How are you?"},
+		{Name: "Mary", Text: `{\i1}Fine`},
+		{Comment: true, Name: "Note", Text: "[check]"},
+	}}
+	if n := sub.LabelSpeakers(); n != 2 {
+		t.Errorf("Expect 2 labeled events, got: %d", n)
+	}
+	sub.AddSoundCaption(Timestamp(time.Second), time.Second, "Default", " door slams ")
+	expects := []string{"JOHN: Hi", "How are you?", `{\i1}MARY: Fine`, "[check]", "[door slams]"}
+	for i, expect := range expects {
+		if sub.Events[i].Text != expect {
+			t.Errorf("Expect %q, got: %q", expect, sub.Events[i].Text)
+		}
+	}
+	if sub.Events[4].Start != "0:00:01.00" || sub.Events[4].End != "0:00:02.00" {
+		t.Errorf("Expect the caption from 1s to 2s, got: %s - %s", sub.Events[4].Start, sub.Events[4].End)
+	}
+
+	if n := sub.StripSDH(); n != 1 || len(sub.Events) != 4 {
+		t.Fatalf("Expect the caption to be removed, got: %d, %d events", n, len(sub.Events))
+	}
+	expects = []string{"Hi", "How are you?", `{\i1}Fine`, "[check]"}
+	for i, expect := range expects {
+		if sub.Events[i].Text != expect {
+			t.Errorf("Expect %q, got: %q", expect, sub.Events[i].Text)
+		}
+	}
+}
+
+func TestWrapMusic(t *testing.T) {
+	cases := []struct {
+		text, expect string
+	}{
+		{"La la", "♪ La la ♪"},
+		{`{\i1}La\NLa{\i0}`, `{\i1}♪ La ♪\N♪ La ♪{\i0}`},
+	}
+	for _, c := range cases {
+		if text := WrapMusic(c.text); text != c.expect {
+			t.Errorf("Expect %q, got: %q", c.expect, text)
+		}
+	}
+}
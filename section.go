@@ -0,0 +1,94 @@
+package ass
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Section is a section of a script, from its [Name] header to the next one
+type Section interface {
+	// SectionName is the name of the section, between the brackets
+	SectionName() string
+	// WriteTo writes the section, header included
+	io.WriterTo
+}
+
+// RawSection is a section the library doesn't understand, such as [Fonts],
+// [Graphics] or the sections of editors, kept as read and written back
+// verbatim after the other sections
+type RawSection struct {
+	Name string `json:"name"`
+	// Lines are the lines of the section, without line endings, the blank
+	// lines at its end left out
+	Lines []string `json:"lines"`
+}
+
+// SectionName returns the name of the section
+func (s *RawSection) SectionName() string {
+	return s.Name
+}
+
+// WriteTo writes the header of the section and its lines
+func (s *RawSection) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	b.WriteString("\n[")
+	b.WriteString(s.Name)
+	b.WriteString("]\n")
+	for _, line := range s.Lines {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (s *RawSection) validate() error {
+	if s.Name == "" || strings.ContainsAny(s.Name, "[]\r\n") {
+		return fmt.Errorf("Invalid section name: %q", s.Name)
+	}
+	switch strings.ToLower(s.Name) {
+	case "script info", "v4+ styles", "v4 styles", "events":
+		return fmt.Errorf("Section %s is not raw", s.Name)
+	}
+	for _, line := range s.Lines {
+		if strings.ContainsAny(line, "\r\n") {
+			return fmt.Errorf("Invalid line of section %s, line break is not allowed: %q", s.Name, line)
+		}
+	}
+	return nil
+}
+
+// knownSection is one of the sections of the subtitle model
+type knownSection struct {
+	name string
+	sub  *Subtitle
+}
+
+func (s knownSection) SectionName() string {
+	return s.name
+}
+
+func (s knownSection) WriteTo(w io.Writer) (int64, error) {
+	sub := *s.sub
+	sub.fulfill()
+	counter := &countingWriter{w: w}
+	err := writeSection(counter, &sub, s.name, true)
+	return counter.n, err
+}
+
+// Sections returns the sections of the subtitle in their output order:
+// Script Info, V4+ Styles, Events, then the raw sections
+func (as *Subtitle) Sections() []Section {
+	sections := []Section{
+		knownSection{SectionScriptInfo, as},
+		knownSection{SectionStyles, as},
+		knownSection{SectionEvents, as},
+	}
+	for _, raw := range as.RawSections {
+		if raw != nil {
+			sections = append(sections, raw)
+		}
+	}
+	return sections
+}
@@ -0,0 +1,77 @@
+package ass
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRawSections(t *testing.T) {
+	script := `[Script Info]
+Title: raw
+
+[Aegisub Project Garbage]
+Active Line: 2
+; kept as is
+
+[V4+ Styles]
+Style: Default,Arial,20,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,2,2,10,10,10,1
+
+[Fonts]
+fontname: font.ttf
+M1!7<<4>
+
+[Events]
+Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,Hello
+`
+	sub, err := Parse(strings.NewReader(script))
+	if err != nil {
+		t.Fatalf("Expect parse success, got: %v", err)
+	}
+	expect := []*RawSection{
+		{Name: "Aegisub Project Garbage", Lines: []string{"Active Line: 2", "; kept as is"}},
+		{Name: "Fonts", Lines: []string{"fontname: font.ttf", "M1!7<<4>"}},
+	}
+	if !reflect.DeepEqual(sub.RawSections, expect) {
+		t.Errorf("Expect %+v, got: %+v", expect, sub.RawSections)
+	}
+	if len(sub.Styles) != 1 || len(sub.Events) != 1 {
+		t.Errorf("Expect 1 style and 1 event, got: %d and %d", len(sub.Styles), len(sub.Events))
+	}
+
+	var names []string
+	for _, section := range sub.Sections() {
+		names = append(names, section.SectionName())
+	}
+	if expect := []string{"Script Info", "V4+ Styles", "Events", "Aegisub Project Garbage", "Fonts"}; !reflect.DeepEqual(names, expect) {
+		t.Errorf("Expect sections %v, got: %v", expect, names)
+	}
+
+	var buf bytes.Buffer
+	if _, err := sub.WriteTo(&buf); err != nil {
+		t.Fatalf("Expect write success, got: %v", err)
+	}
+	out := buf.String()
+	for _, s := range []string{"\n[Aegisub Project Garbage]\nActive Line: 2\n; kept as is\n", "\n[Fonts]\nfontname: font.ttf\nM1!7<<4>\n"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("Expect %q written, got: %s", s, out)
+		}
+	}
+	again, err := Parse(&buf)
+	if err != nil || !reflect.DeepEqual(again.RawSections, expect) {
+		t.Errorf("Expect raw sections kept after a round trip, got: %+v, %v", again.RawSections, err)
+	}
+
+	cloned := sub.Clone()
+	cloned.RawSections[0].Lines[0] = "changed"
+	if sub.RawSections[0].Lines[0] != "Active Line: 2" {
+		t.Errorf("Expect the clone not to share raw sections")
+	}
+
+	for _, raw := range []*RawSection{{Name: ""}, {Name: "Events"}, {Name: "Fonts", Lines: []string{"a\nb"}}} {
+		if err := (&Subtitle{RawSections: []*RawSection{raw}}).Validate(); err == nil {
+			t.Errorf("Expect validation error for %+v, but passed", raw)
+		}
+	}
+}
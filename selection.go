@@ -0,0 +1,130 @@
+package ass
+
+import (
+	"regexp"
+	"time"
+)
+
+// Selection is a set of events of a subtitle, built by chaining filters,
+// which can be retimed, restyled or deleted in bulk
+type Selection struct {
+	sub    *Subtitle
+	events []*Event
+}
+
+// Select starts a selection with all the events of the subtitle
+func (as *Subtitle) Select() *Selection {
+	events := make([]*Event, 0, len(as.Events))
+	for _, evt := range as.Events {
+		if evt != nil {
+			events = append(events, evt)
+		}
+	}
+	return &Selection{sub: as, events: events}
+}
+
+// Filter keeps the events for which fn returns true
+func (sel *Selection) Filter(fn func(*Event) bool) *Selection {
+	events := make([]*Event, 0, len(sel.events))
+	for _, evt := range sel.events {
+		if fn(evt) {
+			events = append(events, evt)
+		}
+	}
+	return &Selection{sub: sel.sub, events: events}
+}
+
+// ByStyle keeps the events using one of the styles
+func (sel *Selection) ByStyle(styles ...string) *Selection {
+	return sel.Filter(func(evt *Event) bool {
+		return contains(styles, evt.Style)
+	})
+}
+
+// ByActor keeps the events spoken by one of the actors
+func (sel *Selection) ByActor(names ...string) *Selection {
+	return sel.Filter(func(evt *Event) bool {
+		return contains(names, evt.Name)
+	})
+}
+
+// Between keeps the events visible at some time in [from, to).
+// Events with invalid timestamps are dropped.
+func (sel *Selection) Between(from, to Timestamp) *Selection {
+	return sel.Filter(func(evt *Event) bool {
+		start, end, err := evt.times()
+		return err == nil && start < to && end > from
+	})
+}
+
+// WithTextMatching keeps the events whose text matches re
+func (sel *Selection) WithTextMatching(re *regexp.Regexp) *Selection {
+	return sel.Filter(func(evt *Event) bool {
+		return re.MatchString(evt.Text)
+	})
+}
+
+// Events returns the selected events
+func (sel *Selection) Events() []*Event {
+	return sel.events
+}
+
+// Len returns the number of selected events
+func (sel *Selection) Len() int {
+	return len(sel.events)
+}
+
+// Shift moves the selected events by d, times before 0 are clamped to 0.
+// Nothing is changed if any of the events has an invalid timestamp.
+func (sel *Selection) Shift(d time.Duration) error {
+	type span struct{ start, end Timestamp }
+	spans := make([]span, len(sel.events))
+	for i, evt := range sel.events {
+		start, end, err := evt.times()
+		if err != nil {
+			return err
+		}
+		spans[i] = span{start, end}
+	}
+	for i, evt := range sel.events {
+		evt.Start = (spans[i].start + Timestamp(d)).String()
+		evt.End = (spans[i].end + Timestamp(d)).String()
+	}
+	return nil
+}
+
+// SetStyle changes the style of the selected events
+func (sel *Selection) SetStyle(style string) {
+	for _, evt := range sel.events {
+		evt.Style = style
+	}
+}
+
+// Delete removes the selected events from the subtitle
+func (sel *Selection) Delete() {
+	selected := make(map[*Event]bool, len(sel.events))
+	for _, evt := range sel.events {
+		selected[evt] = true
+	}
+	events := sel.sub.Events[:0]
+	for _, evt := range sel.sub.Events {
+		if !selected[evt] {
+			events = append(events, evt)
+		}
+	}
+	for i := len(events); i < len(sel.sub.Events); i++ {
+		sel.sub.Events[i] = nil
+	}
+	sel.sub.Events = events
+	sel.sub.index = nil
+	sel.events = nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package ass
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestSelection(t *testing.T) {
+	sub := &Subtitle{
+		Events: []*Event{
+			{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Name: "Naru", Text: "Hello"},
+			{Start: "0:00:03.00", End: "0:00:04.00", Style: "Signs", Text: "EXIT"},
+			{Start: "0:00:05.00", End: "0:00:06.00", Style: "Default", Name: "Naru", Text: "Bye"},
+			{Start: "0:00:07.00", End: "0:00:08.00", Style: "Default", Name: "Kei", Text: "Bye"},
+		},
+	}
+
+	cases := []struct {
+		sel    *Selection
+		expect int
+	}{
+		{sub.Select(), 4},
+		{sub.Select().ByStyle("Signs"), 1},
+		{sub.Select().ByActor("Naru"), 2},
+		{sub.Select().Between(Timestamp(1500*time.Millisecond), Timestamp(5*time.Second)), 2},
+		{sub.Select().ByStyle("Default").WithTextMatching(regexp.MustCompile(`^B`)), 2},
+		{sub.Select().ByActor("Naru").WithTextMatching(regexp.MustCompile(`^B`)), 1},
+	}
+
+	for i, c := range cases {
+		if c.sel.Len() != c.expect {
+			t.Errorf("Case %d: expect %d events, got: %d", i, c.expect, c.sel.Len())
+		}
+	}
+
+	if err := sub.Select().ByActor("Naru").Shift(-1500 * time.Millisecond); err != nil {
+		t.Errorf("Expect shift success, got: %v", err)
+	}
+	if sub.Events[0].Start != "0:00:00.00" || sub.Events[0].End != "0:00:00.50" || sub.Events[2].Start != "0:00:03.50" {
+		t.Errorf("Unexpected shifted times: %+v %+v", sub.Events[0], sub.Events[2])
+	}
+
+	sub.Select().ByStyle("Signs").SetStyle("Default")
+	if sub.Events[1].Style != "Default" {
+		t.Errorf("Expect restyled event, got: %s", sub.Events[1].Style)
+	}
+
+	sub.Select().ByActor("Naru").Delete()
+	if len(sub.Events) != 2 || sub.Events[0].Text != "EXIT" || sub.Events[1].Name != "Kei" {
+		t.Errorf("Unexpected events after delete: %+v", sub.Events)
+	}
+}
@@ -0,0 +1,218 @@
+package ass
+
+import (
+	"io"
+	"strconv"
+	"sync"
+)
+
+// The direct serializer produces the same output as assV4Tpl without
+// text/template, it is used unless the template is customized.
+
+const (
+	stylesFormat = "Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\n"
+	eventsHeader = "\n[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n"
+
+	// serializeChunk is the size written to the destination at once
+	serializeChunk = 32 * 1024
+)
+
+var serializeBufPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, serializeChunk+1024)
+		return &b
+	},
+}
+
+// writeSection writes a section of the subtitle, or the header of it
+func writeSection(w io.Writer, as *Subtitle, section string, padding bool) (err error) {
+	bp := serializeBufPool.Get().(*[]byte)
+	b := (*bp)[:0]
+	defer func() {
+		if cap(b) <= 4*serializeChunk {
+			*bp = b[:0]
+			serializeBufPool.Put(bp)
+		}
+	}()
+
+	switch section {
+	case SectionScriptInfo:
+		b = appendScriptInfo(b, as)
+	case SectionStyles:
+		b = appendStyles(b, as)
+	case SectionEvents:
+		b = append(b, eventsHeader...)
+		for _, evt := range as.Events {
+			b = appendEvent(b, evt, padding)
+			if len(b) >= serializeChunk {
+				if _, err = w.Write(b); err != nil {
+					return err
+				}
+				b = b[:0]
+			}
+		}
+	case "header":
+		b = appendScriptInfo(b, as)
+		b = appendStyles(b, as)
+		b = append(b, eventsHeader...)
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func appendScriptInfo(b []byte, as *Subtitle) []byte {
+	b = append(b, "\n[Script Info]\nTitle: "...)
+	b = append(b, as.Title...)
+	b = append(b, "\nOriginal Script: "...)
+	b = append(b, as.OriginScript...)
+	b = append(b, "\nScriptType: v4.00+\nCollisions: "...)
+	if as.Collisions == "" {
+		b = append(b, CollisionsNormal...)
+	} else {
+		b = append(b, as.Collisions...)
+	}
+	b = append(b, "\nPlayResX: "...)
+	b = strconv.AppendUint(b, uint64(as.PlayerWidth), 10)
+	b = append(b, "\nPlayResY: "...)
+	b = strconv.AppendUint(b, uint64(as.PlayerHeight), 10)
+	if as.LayoutResX != 0 {
+		b = append(b, "\nLayoutResX: "...)
+		b = strconv.AppendUint(b, uint64(as.LayoutResX), 10)
+	}
+	if as.LayoutResY != 0 {
+		b = append(b, "\nLayoutResY: "...)
+		b = strconv.AppendUint(b, uint64(as.LayoutResY), 10)
+	}
+	b = append(b, "\nTimer: "...)
+	b = strconv.AppendFloat(b, float64(as.Timer), 'f', 4, 32)
+	b = append(b, "\nWrapStyle: "...)
+	b = strconv.AppendInt(b, int64(as.WrapStyle), 10)
+	b = append(b, "\nScaledBorderAndShadow: "...)
+	b = append(b, yesNo(as.ScaledBorderAndShadow)...)
+	if as.Kerning {
+		b = append(b, "\nKerning: yes"...)
+	}
+	if as.YCbCrMatrix != "" {
+		b = append(b, "\nYCbCr Matrix: "...)
+		b = append(b, as.YCbCrMatrix...)
+	}
+	if as.Language != "" {
+		b = append(b, "\nLanguage: "...)
+		b = append(b, as.Language...)
+	}
+	return append(b, '\n')
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
+
+func appendStyles(b []byte, as *Subtitle) []byte {
+	b = append(b, "\n[V4+ Styles]\n"...)
+	b = append(b, stylesFormat...)
+	for _, style := range as.Styles {
+		b = appendStyle(b, style)
+	}
+	return append(b, '\n')
+}
+
+func appendStyle(b []byte, style *Style) []byte {
+	b = append(b, "Style: "...)
+	b = append(b, style.Name...)
+	b = append(b, ',')
+	b = append(b, style.FontName...)
+	b = append(b, ',')
+	b = strconv.AppendInt(b, int64(style.FontSize), 10)
+	b = append(b, ",&H"...)
+	b = append(b, style.PrimaryColor...)
+	b = append(b, ",&H"...)
+	b = append(b, style.SecondColor...)
+	b = append(b, ",&H"...)
+	b = append(b, style.OutlineColor...)
+	b = append(b, ",&H"...)
+	b = append(b, style.BackColor...)
+	for _, v := range []int{bold(style.Bold), style.Italic, style.Underline, style.StrikeOut, scale(style.ScaleX), scale(style.ScaleY)} {
+		b = strconv.AppendInt(append(b, ','), int64(v), 10)
+	}
+	for _, v := range []float64{style.Spacing, style.Angle} {
+		b = append(append(b, ','), formatNumber(v)...)
+	}
+	b = strconv.AppendInt(append(b, ','), int64(borderStyle(style.BorderStyle)), 10)
+	for _, v := range []float64{outline(style.Outline), style.Shadow} {
+		b = append(append(b, ','), formatNumber(v)...)
+	}
+	b = strconv.AppendInt(append(b, ','), int64(alignment(style.Alignment)), 10)
+	for _, v := range []uint{marginH(style.MarginL), marginH(style.MarginR), marginV(style.MarginV)} {
+		b = strconv.AppendUint(append(b, ','), uint64(v), 10)
+	}
+	b = strconv.AppendInt(append(b, ','), int64(style.Encoding), 10)
+	return append(b, '\n')
+}
+
+func appendEvent(b []byte, evt *Event, padding bool) []byte {
+	if evt.Comment {
+		b = append(b, "Comment: "...)
+	} else {
+		b = append(b, "Dialogue: "...)
+	}
+	b = strconv.AppendInt(b, int64(evt.Layer), 10)
+	b = append(b, ',')
+	b = append(b, evt.Start...)
+	b = append(b, ',')
+	b = append(b, evt.End...)
+	b = append(b, ',')
+	b = append(b, evt.Style...)
+	b = append(b, ',')
+	b = append(b, evt.Name...)
+	b = append(b, ',')
+	b = appendMargin(b, evt.MarginL, padding)
+	b = append(b, ',')
+	b = appendMargin(b, evt.MarginR, padding)
+	b = append(b, ',')
+	b = appendMargin(b, evt.MarginV, padding)
+	b = append(b, ',')
+	b = append(b, evt.Effect...)
+	b = append(b, ',')
+	b = appendEventText(b, evt)
+	return append(b, '\n')
+}
+
+// appendEventText appends the text field of evt, with the forced marker and
+// the extra data in front
+func appendEventText(b []byte, evt *Event) []byte {
+	if evt.Forced {
+		b = append(b, forcedMarker...)
+	}
+	b = appendExtra(b, evt.Extra)
+	return appendText(b, evt.Text)
+}
+
+func appendMargin(b []byte, v uint, padding bool) []byte {
+	if padding {
+		for limit := uint(1000); limit > 1 && v < limit; limit /= 10 {
+			b = append(b, '0')
+		}
+	}
+	return strconv.AppendUint(b, uint64(v), 10)
+}
+
+// appendText appends dialogue text with line breaks converted, as sanitizeText
+func appendText(b []byte, text string) []byte {
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; c {
+		case '\r':
+			if i+1 < len(text) && text[i+1] == '\n' {
+				i++
+			}
+			b = append(b, `\N`...)
+		case '\n':
+			b = append(b, `\N`...)
+		default:
+			b = append(b, c)
+		}
+	}
+	return b
+}
@@ -0,0 +1,153 @@
+package ass
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func newBenchSubtitle(n int) Subtitle {
+	sub := Subtitle{
+		Title:                 "Bench",
+		Timer:                 100,
+		WrapStyle:             WrapSmartLower,
+		ScaledBorderAndShadow: true,
+		YCbCrMatrix:           MatrixTV709,
+		Collisions:            CollisionsReverse,
+		LayoutResX:            1920,
+		Kerning:               true,
+		Language:              "en",
+		Styles:                []*Style{{Name: "Default", FontSize: 48, PrimaryColor: "00FFFFFF", SecondColor: "000000FF", OutlineColor: "00000000", BackColor: "80000000"}},
+	}
+	for i := 0; i < n; i++ {
+		sub.Events = append(sub.Events, &Event{
+			Layer:   i % 3,
+			Start:   Timestamp(i * 1e9).String(),
+			End:     Timestamp((i + 1) * 1e9).String(),
+			Style:   "Default",
+			Name:    "Actor",
+			MarginL: uint(i % 20000),
+			Text:    fmt.Sprintf(`{\i1}Line %d{\i0}, with some dialogue text`+"\n", i),
+			Comment: i%10 == 9,
+			Forced:  i%7 == 3,
+		})
+	}
+	return sub
+}
+
+// forceTemplate makes the writer use text/template instead of the serializer
+var forceTemplate = WithFuncs(template.FuncMap{})
+
+func TestSerializerMatchesTemplate(t *testing.T) {
+	sub := newBenchSubtitle(2000)
+	sub.Styles = append(sub.Styles, &Style{Name: "Signs", FontName: "Verdana", FontSize: 30})
+	sub.Events[3].Extra = map[string]string{"id": "3", "note": `a {\b1} c`}
+
+	cases := [][]WriteOption{
+		nil,
+		{WithPadding(false)},
+		{WithLineEnding(CRLF), WithEncoding(EncodingUTF8BOM)},
+		{WithSectionOrder(SectionEvents, SectionStyles, SectionScriptInfo)},
+	}
+
+	for i, opts := range cases {
+		var direct, tpl bytes.Buffer
+		if _, err := sub.WriteToWithOptions(&direct, opts...); err != nil {
+			t.Fatalf("Case %d: expect write success, got: %v", i, err)
+		}
+		if _, err := sub.WriteToWithOptions(&tpl, append(opts, forceTemplate)...); err != nil {
+			t.Fatalf("Case %d: expect write success, got: %v", i, err)
+		}
+		if direct.String() != tpl.String() {
+			t.Errorf("Case %d: expect the same output as the template", i)
+		}
+	}
+}
+
+func BenchmarkWriteTo(b *testing.B) {
+	sub := newBenchSubtitle(50000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sub.WriteTo(ioutil.Discard)
+	}
+}
+
+func BenchmarkWriteToTemplate(b *testing.B) {
+	sub := newBenchSubtitle(50000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sub.WriteToWithOptions(ioutil.Discard, forceTemplate)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	if _, err := parseTemplate(`{{define "event"}}{{.Text}`); err == nil {
+		t.Errorf("Expect template parse error, but passed")
+	}
+
+	tpl, err := newTemplate()
+	if err != nil {
+		t.Fatalf("Expect default template parsed, got: %v", err)
+	}
+	if _, err := tpl.Parse(`{{define "event"}}custom{{end}}`); err != nil {
+		t.Fatalf("Expect custom template parsed, got: %v", err)
+	}
+
+	// customizing a copy must not change the cached template
+	again, err := newTemplate()
+	if err != nil {
+		t.Fatalf("Expect default template parsed, got: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := again.ExecuteTemplate(&buf, "event", &Event{Text: "default"}); err != nil {
+		t.Fatalf("Expect event executed, got: %v", err)
+	}
+	if buf.String() != "Dialogue: 0,,,,,0000,0000,0000,,default\n" {
+		t.Errorf("Unexpected event line: %q", buf.String())
+	}
+}
+
+func TestStyleRoundTrip(t *testing.T) {
+	input := `
+[Script Info]
+Title: Styled
+Original Script: someone
+ScriptType: v4.00+
+Collisions: Normal
+PlayResX: 1280
+PlayResY: 720
+Timer: 100.0000
+WrapStyle: 0
+ScaledBorderAndShadow: yes
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Default,Arial,48,&H00FFFFFF,&H000000FF,&H00000000,&H80000000,1,-1,0,0,100,100,0,0,1,2,2,2,10,10,10,1
+Style: Sign,Verdana,31,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,-1,0,-1,-1,90,110,1.5,-12.5,3,0.5,1.25,7,5,35,12,128
+
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+Dialogue: 0,0:00:01.00,0:00:02.50,Default,,0000,0000,0000,,{\i1}Hello
+Dialogue: 0,0:00:03.00,0:00:04.00,Sign,,0000,0000,0000,,EXIT
+
+`
+	sub, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expect parse success, got: %v", err)
+	}
+	for _, opts := range [][]WriteOption{nil, {forceTemplate}} {
+		var buf bytes.Buffer
+		if _, err := sub.WriteToWithOptions(&buf, opts...); err != nil {
+			t.Fatalf("Expect write success, got: %v", err)
+		}
+		if buf.String() != input {
+			t.Errorf("Expect the input written back, got: %s", buf.String())
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package ass
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ShakeOptions configures Subtitle.Shake
+type ShakeOptions struct {
+	// Amplitude is the largest offset from the position, in script pixels
+	Amplitude float64
+	// Interval is how long each position is held, 40ms by default, a frame
+	// at 25 fps
+	Interval time.Duration
+	// Decay lowers the amplitude linearly to 0 at the end of the event
+	Decay bool
+	// Seed seeds the offsets, the same seed gives the same shake
+	Seed int64
+}
+
+// Shake splits the event into short events with random offsets around its
+// position, for impact lines. The position is the \pos of the event, the
+// point of its \move at the time, or else the point the renderer would
+// anchor it at, from its alignment and margins.
+func (as *Subtitle) Shake(evt *Event, opts ShakeOptions) ([]*Event, error) {
+	start, end, err := evt.times()
+	if err != nil {
+		return nil, err
+	}
+	if opts.Amplitude < 0 {
+		return nil, fmt.Errorf("Invalid amplitude: %v", opts.Amplitude)
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = 40 * time.Millisecond
+	}
+
+	at := as.shakePosition(evt)
+	text := removeTags(evt.Text, func(tag string) bool {
+		return strings.HasPrefix(tag, `\pos(`) || strings.HasPrefix(tag, `\move(`)
+	})
+	rnd := rand.New(rand.NewSource(opts.Seed))
+	var events []*Event
+	for from := start; from < end; from += Timestamp(interval) {
+		to := from + Timestamp(interval)
+		if to > end {
+			to = end
+		}
+		e := evt.clone()
+		e.Start, e.End = from.String(), to.String()
+		if e.Start == e.End {
+			continue
+		}
+		amplitude := opts.Amplitude
+		if opts.Decay {
+			amplitude *= float64(end-from) / float64(end-start)
+		}
+		x, y := at(from - start)
+		x += (rnd.Float64()*2 - 1) * amplitude
+		y += (rnd.Float64()*2 - 1) * amplitude
+		e.Text = prependTags(text, `\pos(`+formatNumber(x)+","+formatNumber(y)+")")
+		events = append(events, &e)
+	}
+	return events, nil
+}
+
+// shakePosition returns the position of the event at a time from its start
+func (as *Subtitle) shakePosition(evt *Event) func(t Timestamp) (x, y float64) {
+	for _, p := range splitText(evt.Text) {
+		if !p.Override {
+			continue
+		}
+		for _, tag := range splitTags(p.Text) {
+			if !strings.HasPrefix(tag, `\move(`) || !strings.HasSuffix(tag, ")") {
+				continue
+			}
+			var v []float64
+			for _, arg := range strings.Split(tag[6:len(tag)-1], ",") {
+				n, err := strconv.ParseFloat(strings.TrimSpace(arg), 64)
+				if err != nil {
+					break
+				}
+				v = append(v, n)
+			}
+			if len(v) != 4 && len(v) != 6 {
+				continue
+			}
+			start, end, _ := evt.times()
+			t1, t2 := 0.0, float64(time.Duration(end-start)/time.Millisecond)
+			if len(v) == 6 {
+				t1, t2 = v[4], v[5]
+			}
+			return func(t Timestamp) (float64, float64) {
+				ms := float64(time.Duration(t) / time.Millisecond)
+				k := 1.0
+				switch {
+				case ms <= t1:
+					k = 0
+				case ms < t2:
+					k = (ms - t1) / (t2 - t1)
+				}
+				return v[0] + (v[2]-v[0])*k, v[1] + (v[3]-v[1])*k
+			}
+		}
+	}
+
+	// \pos, or the anchor point of the text
+	align, x, y, positioned := as.eventLayout(evt)
+	if !positioned {
+		box, _ := as.textBox(evt, nil)
+		x, y = anchorPoint(box, align)
+	}
+	return func(Timestamp) (float64, float64) { return x, y }
+}
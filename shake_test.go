@@ -0,0 +1,75 @@
+package ass
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShake(t *testing.T) {
+	sub := &Subtitle{PlayerWidth: 640, PlayerHeight: 480}
+	cases := []struct {
+		text   string
+		opts   ShakeOptions
+		expect []string
+	}{
+		{`{\pos(10,20)\b1}Boom`, ShakeOptions{Interval: 100 * time.Millisecond}, []string{
+			`0:00:01.00 0:00:01.10 {\pos(10,20)\b1}Boom`,
+			`0:00:01.10 0:00:01.20 {\pos(10,20)\b1}Boom`,
+			`0:00:01.20 0:00:01.25 {\pos(10,20)\b1}Boom`,
+		}},
+		{`{\move(0,0,50,100,50,150)}Boom`, ShakeOptions{Interval: 100 * time.Millisecond}, []string{
+			`0:00:01.00 0:00:01.10 {\pos(0,0)}Boom`,
+			`0:00:01.10 0:00:01.20 {\pos(25,50)}Boom`,
+			`0:00:01.20 0:00:01.25 {\pos(50,100)}Boom`,
+		}},
+		{`Boom`, ShakeOptions{Interval: 200 * time.Millisecond}, []string{
+			`0:00:01.00 0:00:01.20 {\pos(320,478)}Boom`,
+			`0:00:01.20 0:00:01.25 {\pos(320,478)}Boom`,
+		}},
+	}
+	for _, c := range cases {
+		events, err := sub.Shake(&Event{Start: "0:00:01.00", End: "0:00:01.25", Text: c.text}, c.opts)
+		if err != nil {
+			t.Fatalf("Expect no error, got: %v", err)
+		}
+		var got []string
+		for _, e := range events {
+			got = append(got, e.Start+" "+e.End+" "+e.Text)
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+	}
+
+	// anchored as the style aligns the text
+	top := &Subtitle{PlayerWidth: 640, PlayerHeight: 480, Styles: []*Style{{Name: "Top", Alignment: 9, MarginR: 40, MarginV: 30}}}
+	events, err := top.Shake(&Event{Start: "0:00:01.00", End: "0:00:01.20", Style: "Top", Text: "Boom"}, ShakeOptions{Interval: 200 * time.Millisecond})
+	if err != nil || len(events) != 1 || events[0].Text != `{\pos(600,30)}Boom` {
+		t.Errorf("Expect the top right anchor, got: %+v %v", events, err)
+	}
+
+	evt := &Event{Start: "0:00:00.00", End: "0:00:01.00", Text: `{\pos(100,100)}Boom`}
+	opts := ShakeOptions{Amplitude: 5, Decay: true, Seed: 1}
+	a, _ := sub.Shake(evt, opts)
+	b, _ := sub.Shake(evt, opts)
+	if len(a) != 25 || !reflect.DeepEqual(a, b) {
+		t.Fatalf("Expect 25 identical events with the same seed, got: %d", len(a))
+	}
+	for i, e := range a {
+		args := strings.Split(strings.TrimSuffix(strings.TrimPrefix(e.Text, `{\pos(`), `)}Boom`), ",")
+		x, _ := strconv.ParseFloat(args[0], 64)
+		y, _ := strconv.ParseFloat(args[1], 64)
+		limit := 5 * float64(25-i) / 25
+		if math.Abs(x-100) > limit+0.001 || math.Abs(y-100) > limit+0.001 {
+			t.Errorf("Expect an offset within %v, got: %s", limit, e.Text)
+		}
+	}
+
+	if _, err := sub.Shake(evt, ShakeOptions{Amplitude: -1}); err == nil {
+		t.Errorf("Expect an error for a negative amplitude")
+	}
+}
@@ -0,0 +1,130 @@
+package ass
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Rect is a rectangle in script pixels
+type Rect struct {
+	X, Y, Width, Height float64
+}
+
+// SignFit is the placement of a sign filling a box
+type SignFit struct {
+	// X and Y are the center of the box, the position with \an5
+	X, Y float64
+	// FontSize makes the text about as high as the box
+	FontSize int
+	// ScaleX and ScaleY, in percent, stretch the text to the exact box
+	ScaleX, ScaleY float64
+}
+
+// Tags returns the override block placing the text
+func (f SignFit) Tags() string {
+	return fmt.Sprintf(`{\an5\pos(%s,%s)\fs%d\fscx%s\fscy%s}`, formatNumber(f.X), formatNumber(f.Y),
+		f.FontSize, formatNumber(f.ScaleX), formatNumber(f.ScaleY))
+}
+
+// FitSign computes the placement making text, rendered in style, fill box,
+// such as the box of the original sign in the video. The font size is
+// scaled so that the text is as high as the box, then \fscx and \fscy fix
+// the remaining differences. A nil m measures with ApproxMeasurer.
+func FitSign(text string, style *Style, box Rect, m TextMeasurer) SignFit {
+	if m == nil {
+		m = ApproxMeasurer{}
+	}
+	fit := SignFit{X: box.X + box.Width/2, Y: box.Y + box.Height/2, FontSize: defaultFontSize, ScaleX: 100, ScaleY: 100}
+	s := Style{FontSize: defaultFontSize}
+	if style != nil {
+		s = *style
+		if s.FontSize <= 0 {
+			s.FontSize = defaultFontSize
+		}
+	}
+	s.ScaleX, s.ScaleY = 100, 100
+
+	_, h := MeasureText(m, text, &s)
+	if h > 0 && box.Height > 0 {
+		if size := int(math.Round(float64(s.FontSize) * box.Height / h)); size > 0 {
+			s.FontSize = size
+		}
+	}
+	fit.FontSize = s.FontSize
+	w, h := MeasureText(m, text, &s)
+	if w > 0 && box.Width > 0 {
+		fit.ScaleX = 100 * box.Width / w
+	}
+	if h > 0 && box.Height > 0 {
+		fit.ScaleY = 100 * box.Height / h
+	}
+	return fit
+}
+
+// FitSign places the event so that its text fills box, see FitSign. The
+// positioning, alignment and font size tags of the event are replaced.
+func (as *Subtitle) FitSign(evt *Event, box Rect, m TextMeasurer) {
+	fit := FitSign(evt.Text, as.styleByName(evt.Style), box, m)
+	evt.Text = fit.Tags() + removeTags(evt.Text, isFitTag)
+}
+
+// removeTags removes the tags matching drop from text, and the override
+// blocks left empty
+func removeTags(text string, drop func(tag string) bool) string {
+	parts := splitText(text)
+	result := parts[:0]
+	for _, p := range parts {
+		if p.Override {
+			// the comments before the first tag are kept
+			kept := p.Text
+			if i := strings.IndexByte(kept, '\\'); i >= 0 {
+				kept = kept[:i]
+			}
+			removed := false
+			for _, tag := range splitTags(p.Text) {
+				if drop(tag) {
+					removed = true
+				} else {
+					kept += tag
+				}
+			}
+			if removed && kept == "" {
+				continue
+			}
+			p.Text = kept
+		}
+		result = append(result, p)
+	}
+	return joinText(result)
+}
+
+// isFitTag reports whether the tag is replaced by FitSign: \pos, \move, \an,
+// \a, \fs, \fscx and \fscy
+func isFitTag(tag string) bool {
+	isNum := func(s string) bool {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return false
+		}
+		for _, c := range s {
+			if (c < '0' || c > '9') && c != '.' && c != '-' {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case strings.HasPrefix(tag, `\pos(`), strings.HasPrefix(tag, `\move(`):
+		return true
+	case strings.HasPrefix(tag, `\fscx`), strings.HasPrefix(tag, `\fscy`):
+		return isNum(tag[5:])
+	case strings.HasPrefix(tag, `\an`):
+		return isNum(tag[3:])
+	case strings.HasPrefix(tag, `\fs`):
+		return isNum(tag[3:])
+	case strings.HasPrefix(tag, `\a`):
+		return isNum(tag[2:])
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package ass
+
+import "testing"
+
+func TestFitSign(t *testing.T) {
+	fixed := TextMeasurerFunc(func(text string, style *Style) (float64, float64) {
+		return float64(len(text)*style.FontSize) / 2, float64(style.FontSize)
+	})
+	cases := []struct {
+		text  string
+		style *Style
+		box   Rect
+		fit   SignFit
+	}{
+		{"abcd", nil, Rect{X: 100, Y: 50, Width: 120, Height: 40}, SignFit{X: 160, Y: 70, FontSize: 40, ScaleX: 150, ScaleY: 100}},
+		{`a\Nb`, &Style{FontSize: 50, ScaleX: 80}, Rect{Width: 10, Height: 100}, SignFit{X: 5, Y: 50, FontSize: 50, ScaleX: 40, ScaleY: 100}},
+		{"ab", nil, Rect{Width: 10, Height: 15}, SignFit{X: 5, Y: 7.5, FontSize: 15, ScaleX: 66.66666666666667, ScaleY: 100}},
+	}
+	for _, c := range cases {
+		if fit := FitSign(c.text, c.style, c.box, fixed); fit != c.fit {
+			t.Errorf("Expect %+v for %q, got: %+v", c.fit, c.text, fit)
+		}
+	}
+
+	fit := SignFit{X: 5, Y: 7.5, FontSize: 15, ScaleX: 66.66666666666667, ScaleY: 100}
+	if tags := fit.Tags(); tags != `{\an5\pos(5,7.5)\fs15\fscx66.667\fscy100}` {
+		t.Errorf("Expect the override block, got: %s", tags)
+	}
+}
+
+func TestSubtitleFitSign(t *testing.T) {
+	sub := &Subtitle{Styles: []*Style{{Name: "Sign", FontSize: 10}}}
+	evt := &Event{Style: "Sign", Text: `{\an7\pos(1,2)\b1\fs30\fsp2}Exit{\fscx50}{note}`}
+	sub.FitSign(evt, Rect{Width: 100, Height: 20}, nil)
+	expect := `{\an5\pos(50,10)\fs20\fscx284.091\fscy100}{\b1\fsp2}Exit{note}`
+	if evt.Text != expect {
+		t.Errorf("Expect %s, got: %s", expect, evt.Text)
+	}
+}
@@ -0,0 +1,46 @@
+package ass
+
+// Chapter is a named time range of the media
+type Chapter struct {
+	Title string    `json:"title"`
+	Start Timestamp `json:"start"`
+	End   Timestamp `json:"end"`
+}
+
+// Slice returns a new subtitle with the events visible in [from, to),
+// clipped to the range and retimed so that from becomes 0. Styles and the
+// script info are copied, events with invalid timestamps are dropped.
+// Timing inside the text, like \move or karaoke tags, is not adjusted.
+func (as *Subtitle) Slice(from, to Timestamp) *Subtitle {
+	sliced := *as
+	sliced.index = nil
+	sliced.Styles = copyStyles(as.Styles)
+	sliced.Events = nil
+	for _, evt := range as.Events {
+		if evt == nil {
+			continue
+		}
+		start, end, err := evt.times()
+		if err != nil || start >= to || end <= from {
+			continue
+		}
+		clipped := evt.clone()
+		clipped.Start = (maxTimestamp(start, from) - from).String()
+		clipped.End = (minTimestamp(end, to) - from).String()
+		sliced.Events = append(sliced.Events, &clipped)
+	}
+	return &sliced
+}
+
+// SplitByChapters slices the subtitle into a subtitle per chapter, titled
+// after the chapter if it has a title
+func (as *Subtitle) SplitByChapters(chapters []Chapter) []*Subtitle {
+	parts := make([]*Subtitle, len(chapters))
+	for i, chapter := range chapters {
+		parts[i] = as.Slice(chapter.Start, chapter.End)
+		if chapter.Title != "" {
+			parts[i].Title = chapter.Title
+		}
+	}
+	return parts
+}
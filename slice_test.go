@@ -0,0 +1,52 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSlice(t *testing.T) {
+	sub := &Subtitle{
+		Title:  "Movie",
+		Styles: []*Style{{Name: "Default"}},
+		Events: []*Event{
+			{Start: "0:00:01.00", End: "0:00:03.00", Text: "a"},
+			{Start: "0:00:09.00", End: "0:00:11.00", Text: "b"},
+			{Start: "0:00:12.00", End: "0:00:13.00", Text: "c"},
+		},
+	}
+
+	parts := sub.SplitByChapters([]Chapter{
+		{Title: "Part 1", Start: 0, End: Timestamp(10 * time.Second)},
+		{Start: Timestamp(10 * time.Second), End: Timestamp(20 * time.Second)},
+	})
+	if len(parts) != 2 || parts[0].Title != "Part 1" || parts[1].Title != "Movie" {
+		t.Fatalf("Unexpected parts: %+v", parts)
+	}
+
+	expects := [][]Event{
+		{
+			{Start: "0:00:01.00", End: "0:00:03.00", Text: "a"},
+			{Start: "0:00:09.00", End: "0:00:10.00", Text: "b"},
+		},
+		{
+			{Start: "0:00:00.00", End: "0:00:01.00", Text: "b"},
+			{Start: "0:00:02.00", End: "0:00:03.00", Text: "c"},
+		},
+	}
+	for i, events := range expects {
+		if len(parts[i].Events) != len(events) {
+			t.Errorf("Part %d: expect %d events, got: %d", i, len(events), len(parts[i].Events))
+			continue
+		}
+		for j, evt := range events {
+			if !reflect.DeepEqual(*parts[i].Events[j], evt) {
+				t.Errorf("Part %d: expect %+v, got: %+v", i, evt, *parts[i].Events[j])
+			}
+		}
+	}
+	if sub.Events[1].End != "0:00:11.00" || parts[0].Styles[0] == sub.Styles[0] {
+		t.Errorf("Expect the subtitle left untouched")
+	}
+}
@@ -0,0 +1,98 @@
+package ass
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Misspelling is a word a spell checker does not know
+type Misspelling struct {
+	// Offset is the byte offset of the word in the checked text
+	Offset      int      `json:"offset"`
+	Word        string   `json:"word"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// SpellChecker checks the spelling of plain text in a language, a BCP 47
+// tag or empty for the default one, e.g. through hunspell or a service
+type SpellChecker interface {
+	Check(text, lang string) ([]Misspelling, error)
+}
+
+// SpellCheckerFunc adapts a function to a SpellChecker
+type SpellCheckerFunc func(text, lang string) ([]Misspelling, error)
+
+// Check calls fn
+func (fn SpellCheckerFunc) Check(text, lang string) ([]Misspelling, error) {
+	return fn(text, lang)
+}
+
+// SpellIssue is a misspelling in an event
+type SpellIssue struct {
+	// Index is the index of the event
+	Index int `json:"index"`
+	// Offset is the byte offset of the word in the text of the event
+	// without its tags, see StripTags
+	Offset      int      `json:"offset"`
+	Word        string   `json:"word"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+func (issue SpellIssue) String() string {
+	return located(0, "Events", issue.Index, "Text", fmt.Sprintf("Misspelled word at %d: %s", issue.Offset, issue.Word))
+}
+
+// spellText returns the text of an event as checked: tags removed, and line
+// breaks, hard spaces and drawings blanked, so that the offsets are the
+// same as in StripTags
+func spellText(text string) string {
+	var b strings.Builder
+	drawing := false
+	for _, p := range splitText(text) {
+		if p.Override {
+			for _, tag := range splitTags(p.Text) {
+				if level, ok := drawingTag(tag); ok {
+					drawing = level > 0
+				}
+			}
+			continue
+		}
+		if drawing {
+			b.WriteString(strings.Repeat(" ", len(p.Text)))
+			continue
+		}
+		b.WriteString(strings.NewReplacer(`\N`, "  ", `\n`, "  ", `\h`, "  ").Replace(p.Text))
+	}
+	return b.String()
+}
+
+// SpellCheck checks the spelling of the events but comments and returns the
+// misspellings in order. The language of each event is given by lang, the
+// language of the subtitle is used if lang is nil or returns "".
+func (as *Subtitle) SpellCheck(checker SpellChecker, lang func(evt *Event) string) ([]SpellIssue, error) {
+	var issues []SpellIssue
+	for i, evt := range as.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		text := spellText(evt.Text)
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		var language string
+		if lang != nil {
+			language = lang(evt)
+		}
+		if language == "" {
+			language = as.Language
+		}
+		misspellings, err := checker.Check(text, language)
+		if err != nil {
+			return nil, fmt.Errorf("%s", located(0, "Events", i, "Text", err.Error()))
+		}
+		for _, m := range misspellings {
+			issues = append(issues, SpellIssue{Index: i, Offset: m.Offset, Word: m.Word, Suggestions: m.Suggestions})
+		}
+	}
+	return issues, nil
+}
@@ -0,0 +1,59 @@
+package ass
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSpellCheck(t *testing.T) {
+	known := map[string]bool{"hello": true, "world": true, "bonjour": true, "monde": true}
+	var langs []string
+	checker := SpellCheckerFunc(func(text, lang string) ([]Misspelling, error) {
+		langs = append(langs, lang)
+		var found []Misspelling
+		offset := 0
+		for _, word := range strings.Fields(text) {
+			offset += strings.Index(text[offset:], word)
+			if !known[strings.ToLower(strings.Trim(word, ",!"))] {
+				found = append(found, Misspelling{Offset: offset, Word: word, Suggestions: []string{"hello"}})
+			}
+			offset += len(word)
+		}
+		return found, nil
+	})
+	sub := &Subtitle{Language: "en", Events: []*Event{
+		{Text: `{\i1}Hello{\i0}, wrold!\Nhello`},
+		{Text: `helo`, Comment: true},
+		nil,
+		{Style: "French", Text: `Bonjour{\p1}m 0 0 l 1 1{\p0} mnde`},
+		{Text: `{\pos(1,1)}`},
+	}}
+	issues, err := sub.SpellCheck(checker, func(evt *Event) string {
+		if evt.Style == "French" {
+			return "fr"
+		}
+		return ""
+	})
+	expect := []SpellIssue{
+		{Index: 0, Offset: 7, Word: "wrold!", Suggestions: []string{"hello"}},
+		{Index: 3, Offset: 19, Word: "mnde", Suggestions: []string{"hello"}},
+	}
+	if err != nil || !reflect.DeepEqual(issues, expect) || !reflect.DeepEqual(langs, []string{"en", "fr"}) {
+		t.Fatalf("Expect %v, got: %v, %v, %v", expect, issues, langs, err)
+	}
+	if word := StripTags(sub.Events[3].Text)[19:23]; word != "mnde" {
+		t.Errorf("Expect the offset in the stripped text, got: %s", word)
+	}
+	if s := issues[0].String(); s != "Events[0].Text: Misspelled word at 7: wrold!" {
+		t.Errorf("Expect the issue located, got: %s", s)
+	}
+
+	_, err = sub.SpellCheck(SpellCheckerFunc(func(string, string) ([]Misspelling, error) {
+		return nil, fmt.Errorf("Unavailable")
+	}), nil)
+	if err == nil || err.Error() != "Events[0].Text: Unavailable" {
+		t.Errorf("Expect the checker error, got: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package ass
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SplitAt splits the event at t into two events covering [Start, t) and
+// [t, End), both keeping the full text. If t is not strictly inside the
+// event, a is a copy of the event and b is nil.
+func (evt Event) SplitAt(t Timestamp) (a, b *Event) {
+	first := evt.clone()
+	start, end, err := evt.times()
+	if err != nil || t <= start || t >= end {
+		return &first, nil
+	}
+	second := evt.clone()
+	first.End = t.String()
+	second.Start = t.String()
+	return &first, &second
+}
+
+// MergeEvents merges fragmented events into one, spanning from the earliest
+// start to the latest end. The other fields are taken from the earliest event.
+// Texts are joined in time order: empty and repeated fragments are dropped,
+// and fragments are separated by a space unless they meet at CJK characters.
+func MergeEvents(events ...*Event) (*Event, error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("No events to merge")
+	}
+
+	type timed struct {
+		evt        *Event
+		start, end Timestamp
+	}
+	list := make([]timed, 0, len(events))
+	for _, evt := range events {
+		if evt == nil {
+			return nil, fmt.Errorf("Event cannot be nil")
+		}
+		start, end, err := evt.times()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, timed{evt, start, end})
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].start < list[j].start })
+
+	merged := list[0].evt.clone()
+	end := list[0].end
+	var text, last string
+	for _, item := range list {
+		if item.end > end {
+			end = item.end
+		}
+		frag := strings.TrimSpace(item.evt.Text)
+		if frag == "" || frag == last {
+			continue
+		}
+		text = joinFragments(text, frag)
+		last = frag
+	}
+	merged.End = end.String()
+	merged.Text = text
+	return &merged, nil
+}
+
+// joinFragments joins two pieces of dialogue text
+func joinFragments(a, b string) string {
+	if a == "" {
+		return b
+	}
+	left, _ := utf8.DecodeLastRuneInString(StripTags(a))
+	right, _ := utf8.DecodeRuneInString(StripTags(b))
+	if isCJK(left) || isCJK(right) {
+		return a + b
+	}
+	return a + " " + b
+}
+
+// isCJK reports whether r belongs to a script written without spaces
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
@@ -0,0 +1,77 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitAt(t *testing.T) {
+	evt := Event{Start: "0:00:01.00", End: "0:00:03.00", Text: "Hello"}
+
+	a, b := evt.SplitAt(Timestamp(2e9))
+	if b == nil || a.End != "0:00:02.00" || b.Start != "0:00:02.00" || b.End != evt.End {
+		t.Errorf("Expect split at 0:00:02.00, got: %+v %+v", a, b)
+	}
+
+	a, b = evt.SplitAt(Timestamp(3e9))
+	if b != nil || !reflect.DeepEqual(*a, evt) {
+		t.Errorf("Expect no split at the end, got: %+v %+v", a, b)
+	}
+}
+
+func TestMergeEvents(t *testing.T) {
+	cases := []struct {
+		input  []*Event
+		expect Event
+	}{
+		{
+			[]*Event{
+				{Start: "0:00:02.00", End: "0:00:03.00", Text: "world"},
+				{Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", Text: "Hello"},
+			},
+			Event{Start: "0:00:01.00", End: "0:00:03.00", Style: "Default", Text: "Hello world"},
+		},
+		{
+			[]*Event{
+				{Start: "0:00:01.00", End: "0:00:02.00", Text: "こんにちは"},
+				{Start: "0:00:02.00", End: "0:00:04.00", Text: "こんにちは"},
+				{Start: "0:00:03.00", End: "0:00:03.50", Text: "世界"},
+			},
+			Event{Start: "0:00:01.00", End: "0:00:04.00", Text: "こんにちは世界"},
+		},
+	}
+
+	for _, c := range cases {
+		got, err := MergeEvents(c.input...)
+		if err != nil {
+			t.Errorf("Expect merge success, got: %v", err)
+			continue
+		}
+		if !reflect.DeepEqual(*got, c.expect) {
+			t.Errorf("Expect %+v, got: %+v", c.expect, *got)
+		}
+	}
+
+	if _, err := MergeEvents(); err == nil {
+		t.Errorf("Expect error merging nothing, but passed")
+	}
+}
+
+func TestSplitAtExtra(t *testing.T) {
+	evt := Event{Start: "0:00:01.00", End: "0:00:03.00", Text: "Hello", Extra: map[string]string{"id": "1"}}
+	a, b := evt.SplitAt(Timestamp(2e9))
+	a.SetExtra("id", "a")
+	b.SetExtra("id", "b")
+	if evt.Extra["id"] != "1" || a.Extra["id"] != "a" || b.Extra["id"] != "b" {
+		t.Errorf("Expect the halves not to share their extra data, got: %v, %v, %v", evt.Extra, a.Extra, b.Extra)
+	}
+
+	merged, err := MergeEvents(a, b)
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	merged.SetExtra("id", "merged")
+	if a.Extra["id"] != "a" {
+		t.Errorf("Expect the merged event not to share the extra data, got: %v", a.Extra)
+	}
+}
@@ -0,0 +1,56 @@
+package ass
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// The events, styles and subtitles are stored in databases as JSON, e.g. in
+// jsonb columns of PostgreSQL.
+
+// Value implements driver.Valuer
+func (evt Event) Value() (driver.Value, error) {
+	return json.Marshal(evt)
+}
+
+// Scan implements sql.Scanner
+func (evt *Event) Scan(src interface{}) error {
+	*evt = Event{}
+	return scanJSON(src, evt)
+}
+
+// Value implements driver.Valuer
+func (style Style) Value() (driver.Value, error) {
+	return json.Marshal(style)
+}
+
+// Scan implements sql.Scanner
+func (style *Style) Scan(src interface{}) error {
+	*style = Style{}
+	return scanJSON(src, style)
+}
+
+// Value implements driver.Valuer
+func (as Subtitle) Value() (driver.Value, error) {
+	return json.Marshal(as)
+}
+
+// Scan implements sql.Scanner
+func (as *Subtitle) Scan(src interface{}) error {
+	*as = Subtitle{}
+	return scanJSON(src, as)
+}
+
+// scanJSON decodes a JSON column, NULL leaves dst zero
+func scanJSON(src interface{}, dst interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dst)
+	case string:
+		return json.Unmarshal([]byte(v), dst)
+	}
+	return fmt.Errorf("Cannot scan %T into %T", src, dst)
+}
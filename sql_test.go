@@ -0,0 +1,43 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSQL(t *testing.T) {
+	sub := Subtitle{
+		Title:  "sql",
+		Styles: []*Style{{Name: "Default", FontSize: 20}},
+		Events: []*Event{{Start: "0:00:01.00", End: "0:00:02.00", Text: "stored"}},
+	}
+	v, err := sub.Value()
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	var got Subtitle
+	if err := got.Scan(v); err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	if !reflect.DeepEqual(got, sub) {
+		t.Errorf("Expect %+v, got: %+v", sub, got)
+	}
+
+	v, _ = sub.Events[0].Value()
+	evt := Event{Text: "old"}
+	if err := evt.Scan(string(v.([]byte))); err != nil || !reflect.DeepEqual(evt, *sub.Events[0]) {
+		t.Errorf("Expect %v, got: %v, %v", sub.Events[0], evt, err)
+	}
+	v, _ = sub.Styles[0].Value()
+	var style Style
+	if err := style.Scan(v); err != nil || style != *sub.Styles[0] {
+		t.Errorf("Expect %v, got: %v, %v", sub.Styles[0], style, err)
+	}
+
+	if err := evt.Scan(nil); err != nil || !reflect.DeepEqual(evt, Event{}) {
+		t.Errorf("Expect NULL to scan as zero, got: %v, %v", evt, err)
+	}
+	if err := evt.Scan(42); err == nil {
+		t.Errorf("Expect error scanning an int")
+	}
+}
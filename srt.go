@@ -0,0 +1,206 @@
+package ass
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	srtTimeReg  = regexp.MustCompile(`^\s*(\d+):(\d\d):(\d\d)[,.](\d{1,3})\s*-->\s*(\d+):(\d\d):(\d\d)[,.](\d{1,3})`)
+	srtTagReg   = regexp.MustCompile(`(?i)<(/?)(i|b|u|s|font)(\s[^>]*)?>`)
+	srtColorReg = regexp.MustCompile(`(?i)color\s*=\s*"?#?([0-9a-f]{6})`)
+)
+
+// ParseSRT reads a SubRip subtitle. The cues become events of the Default
+// style, with <i>, <b>, <u>, <s> and <font color> converted to override tags.
+// Like Parse, gzip and zip inputs are decompressed.
+func ParseSRT(r io.Reader) (*Subtitle, error) {
+	d, err := newSRTDecoder(r)
+	if err != nil {
+		return nil, err
+	}
+	sub := d.header()
+	for {
+		evt, err := d.decode()
+		if err == io.EOF {
+			return sub, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		sub.Events = append(sub.Events, evt)
+	}
+}
+
+// newCueSubtitle returns a subtitle with the Default style of the events
+// converted from SubRip or WebVTT cues
+func newCueSubtitle() *Subtitle {
+	return &Subtitle{Styles: []*Style{{
+		Name:         "Default",
+		FontName:     defFontName,
+		FontSize:     72,
+		PrimaryColor: "00FFFFFF",
+		SecondColor:  "000000FF",
+		OutlineColor: "00000000",
+		BackColor:    "00000000",
+		ScaleX:       100,
+		ScaleY:       100,
+	}}}
+}
+
+// srtDecoder reads the cues of a SubRip subtitle one by one
+type srtDecoder struct {
+	scanner *bufio.Scanner
+	lineNo  int
+	evt     *Event   // the cue being read
+	lines   []string // its text lines so far
+	charset string
+}
+
+func newSRTDecoder(r io.Reader) (*srtDecoder, error) {
+	r, err := decompress(r, ".srt")
+	if err != nil {
+		return nil, err
+	}
+	decoded, charset, err := DetectEncoding(r)
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(decoded)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	return &srtDecoder{scanner: scanner, charset: charset}, nil
+}
+
+func (d *srtDecoder) header() *Subtitle {
+	sub := newCueSubtitle()
+	sub.charset = d.charset
+	return sub
+}
+
+// finish returns the cue being read, if any, with its text
+func (d *srtDecoder) finish() *Event {
+	evt := d.evt
+	if evt != nil {
+		evt.Text = srtText(strings.Join(d.lines, "\n"))
+	}
+	d.evt, d.lines = nil, nil
+	return evt
+}
+
+// decode reads the next cue, io.EOF at the end of the input
+func (d *srtDecoder) decode() (*Event, error) {
+	for d.scanner.Scan() {
+		d.lineNo++
+		line := strings.TrimRight(d.scanner.Text(), "\r")
+		if d.lineNo == 1 {
+			line = strings.TrimPrefix(line, utf8BOM)
+		}
+		if m := srtTimeReg.FindStringSubmatch(line); m != nil {
+			// a text line may be the index of the next cue
+			if d.evt != nil && len(d.lines) > 0 {
+				if _, err := strconv.Atoi(strings.TrimSpace(d.lines[len(d.lines)-1])); err == nil {
+					d.lines = d.lines[:len(d.lines)-1]
+				}
+			}
+			done := d.finish()
+			d.evt = &Event{Style: "Default", Start: srtTimestamp(m[1:5]).String(), End: srtTimestamp(m[5:9]).String()}
+			if done != nil {
+				return done, nil
+			}
+			continue
+		}
+		if d.evt == nil {
+			if strings.TrimSpace(line) != "" {
+				if _, err := strconv.Atoi(strings.TrimSpace(line)); err != nil {
+					return nil, fmt.Errorf("Line %d: Invalid cue: %s", d.lineNo, line)
+				}
+			}
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			return d.finish(), nil
+		}
+		d.lines = append(d.lines, line)
+	}
+	if err := d.scanner.Err(); err != nil {
+		return nil, err
+	}
+	if evt := d.finish(); evt != nil {
+		return evt, nil
+	}
+	return nil, io.EOF
+}
+
+// srtTimestamp converts the hours, minutes, seconds and milliseconds
+func srtTimestamp(fields []string) Timestamp {
+	var v [4]int
+	for i, f := range fields {
+		v[i], _ = strconv.Atoi(f)
+	}
+	// 1 or 2 digit fractions are tenths or hundredths
+	for n := len(fields[3]); n < 3; n++ {
+		v[3] *= 10
+	}
+	return Timestamp(time.Duration(v[0])*time.Hour + time.Duration(v[1])*time.Minute +
+		time.Duration(v[2])*time.Second + time.Duration(v[3])*time.Millisecond)
+}
+
+// srtText converts the text of a cue to dialogue text
+func srtText(text string) string {
+	return srtTagReg.ReplaceAllStringFunc(EscapeText(text), func(tag string) string {
+		m := srtTagReg.FindStringSubmatch(tag)
+		closing, name := m[1] == "/", strings.ToLower(m[2])
+		if name == "font" {
+			if closing {
+				return `{\c}`
+			}
+			if c := srtColorReg.FindStringSubmatch(m[3]); c != nil {
+				rgb := strings.ToUpper(c[1])
+				return `{\c&H` + rgb[4:6] + rgb[2:4] + rgb[0:2] + `&}`
+			}
+			return ""
+		}
+		if closing {
+			return `{\` + name + `0}`
+		}
+		return `{\` + name + `1}`
+	})
+}
+
+// WriteSRT converts the subtitle to SubRip: the events become cues in the
+// order of their start time, override tags are dropped except italic, bold
+// and underline.
+func (as Subtitle) WriteSRT(w io.Writer) (int64, error) {
+	if err := as.validate(); err != nil {
+		return 0, err
+	}
+	counter := &countingWriter{w: w}
+	writer := bufio.NewWriter(counter)
+	n := 0
+	for _, evt := range sortedEvents(as.Events) {
+		if evt.Comment {
+			continue
+		}
+		start, end, err := evt.times()
+		if err != nil {
+			return counter.n, err
+		}
+		if n > 0 {
+			writer.WriteByte('\n')
+		}
+		n++
+		fmt.Fprintf(writer, "%d\n%s --> %s\n%s\n", n, srtFormat(start), srtFormat(end), markupText(evt.Text, false))
+	}
+	err := writer.Flush()
+	return counter.n, err
+}
+
+// srtFormat formats a timestamp as hh:mm:ss,mmm
+func srtFormat(t Timestamp) string {
+	return strings.Replace(vttTimestamp(t.Duration()), ".", ",", 1)
+}
@@ -0,0 +1,52 @@
+package ass
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSRT(t *testing.T) {
+	input := "\ufeff1\r\n00:00:01,000 --> 00:00:02,500\r\n<i>Hello</i> {world}\r\nsecond line\r\n\r\n" +
+		"2\n00:01:02.5 --> 00:01:04,000 X1:10 X2:20\n<font color=\"#FF8000\">orange</font>\n" +
+		"3\n00:01:05,000 --> 00:01:06,000\nno blank line before\n"
+	sub, err := ParseSRT(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	expect := []*Event{
+		{Start: "0:00:01.00", End: "0:00:02.50", Style: "Default", Text: `{\i1}Hello{\i0} \{world\}\Nsecond line`},
+		{Start: "0:01:02.50", End: "0:01:04.00", Style: "Default", Text: `{\c&H0080FF&}orange{\c}`},
+		{Start: "0:01:05.00", End: "0:01:06.00", Style: "Default", Text: "no blank line before"},
+	}
+	if !reflect.DeepEqual(sub.Events, expect) {
+		t.Errorf("Expect %v, got: %v", expect, sub.Events)
+	}
+	if len(sub.Styles) != 1 || sub.Styles[0].Name != "Default" {
+		t.Errorf("Expect a Default style, got: %v", sub.Styles)
+	}
+
+	if _, err := ParseSRT(strings.NewReader("not a subtitle\n")); err == nil {
+		t.Errorf("Expect error for invalid input")
+	}
+}
+
+func TestWriteSRT(t *testing.T) {
+	sub := Subtitle{Events: []*Event{
+		{Start: "0:00:03.00", End: "1:00:04.00", Text: `{\pos(1,1)}a < b\Nc\hd`},
+		{Start: "0:00:01.00", End: "0:00:02.00", Text: `{\i1}first`},
+	}}
+	var buf strings.Builder
+	if _, err := sub.WriteSRT(&buf); err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	expect := "1\n00:00:01,000 --> 00:00:02,000\n<i>first</i>\n\n2\n00:00:03,000 --> 01:00:04,000\na < b\nc\u00a0d\n"
+	if buf.String() != expect {
+		t.Errorf("Expect %q, got: %q", expect, buf.String())
+	}
+
+	back, err := ParseSRT(strings.NewReader(buf.String()))
+	if err != nil || len(back.Events) != 2 || back.Events[0].Text != `{\i1}first{\i0}` {
+		t.Errorf("Expect the cues parsed back, got: %v, %v", back, err)
+	}
+}
@@ -0,0 +1,142 @@
+package ass
+
+import (
+	"math"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+// gapBounds are the lower bounds of the buckets of the gap histogram
+var gapBounds = []time.Duration{0, 100 * time.Millisecond, 500 * time.Millisecond, time.Second, 2 * time.Second, 5 * time.Second}
+
+// GapBucket counts the gaps between consecutive events in [From, To),
+// To is 0 for the last bucket
+type GapBucket struct {
+	From  time.Duration `json:"from"`
+	To    time.Duration `json:"to"`
+	Count int           `json:"count"`
+}
+
+// Stats are the totals of a subtitle, comments left out
+type Stats struct {
+	Events  int            `json:"events"`
+	ByStyle map[string]int `json:"byStyle"`
+	ByActor map[string]int `json:"byActor"`
+	ByLayer map[int]int    `json:"byLayer"`
+	// Duration is how long some dialogue is shown, overlapping events
+	// counted once. Events with invalid times are left out of it, of the
+	// reading speeds and of the gaps.
+	Duration time.Duration `json:"duration"`
+	// Chars is the number of characters read, see QCProfile.MaxCPS
+	Chars int `json:"chars"`
+	// reading speeds of the events, in characters per second
+	AverageCPS float64 `json:"averageCPS"`
+	MedianCPS  float64 `json:"medianCPS"`
+	P90CPS     float64 `json:"p90CPS"`
+	MaxCPS     float64 `json:"maxCPS"`
+	// LongestLine is the longest displayed line, in characters, and
+	// LongestLineIndex the index of its event, -1 without events
+	LongestLine      string `json:"longestLine"`
+	LongestLineIndex int    `json:"longestLineIndex"`
+	// Overlaps counts the events starting before the previous one ends
+	Overlaps int `json:"overlaps"`
+	// Gaps is the histogram of the gaps between consecutive events
+	Gaps []GapBucket `json:"gaps"`
+}
+
+// Stats computes the totals of the subtitle
+func (as *Subtitle) Stats() Stats {
+	stats := Stats{
+		ByStyle:          make(map[string]int),
+		ByActor:          make(map[string]int),
+		ByLayer:          make(map[int]int),
+		LongestLineIndex: -1,
+	}
+	for i, bound := range gapBounds {
+		bucket := GapBucket{From: bound}
+		if i+1 < len(gapBounds) {
+			bucket.To = gapBounds[i+1]
+		}
+		stats.Gaps = append(stats.Gaps, bucket)
+	}
+
+	type timed struct {
+		start, end Timestamp
+	}
+	var (
+		list    []timed
+		cps     []float64
+		longest int
+	)
+	for i, evt := range as.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		stats.Events++
+		stats.ByStyle[evt.Style]++
+		stats.ByActor[evt.Name]++
+		stats.ByLayer[evt.Layer]++
+		chars := countChars(evt.Text)
+		stats.Chars += chars
+		for _, line := range displayLines(evt.Text) {
+			if n := utf8.RuneCountInString(line); n > longest || stats.LongestLineIndex < 0 {
+				stats.LongestLine, stats.LongestLineIndex, longest = line, i, n
+			}
+		}
+
+		start, end, err := evt.times()
+		if err != nil || end <= start {
+			continue
+		}
+		list = append(list, timed{start, end})
+		cps = append(cps, float64(chars)/time.Duration(end-start).Seconds())
+	}
+
+	if len(cps) > 0 {
+		sort.Float64s(cps)
+		sum := 0.0
+		for _, v := range cps {
+			sum += v
+		}
+		stats.AverageCPS = sum / float64(len(cps))
+		stats.MedianCPS = percentile(cps, 50)
+		stats.P90CPS = percentile(cps, 90)
+		stats.MaxCPS = cps[len(cps)-1]
+	}
+
+	sort.SliceStable(list, func(i, j int) bool { return list[i].start < list[j].start })
+	var shownUntil Timestamp
+	for i, t := range list {
+		switch {
+		case i == 0 || t.start >= shownUntil:
+			stats.Duration += time.Duration(t.end - t.start)
+		case t.end > shownUntil:
+			stats.Duration += time.Duration(t.end - shownUntil)
+		}
+		if i > 0 {
+			gap := time.Duration(t.start - list[i-1].end)
+			if gap < 0 {
+				stats.Overlaps++
+			} else {
+				b := sort.Search(len(gapBounds), func(k int) bool { return gapBounds[k] > gap }) - 1
+				stats.Gaps[b].Count++
+			}
+		}
+		if t.end > shownUntil {
+			shownUntil = t.end
+		}
+	}
+	return stats
+}
+
+// percentile returns the p-th percentile of sorted values, interpolated
+// between the closest ranks
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	if lo+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lo] + (sorted[lo+1]-sorted[lo])*(rank-float64(lo))
+}
@@ -0,0 +1,55 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	sub := &Subtitle{Events: []*Event{
+		{Start: "0:00:01.00", End: "0:00:03.00", Style: "Default", Name: "A", Text: "0123456789"},
+		{Start: "0:00:02.00", End: "0:00:04.00", Style: "Default", Name: "B", Text: `{\i1}abcd{\i0}\Nefgh`},
+		{Start: "0:00:04.05", End: "0:00:05.05", Style: "Sign", Layer: 1, Text: "xy"},
+		{Start: "0:00:08.00", End: "0:00:09.00", Style: "Default", Name: "A", Text: "longest line"},
+		{Start: "bad", End: "0:00:01.00", Style: "Default", Text: "x"},
+		{Start: "0:00:01.00", End: "0:00:20.00", Style: "Notes", Text: "a comment left out", Comment: true},
+	}}
+	stats := sub.Stats()
+
+	if stats.Events != 5 {
+		t.Errorf("Expect 5 events, got: %d", stats.Events)
+	}
+	if expect := map[string]int{"Default": 4, "Sign": 1}; !reflect.DeepEqual(stats.ByStyle, expect) {
+		t.Errorf("Expect by style %v, got: %v", expect, stats.ByStyle)
+	}
+	if expect := map[string]int{"A": 2, "B": 1, "": 2}; !reflect.DeepEqual(stats.ByActor, expect) {
+		t.Errorf("Expect by actor %v, got: %v", expect, stats.ByActor)
+	}
+	if expect := map[int]int{0: 4, 1: 1}; !reflect.DeepEqual(stats.ByLayer, expect) {
+		t.Errorf("Expect by layer %v, got: %v", expect, stats.ByLayer)
+	}
+	if expect := 5 * time.Second; stats.Duration != expect {
+		t.Errorf("Expect duration %v, got: %v", expect, stats.Duration)
+	}
+	if stats.Chars != 33 {
+		t.Errorf("Expect 33 chars, got: %d", stats.Chars)
+	}
+	// 5, 4, 2 and 12 characters per second
+	if stats.AverageCPS != 5.75 || stats.MedianCPS != 4.5 || stats.MaxCPS != 12 {
+		t.Errorf("Expect average 5.75, median 4.5, max 12, got: %v, %v, %v", stats.AverageCPS, stats.MedianCPS, stats.MaxCPS)
+	}
+	if stats.LongestLine != "longest line" || stats.LongestLineIndex != 3 {
+		t.Errorf("Expect longest line of event 3, got: %q of %d", stats.LongestLine, stats.LongestLineIndex)
+	}
+	if stats.Overlaps != 1 {
+		t.Errorf("Expect 1 overlap, got: %d", stats.Overlaps)
+	}
+	counts := make([]int, len(stats.Gaps))
+	for i, bucket := range stats.Gaps {
+		counts[i] = bucket.Count
+	}
+	if expect := []int{1, 0, 0, 0, 1, 0}; !reflect.DeepEqual(counts, expect) {
+		t.Errorf("Expect gaps %v, got: %v", expect, counts)
+	}
+}
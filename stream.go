@@ -0,0 +1,46 @@
+package ass
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// StreamWriter writes a subtitle incrementally: the header once, then the
+// events as they are produced, each one flushed immediately. It suits live
+// pipelines feeding players that tail the file.
+type StreamWriter struct {
+	w   *bufio.Writer
+	buf []byte
+}
+
+// NewStreamWriter writes the header of sub, its styles and script info, to w.
+// The events of sub are ignored, use WriteEvent to stream them.
+func NewStreamWriter(w io.Writer, sub Subtitle) (*StreamWriter, error) {
+	sub.Events = nil
+	if err := sub.validate(); err != nil {
+		return nil, err
+	}
+	sub.fulfill()
+
+	sw := &StreamWriter{w: bufio.NewWriter(w)}
+	if err := writeSection(sw.w, &sub, "header", true); err != nil {
+		return nil, err
+	}
+	return sw, sw.w.Flush()
+}
+
+// WriteEvent validates the event, writes it and flushes
+func (sw *StreamWriter) WriteEvent(evt *Event) error {
+	if evt == nil {
+		return fmt.Errorf("Event cannot be nil")
+	}
+	if err := evt.validate(); err != nil {
+		return err
+	}
+	sw.buf = appendEvent(sw.buf[:0], evt, true)
+	if _, err := sw.w.Write(sw.buf); err != nil {
+		return err
+	}
+	return sw.w.Flush()
+}
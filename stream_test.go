@@ -0,0 +1,43 @@
+package ass
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStreamWriter(t *testing.T) {
+	sub := Subtitle{
+		Styles: []*Style{{Name: "Default"}},
+		Events: []*Event{{Start: "0:00:00.00", End: "0:00:01.00", Text: "first"}},
+	}
+
+	var buf bytes.Buffer
+	sw, err := NewStreamWriter(&buf, sub)
+	if err != nil {
+		t.Fatalf("Expect header written, got: %v", err)
+	}
+	if !strings.HasSuffix(buf.String(), "[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n") {
+		t.Errorf("Expect header flushed, got: %s", buf.String())
+	}
+
+	if err := sw.WriteEvent(sub.Events[0]); err != nil {
+		t.Fatalf("Expect event written, got: %v", err)
+	}
+	if !strings.HasSuffix(buf.String(), "\nDialogue: 0,0:00:00.00,0:00:01.00,,,0000,0000,0000,,first\n") {
+		t.Errorf("Expect event flushed, got: %s", buf.String())
+	}
+
+	if err := sw.WriteEvent(&Event{Start: "bad"}); err == nil {
+		t.Errorf("Expect invalid event error, but passed")
+	}
+
+	// the streamed output is the same as writing the whole subtitle
+	var whole bytes.Buffer
+	if _, err := sub.WriteTo(&whole); err != nil {
+		t.Fatalf("Expect write success, got: %v", err)
+	}
+	if whole.String() != buf.String()+"\n" {
+		t.Errorf("Expect the same output as WriteTo, got:\n%s\nand\n%s", buf.String(), whole.String())
+	}
+}
@@ -0,0 +1,233 @@
+package ass
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// textPart is a piece of dialogue text, either an override block
+// (the content between { and }) or plain text
+type textPart struct {
+	Text     string
+	Override bool
+}
+
+// splitText splits dialogue text into plain text and override blocks.
+// An unclosed { is treated as plain text, the same way renderers do.
+func splitText(text string) []textPart {
+	var parts []textPart
+	for text != "" {
+		open := strings.IndexByte(text, '{')
+		if open < 0 {
+			parts = append(parts, textPart{Text: text})
+			break
+		}
+		end := strings.IndexByte(text[open:], '}')
+		if end < 0 {
+			parts = append(parts, textPart{Text: text})
+			break
+		}
+		if open > 0 {
+			parts = append(parts, textPart{Text: text[:open]})
+		}
+		parts = append(parts, textPart{Text: text[open+1 : open+end], Override: true})
+		text = text[open+end+1:]
+	}
+	return parts
+}
+
+// joinText is the reverse of splitText
+func joinText(parts []textPart) string {
+	var b strings.Builder
+	for _, p := range parts {
+		if p.Override {
+			b.WriteByte('{')
+			b.WriteString(p.Text)
+			b.WriteByte('}')
+			continue
+		}
+		b.WriteString(p.Text)
+	}
+	return b.String()
+}
+
+// splitTags splits the content of an override block into tags, each one
+// starting with a backslash. Backslashes inside parentheses, e.g. the
+// tags animated by \t(...), belong to the enclosing tag.
+func splitTags(block string) []string {
+	var tags []string
+	depth, start := 0, -1
+	for i := 0; i < len(block); i++ {
+		switch block[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case '\\':
+			if depth > 0 {
+				continue
+			}
+			if start >= 0 {
+				tags = append(tags, block[start:i])
+			}
+			start = i
+		}
+	}
+	if start >= 0 {
+		tags = append(tags, block[start:])
+	}
+	return tags
+}
+
+// StripTags removes all override blocks from dialogue text
+func StripTags(text string) string {
+	var b strings.Builder
+	for _, p := range splitText(text) {
+		if !p.Override {
+			b.WriteString(p.Text)
+		}
+	}
+	return b.String()
+}
+
+// drawingTag parses a \p tag, returning the drawing scale level.
+// Level 0 turns the drawing mode off.
+func drawingTag(tag string) (int, bool) {
+	if len(tag) < 3 || tag[:2] != `\p` {
+		return 0, false
+	}
+	n := 0
+	for _, c := range tag[2:] {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, true
+}
+
+// alignTag returns the numpad alignment set by an \an tag or a legacy \a
+// tag, whose values are 1-3 bottom, +4 top and +8 middle
+func alignTag(tag string) (int, bool) {
+	if strings.HasPrefix(tag, `\an`) {
+		n, err := strconv.Atoi(tag[3:])
+		return n, err == nil && n >= 1 && n <= 9
+	}
+	if !strings.HasPrefix(tag, `\a`) {
+		return 0, false
+	}
+	n, err := strconv.Atoi(tag[2:])
+	if err != nil || n <= 0 || n&3 == 0 || n > 11 {
+		return 0, false
+	}
+	column := (n - 1) & 3
+	switch {
+	case n&8 != 0:
+		return 4 + column, true
+	case n&4 != 0:
+		return 7 + column, true
+	}
+	return 1 + column, true
+}
+
+// textLayout returns the numpad alignment of the text, def if it has no
+// alignment tag, and its position, if positioned by \pos or the start of
+// \move
+func textLayout(text string, def int) (align int, x, y float64, positioned bool) {
+	align = def
+	for _, p := range splitText(text) {
+		if !p.Override {
+			continue
+		}
+		for _, tag := range splitTags(p.Text) {
+			switch {
+			case strings.HasPrefix(tag, `\pos(`), strings.HasPrefix(tag, `\move(`):
+				args := strings.Split(strings.TrimSuffix(tag[strings.IndexByte(tag, '(')+1:], ")"), ",")
+				if len(args) < 2 {
+					continue
+				}
+				px, errX := strconv.ParseFloat(strings.TrimSpace(args[0]), 64)
+				py, errY := strconv.ParseFloat(strings.TrimSpace(args[1]), 64)
+				if errX == nil && errY == nil {
+					x, y, positioned = px, py, true
+				}
+			default:
+				if n, ok := alignTag(tag); ok {
+					align = n
+				}
+			}
+		}
+	}
+	return align, x, y, positioned
+}
+
+// The kinds of argument of the override tags
+const (
+	tagNumber = iota
+	tagColor
+	tagName
+	tagParens
+)
+
+// overrideTags are the override tags renderers know, with the kind of
+// their argument, the longest names first where one is a prefix of another
+var overrideTags = []struct {
+	name string
+	kind int
+}{
+	{"xbord", tagNumber}, {"ybord", tagNumber}, {"xshad", tagNumber}, {"yshad", tagNumber},
+	{"alpha", tagColor}, {"iclip", tagParens}, {"clip", tagParens}, {"blur", tagNumber},
+	{"bord", tagNumber}, {"shad", tagNumber}, {"move", tagParens}, {"fade", tagParens},
+	{"fscx", tagNumber}, {"fscy", tagNumber}, {"fsp", tagNumber}, {"fad", tagParens},
+	{"fax", tagNumber}, {"fay", tagNumber}, {"frx", tagNumber}, {"fry", tagNumber},
+	{"frz", tagNumber}, {"pos", tagParens}, {"org", tagParens}, {"pbo", tagNumber},
+	{"1c", tagColor}, {"2c", tagColor}, {"3c", tagColor}, {"4c", tagColor},
+	{"1a", tagColor}, {"2a", tagColor}, {"3a", tagColor}, {"4a", tagColor},
+	{"an", tagNumber}, {"be", tagNumber}, {"fe", tagNumber}, {"fn", tagName},
+	{"fr", tagNumber}, {"fs", tagNumber}, {"kf", tagNumber}, {"ko", tagNumber},
+	{"a", tagNumber}, {"b", tagNumber}, {"c", tagColor}, {"i", tagNumber},
+	{"K", tagNumber}, {"k", tagNumber}, {"p", tagNumber}, {"q", tagNumber},
+	{"r", tagName}, {"s", tagNumber}, {"t", tagParens}, {"u", tagNumber},
+}
+
+var (
+	tagNumberReg = regexp.MustCompile(`^-?(\d+(\.\d*)?|\.\d+)$`)
+	tagColorReg  = regexp.MustCompile(`^&?H?[0-9A-Fa-f]{1,8}&?$`)
+)
+
+// checkTag returns an error if a tag, as returned by splitTags, is unknown
+// or has an invalid argument. An empty argument, which resets the tag to
+// the style, is valid.
+func checkTag(tag string) error {
+	for _, t := range overrideTags {
+		if !strings.HasPrefix(tag[1:], t.name) {
+			continue
+		}
+		arg := strings.TrimSpace(tag[1+len(t.name):])
+		valid := arg == ""
+		switch t.kind {
+		case tagNumber:
+			if arg != "" && unicode.IsLetter(rune(arg[0])) {
+				// another name, e.g. \bogus is not \b with ogus
+				return fmt.Errorf("Unknown tag: %s", tag)
+			}
+			valid = valid || tagNumberReg.MatchString(arg)
+		case tagColor:
+			valid = valid || tagColorReg.MatchString(arg)
+		case tagName:
+			valid = true
+		case tagParens:
+			valid = strings.HasPrefix(arg, "(") && strings.HasSuffix(arg, ")")
+		}
+		if !valid {
+			return fmt.Errorf("Invalid tag argument: %s", tag)
+		}
+		return nil
+	}
+	return fmt.Errorf("Unknown tag: %s", tag)
+}
@@ -0,0 +1,53 @@
+package ass
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// String returns the event as a line of the Events section, without line break
+func (evt Event) String() string {
+	return strings.TrimSuffix(string(appendEvent(nil, &evt, true)), "\n")
+}
+
+// MarshalText returns the event as a line of the Events section
+func (evt Event) MarshalText() ([]byte, error) {
+	return []byte(evt.String()), nil
+}
+
+// MarshalJSON keeps the JSON object form, which MarshalText would replace
+func (evt Event) MarshalJSON() ([]byte, error) {
+	type event Event
+	return json.Marshal(event(evt))
+}
+
+// String returns the style as a line of the Styles section, without line break
+func (style Style) String() string {
+	return strings.TrimSuffix(string(appendStyle(nil, &style)), "\n")
+}
+
+// MarshalText returns the style as a line of the Styles section
+func (style Style) MarshalText() ([]byte, error) {
+	return []byte(style.String()), nil
+}
+
+// MarshalJSON keeps the JSON object form, which MarshalText would replace
+func (style Style) MarshalJSON() ([]byte, error) {
+	type plainStyle Style
+	return json.Marshal(plainStyle(style))
+}
+
+// MarshalText returns the timestamp formatted as h:mm:ss.cc
+func (t Timestamp) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText parses a timestamp formatted as h:mm:ss.cc
+func (t *Timestamp) UnmarshalText(text []byte) error {
+	ts, err := ParseTimestamp(string(text))
+	if err != nil {
+		return err
+	}
+	*t = ts
+	return nil
+}
@@ -0,0 +1,50 @@
+package ass
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestStringers(t *testing.T) {
+	evt := Event{Layer: 1, Start: "0:00:01.00", End: "0:00:02.00", Style: "Default", MarginL: 10, Text: "a\nb"}
+	cases := []struct {
+		input  fmt.Stringer
+		expect string
+	}{
+		{evt, `Dialogue: 1,0:00:01.00,0:00:02.00,Default,,0010,0000,0000,,a\Nb`},
+		{Style{Name: "Default", FontName: "Arial", FontSize: 20, PrimaryColor: "00FFFFFF", SecondColor: "00000000", OutlineColor: "00000000", BackColor: "00000000"},
+			"Style: Default,Arial,20,&H00FFFFFF,&H00000000,&H00000000,&H00000000,1,0,0,0,100,100,0,0,1,2,0,2,20,20,2,0"},
+		{Timestamp(3723450e6), "1:02:03.45"},
+	}
+
+	for _, c := range cases {
+		if got := c.input.String(); got != c.expect {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+		text, err := c.input.(interface{ MarshalText() ([]byte, error) }).MarshalText()
+		if err != nil || string(text) != c.expect {
+			t.Errorf("Expect marshaled %q, got: %q %v", c.expect, text, err)
+		}
+	}
+}
+
+func TestJSONShape(t *testing.T) {
+	sub := Subtitle{Events: []*Event{{Start: "0:00:01.00", Text: "hi"}}, Styles: []*Style{{Name: "Default"}}}
+	data, err := json.Marshal(sub)
+	if err != nil {
+		t.Fatalf("Expect marshal success, got: %v", err)
+	}
+	var decoded Subtitle
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expect events and styles kept as JSON objects, got: %v", err)
+	}
+	if decoded.Events[0].Text != "hi" || decoded.Styles[0].Name != "Default" {
+		t.Errorf("Unexpected decoded subtitle: %s", data)
+	}
+
+	var w Word
+	if err := json.Unmarshal([]byte(`{"text":"hi","start":"0:00:01.50","end":"0:00:02.00"}`), &w); err != nil || w.Start != Timestamp(1500e6) {
+		t.Errorf("Expect timestamps decoded from text, got: %+v %v", w, err)
+	}
+}
@@ -0,0 +1,131 @@
+package ass
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Theme is a house style kept outside of code: styles plus the default
+// resolution and margins. Its fields have the same names as in the JSON
+// form of Subtitle, Style and Event, in any of the theme formats. The
+// colors are quoted in YAML, 00000000 would be a number otherwise.
+//
+//	playResX: 1920
+//	playResY: 1080
+//	marginV: 40
+//	styles:
+//	  - name: Default
+//	    font: Arial
+//	    fontSize: 64
+//	    primaryColor: "00FFFFFF"
+type Theme struct {
+	PlayResX uint     `json:"playResX"`
+	PlayResY uint     `json:"playResY"`
+	MarginL  uint     `json:"marginLeft"`
+	MarginR  uint     `json:"marginRight"`
+	MarginV  uint     `json:"marginV"`
+	Styles   []*Style `json:"styles"`
+}
+
+// LoadThemeYAML reads a YAML theme
+func LoadThemeYAML(r io.Reader) (*Theme, error) {
+	var v map[string]interface{}
+	if err := yaml.NewDecoder(r).Decode(&v); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("Invalid YAML theme: %v", err)
+	}
+	return newTheme(v)
+}
+
+// LoadThemeTOML reads a TOML theme, the styles being an array of tables:
+//
+//	playResY = 1080
+//	[[styles]]
+//	name = "Default"
+//	fontSize = 64
+func LoadThemeTOML(r io.Reader) (*Theme, error) {
+	var v map[string]interface{}
+	if _, err := toml.NewDecoder(r).Decode(&v); err != nil {
+		return nil, fmt.Errorf("Invalid TOML theme: %v", err)
+	}
+	return newTheme(v)
+}
+
+// LoadTheme reads a theme file, YAML or TOML by its extension
+func LoadTheme(path string) (*Theme, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return LoadThemeYAML(bytes.NewReader(data))
+	case ".toml":
+		return LoadThemeTOML(bytes.NewReader(data))
+	}
+	return nil, &os.PathError{Op: "load theme", Path: path, Err: fmt.Errorf("Unsupported theme format")}
+}
+
+// newTheme decodes a theme from its generic form through JSON, so that the
+// field names are the JSON ones whatever the format, and validates it
+func newTheme(v map[string]interface{}) (*Theme, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid theme: %v", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	theme := &Theme{}
+	if err := dec.Decode(theme); err != nil {
+		return nil, fmt.Errorf("Invalid theme: %v", err)
+	}
+	for i, style := range theme.Styles {
+		if style == nil {
+			return nil, fmt.Errorf("Invalid theme: style %d is empty", i)
+		}
+		if err := style.validate(); err != nil {
+			return nil, fmt.Errorf("Invalid theme style %s: %v", style.Name, err)
+		}
+	}
+	return theme, nil
+}
+
+// ApplyTheme applies a theme to the subtitle: its styles replace the styles
+// of the same name or are added, its resolution, if set, replaces the
+// script resolution without resampling, and its margins are set on the
+// events without margins of their own.
+func (as *Subtitle) ApplyTheme(theme *Theme) {
+	for _, style := range theme.Styles {
+		s := *style
+		if old := as.styleByName(s.Name); old != nil {
+			*old = s
+		} else {
+			as.Styles = append(as.Styles, &s)
+		}
+	}
+	if theme.PlayResX != 0 || theme.PlayResY != 0 {
+		as.PlayerWidth, as.PlayerHeight = theme.PlayResX, theme.PlayResY
+	}
+	for _, evt := range as.Events {
+		if evt == nil {
+			continue
+		}
+		if evt.MarginL == 0 {
+			evt.MarginL = theme.MarginL
+		}
+		if evt.MarginR == 0 {
+			evt.MarginR = theme.MarginR
+		}
+		if evt.MarginV == 0 {
+			evt.MarginV = theme.MarginV
+		}
+	}
+}
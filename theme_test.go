@@ -0,0 +1,124 @@
+package ass
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const yamlTheme = `playResX: 1920
+playResY: 1080
+marginV: 40
+styles:
+  - name: Default
+    font: Arial
+    fontSize: 64
+    primaryColor: "00FFFFFF"
+  - name: Signs
+    font: Verdana
+    fontSize: 48
+    bold: -1
+`
+
+const tomlTheme = `playResX = 1920
+playResY = 1080
+marginV = 40
+
+[[styles]]
+name = "Default"
+font = "Arial"
+fontSize = 64
+primaryColor = "00FFFFFF"
+
+[[styles]]
+name = "Signs"
+font = "Verdana"
+fontSize = 48
+bold = -1
+`
+
+func TestLoadTheme(t *testing.T) {
+	expect := &Theme{
+		PlayResX: 1920,
+		PlayResY: 1080,
+		MarginV:  40,
+		Styles: []*Style{
+			{Name: "Default", FontName: "Arial", FontSize: 64, PrimaryColor: "00FFFFFF"},
+			{Name: "Signs", FontName: "Verdana", FontSize: 48, Bold: -1},
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "theme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for name, content := range map[string]string{"theme.yaml": yamlTheme, "theme.toml": tomlTheme} {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		theme, err := LoadTheme(path)
+		if err != nil {
+			t.Fatalf("%s: expect theme loaded, got: %v", name, err)
+		}
+		if !reflect.DeepEqual(theme, expect) {
+			t.Errorf("%s: expect %+v, got: %+v", name, expect, theme)
+		}
+	}
+	if _, err := LoadTheme(filepath.Join(dir, "theme.json")); err == nil {
+		t.Errorf("Expect error for a missing theme, but passed")
+	}
+
+	invalid := []string{
+		"styles:\n  - name: Default\n    fontColor: red\n",
+		"styles:\n  - name: Default\n    primaryColor: red\n",
+		"styles: [",
+	}
+	for _, c := range invalid {
+		if _, err := LoadThemeYAML(strings.NewReader(c)); err == nil {
+			t.Errorf("Expect error for %q, but passed", c)
+		}
+	}
+	if _, err := LoadThemeTOML(strings.NewReader("styles = 1\n")); err == nil {
+		t.Errorf("Expect error for invalid styles, but passed")
+	}
+}
+
+func TestApplyTheme(t *testing.T) {
+	theme, err := LoadThemeYAML(strings.NewReader(yamlTheme))
+	if err != nil {
+		t.Fatalf("Expect theme loaded, got: %v", err)
+	}
+	sub := &Subtitle{
+		PlayerWidth:  640,
+		PlayerHeight: 480,
+		Styles:       []*Style{{Name: "Default", FontName: "Times", FontSize: 20}, {Name: "Notes", FontSize: 10}},
+		Events:       []*Event{{Text: "a"}, {MarginV: 5, Text: "b"}, nil},
+	}
+	sub.ApplyTheme(theme)
+
+	if sub.PlayerWidth != 1920 || sub.PlayerHeight != 1080 {
+		t.Errorf("Expect the theme resolution, got: %dx%d", sub.PlayerWidth, sub.PlayerHeight)
+	}
+	var names []string
+	for _, style := range sub.Styles {
+		names = append(names, style.Name)
+	}
+	if expect := []string{"Default", "Notes", "Signs"}; !reflect.DeepEqual(names, expect) {
+		t.Errorf("Expect styles %v, got: %v", expect, names)
+	}
+	if sub.Styles[0].FontName != "Arial" || sub.Styles[0].FontSize != 64 {
+		t.Errorf("Expect Default replaced, got: %+v", sub.Styles[0])
+	}
+	if sub.Events[0].MarginV != 40 || sub.Events[1].MarginV != 5 {
+		t.Errorf("Expect margins 40 and 5, got: %d and %d", sub.Events[0].MarginV, sub.Events[1].MarginV)
+	}
+	theme.Styles[0].FontSize = 1
+	if sub.Styles[0].FontSize != 64 {
+		t.Errorf("Expect the styles copied from the theme")
+	}
+}
@@ -0,0 +1,203 @@
+package ass
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimelineLanes is how the events are grouped into the lanes of a timeline
+type TimelineLanes int
+
+// The timeline lanes
+const (
+	// LanesByStyle puts the events of each style in a lane, in order of
+	// first appearance
+	LanesByStyle TimelineLanes = iota
+	// LanesByLayer puts the events of each layer in a lane, lowest first
+	LanesByLayer
+)
+
+// TimelineOptions configures the timeline exports
+type TimelineOptions struct {
+	Lanes TimelineLanes
+	// Width is the width of the timeline, in pixels for SVG, 1200 by
+	// default, and in characters for text, 100 by default, labels aside
+	Width int
+	// Duration is the time covered by the timeline, the end of the last
+	// event by default
+	Duration time.Duration
+}
+
+// timelineEvent is an event placed in a lane
+type timelineEvent struct {
+	index      int
+	start, end time.Duration
+	overlap    bool
+}
+
+// timelineLane is a row of a timeline
+type timelineLane struct {
+	label  string
+	events []timelineEvent
+}
+
+// timeline returns the lanes of the events but comments, the events
+// overlapping another event of the lane flagged, and the duration covered
+func (as *Subtitle) timeline(opts TimelineOptions) ([]*timelineLane, time.Duration) {
+	var lanes []*timelineLane
+	byKey := make(map[string]*timelineLane)
+	layers := make(map[*timelineLane]int)
+	duration := opts.Duration
+	for i, evt := range as.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		start, end, err := evt.times()
+		if err != nil {
+			continue
+		}
+		label := evt.Style
+		if opts.Lanes == LanesByLayer {
+			label = "Layer " + strconv.Itoa(evt.Layer)
+		}
+		lane := byKey[label]
+		if lane == nil {
+			lane = &timelineLane{label: label}
+			byKey[label] = lane
+			layers[lane] = evt.Layer
+			lanes = append(lanes, lane)
+		}
+		lane.events = append(lane.events, timelineEvent{index: i, start: start.Duration(), end: end.Duration()})
+		if opts.Duration <= 0 && end.Duration() > duration {
+			duration = end.Duration()
+		}
+	}
+	if opts.Lanes == LanesByLayer {
+		sort.SliceStable(lanes, func(i, j int) bool { return layers[lanes[i]] < layers[lanes[j]] })
+	}
+
+	for _, lane := range lanes {
+		events := lane.events
+		sort.SliceStable(events, func(i, j int) bool { return events[i].start < events[j].start })
+		// the latest end so far, and the event ending then
+		var end time.Duration
+		last := -1
+		for i := range events {
+			if last >= 0 && events[i].start < end {
+				events[i].overlap = true
+				events[last].overlap = true
+			}
+			if events[i].end > end {
+				end, last = events[i].end, i
+			}
+		}
+	}
+	return lanes, duration
+}
+
+// Colors of the SVG timeline
+const (
+	timelineEventColor   = "#4682B4"
+	timelineOverlapColor = "#DC143C"
+)
+
+// WriteTimelineSVG draws the timeline of the events as an SVG image: a lane
+// per style or layer, the events as bars, in red where they overlap another
+// event of the lane, and a mark every minute. The bars show the index and
+// text of their event on hover.
+func (as *Subtitle) WriteTimelineSVG(w io.Writer, opts TimelineOptions) error {
+	if opts.Width <= 0 {
+		opts.Width = 1200
+	}
+	lanes, duration := as.timeline(opts)
+	const labelWidth, laneHeight, top = 120, 20, 20
+	height := top + len(lanes)*laneHeight
+	scale := 0.0
+	if duration > 0 {
+		scale = float64(opts.Width) / duration.Seconds()
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="12">`+"\n",
+		labelWidth+opts.Width, height)
+	for t := time.Duration(0); t <= duration; t += time.Minute {
+		x := labelWidth + t.Seconds()*scale
+		fmt.Fprintf(b, `<line x1="%s" y1="%d" x2="%s" y2="%d" stroke="#CCC"/>`+"\n", formatNumber(x), top, formatNumber(x), height)
+		fmt.Fprintf(b, `<text x="%s" y="%d">%s</text>`+"\n", formatNumber(x), top-6, Timestamp(t).String())
+	}
+	for i, lane := range lanes {
+		y := top + i*laneHeight
+		fmt.Fprintf(b, `<text x="4" y="%d">%s</text>`+"\n", y+laneHeight-6, html.EscapeString(lane.label))
+		for _, e := range lane.events {
+			color := timelineEventColor
+			if e.overlap {
+				color = timelineOverlapColor
+			}
+			width := (e.end - e.start).Seconds() * scale
+			fmt.Fprintf(b, `<rect x="%s" y="%d" width="%s" height="%d" fill="%s"><title>%d: %s</title></rect>`+"\n",
+				formatNumber(labelWidth+e.start.Seconds()*scale), y+2, formatNumber(width), laneHeight-4, color,
+				e.index, html.EscapeString(displayText(as.Events[e.index].Text)))
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.Flush()
+}
+
+// WriteTimelineText draws the timeline of the events as a plain text Gantt
+// chart, a line per style or layer: = where an event is shown, # where
+// events overlap and . elsewhere, each character covering the same time.
+func (as *Subtitle) WriteTimelineText(w io.Writer, opts TimelineOptions) error {
+	if opts.Width <= 0 {
+		opts.Width = 100
+	}
+	lanes, duration := as.timeline(opts)
+	labelWidth := 0
+	for _, lane := range lanes {
+		if n := len([]rune(lane.label)); n > labelWidth {
+			labelWidth = n
+		}
+	}
+
+	b := bufio.NewWriter(w)
+	start, end := Timestamp(0).String(), Timestamp(duration).String()
+	pad := opts.Width - len(start)
+	if pad <= len(end) {
+		pad = len(end) + 1
+	}
+	fmt.Fprintf(b, "%*s %s%*s\n", labelWidth, "", start, pad, end)
+	for _, lane := range lanes {
+		counts := make([]int, opts.Width)
+		for _, e := range lane.events {
+			if duration <= 0 {
+				continue
+			}
+			from := int(int64(e.start) * int64(opts.Width) / int64(duration))
+			to := int(int64(e.end) * int64(opts.Width) / int64(duration))
+			if to <= from {
+				to = from + 1
+			}
+			for c := from; c < to && c < opts.Width; c++ {
+				counts[c]++
+			}
+		}
+		row := make([]byte, opts.Width)
+		for c, n := range counts {
+			switch {
+			case n == 0:
+				row[c] = '.'
+			case n == 1:
+				row[c] = '='
+			default:
+				row[c] = '#'
+			}
+		}
+		fmt.Fprintf(b, "%s%s %s\n", lane.label, strings.Repeat(" ", labelWidth-len([]rune(lane.label))), row)
+	}
+	return b.Flush()
+}
@@ -0,0 +1,66 @@
+package ass
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTimelineSubtitle() *Subtitle {
+	return &Subtitle{Events: []*Event{
+		{Start: "0:00:00.00", End: "0:00:02.00", Style: "Default", Text: "one"},
+		{Start: "0:00:01.00", End: "0:00:03.00", Style: "Default", Text: "two & <three>"},
+		{Start: "0:00:05.00", End: "0:00:10.00", Style: "Sign", Layer: 1, Text: "{\\pos(1,1)}sign"},
+		{Start: "0:00:06.00", End: "0:00:08.00", Style: "Default", Text: "four"},
+		{Start: "0:00:00.00", End: "0:00:10.00", Style: "Default", Text: "note", Comment: true},
+		nil,
+	}}
+}
+
+func TestWriteTimelineText(t *testing.T) {
+	sub := newTimelineSubtitle()
+	var buf bytes.Buffer
+	if err := sub.WriteTimelineText(&buf, TimelineOptions{Width: 20}); err != nil {
+		t.Fatal(err)
+	}
+	expect := "        0:00:00.00 0:00:10.00\n" +
+		"Default ==##==......====....\n" +
+		"Sign    ..........==========\n"
+	if buf.String() != expect {
+		t.Errorf("Expect:\n%s\ngot:\n%s", expect, buf.String())
+	}
+
+	buf.Reset()
+	if err := sub.WriteTimelineText(&buf, TimelineOptions{Lanes: LanesByLayer, Width: 20, Duration: 20 * time.Second}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) != 4 || lines[1] != "Layer 0 =#=...==............" || lines[2] != "Layer 1 .....=====.........." {
+		t.Errorf("Expect the lanes by layer, got:\n%s", buf.String())
+	}
+}
+
+func TestWriteTimelineSVG(t *testing.T) {
+	sub := newTimelineSubtitle()
+	var buf bytes.Buffer
+	if err := sub.WriteTimelineSVG(&buf, TimelineOptions{Width: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	for _, s := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="1120" height="60"`,
+		`<rect x="120" y="22" width="200" height="16" fill="#DC143C"><title>0: one</title></rect>`,
+		`<rect x="220" y="22" width="200" height="16" fill="#DC143C"><title>1: two &amp; &lt;three&gt;</title></rect>`,
+		`<rect x="720" y="22" width="200" height="16" fill="#4682B4"><title>3: four</title></rect>`,
+		`<rect x="620" y="42" width="500" height="16" fill="#4682B4"><title>2: sign</title></rect>`,
+		`<text x="4" y="54">Sign</text>`,
+	} {
+		if !strings.Contains(svg, s) {
+			t.Errorf("Expect %q in:\n%s", s, svg)
+		}
+	}
+	if strings.Contains(svg, "note") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Errorf("Expect comments left out, got:\n%s", svg)
+	}
+}
@@ -0,0 +1,128 @@
+package ass
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Timestamp is a point of time in a subtitle, with centisecond precision
+// when it is written
+type Timestamp time.Duration
+
+// maxHours is the most hours a timestamp holds, with a bit left for the
+// minutes and seconds
+const maxHours = (math.MaxInt64 - int64(time.Hour)) / int64(time.Hour)
+
+// ParseTimestamp parses a timestamp like h:mm:ss.cc, h:mm:ss:cc is accepted
+// too. The minutes and seconds must be 2 digits from 00 to 59.
+func ParseTimestamp(s string) (Timestamp, error) {
+	t, ok := parseTimestamp(s)
+	if !ok {
+		return 0, fmt.Errorf("Invalid timestamp: %s", s)
+	}
+	return t, nil
+}
+
+// parseTimestamp is ParseTimestamp without allocating
+func parseTimestamp(s string) (Timestamp, bool) {
+	var h int64
+	i := 0
+	for ; i < len(s) && '0' <= s[i] && s[i] <= '9'; i++ {
+		if h = h*10 + int64(s[i]-'0'); h > maxHours {
+			return 0, false
+		}
+	}
+	if i == 0 || len(s)-i != 9 || s[i] != ':' || s[i+3] != ':' || (s[i+6] != '.' && s[i+6] != ':') {
+		return 0, false
+	}
+	m, okM := twoDigits(s[i+1:])
+	sec, okS := twoDigits(s[i+4:])
+	cs, okC := twoDigits(s[i+7:])
+	if !okM || !okS || !okC || m > 59 || sec > 59 {
+		return 0, false
+	}
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(cs)*10*time.Millisecond
+	return Timestamp(d), true
+}
+
+// appendTwoDigits appends v, from 0 to 99, as 2 digits
+func appendTwoDigits(b []byte, v int64) []byte {
+	return append(b, byte('0'+v/10), byte('0'+v%10))
+}
+
+// twoDigits parses the 2 digits at the start of s
+func twoDigits(s string) (int, bool) {
+	if s[0] < '0' || s[0] > '9' || s[1] < '0' || s[1] > '9' {
+		return 0, false
+	}
+	return int(s[0]-'0')*10 + int(s[1]-'0'), true
+}
+
+// Duration converts the timestamp to a time.Duration
+func (t Timestamp) Duration() time.Duration {
+	return time.Duration(t)
+}
+
+// String formats the timestamp as h:mm:ss.cc, negative values are clamped to 0
+func (t Timestamp) String() string {
+	if t < 0 {
+		t = 0
+	}
+	var buf [32]byte
+	return string(t.appendTo(buf[:0]))
+}
+
+// appendTo appends the timestamp formatted as by String to b
+func (t Timestamp) appendTo(b []byte) []byte {
+	if t < 0 {
+		t = 0
+	}
+	cs := int64(time.Duration(t) / (10 * time.Millisecond))
+	b = strconv.AppendInt(b, cs/360000, 10)
+	b = appendTwoDigits(append(b, ':'), cs/6000%60)
+	b = appendTwoDigits(append(b, ':'), cs/100%60)
+	b = appendTwoDigits(append(b, '.'), cs%100)
+	return b
+}
+
+// StartTime parses the start time of the event
+func (evt Event) StartTime() (Timestamp, error) {
+	return ParseTimestamp(evt.Start)
+}
+
+// EndTime parses the end time of the event
+func (evt Event) EndTime() (Timestamp, error) {
+	return ParseTimestamp(evt.End)
+}
+
+// NewEvent creates an event of style starting at start and lasting dur
+func NewEvent(start Timestamp, dur time.Duration, style, text string) *Event {
+	return &Event{
+		Start: start.String(),
+		End:   (start + Timestamp(dur)).String(),
+		Style: style,
+		Text:  text,
+	}
+}
+
+// WithDuration sets the end time of the event to its start time plus d
+func (evt *Event) WithDuration(d time.Duration) error {
+	start, err := evt.StartTime()
+	if err != nil {
+		return err
+	}
+	evt.End = (start + Timestamp(d)).String()
+	return nil
+}
+
+// times parses both the start and end time of the event
+func (evt Event) times() (start, end Timestamp, err error) {
+	if start, err = evt.StartTime(); err != nil {
+		return
+	}
+	end, err = evt.EndTime()
+	return
+}
@@ -0,0 +1,113 @@
+package ass
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	cases := []struct {
+		input  string
+		output string
+		valid  bool
+	}{
+		{"0:00:01.50", "0:00:01.50", true},
+		{"1:02:03:04", "1:02:03.04", true},
+		{"10:59:59.99", "10:59:59.99", true},
+		{"0:60:00.00", "", false},
+		{"0:00:60.00", "", false},
+		{"0:00:01.5", "", false},
+		{"0:00:01", "", false},
+		{"-1:00:01.00", "", false},
+		{"+1:00:01.00", "", false},
+		{"00:00:01.00", "0:00:01.00", true},
+		{"123:45:06.70", "123:45:06.70", true},
+		{"0:5:00.00", "", false},
+		{"0:00:01.000", "", false},
+		{"0:00:01,00", "", false},
+		{"0:0a:01.00", "", false},
+		{"99999999999:00:00.00", "", false},
+		{"", "", false},
+	}
+
+	for _, c := range cases {
+		ts, err := ParseTimestamp(c.input)
+		if c.valid && err != nil {
+			t.Errorf("Expect parse %s success, got: %v", c.input, err)
+			continue
+		}
+		if !c.valid {
+			if err == nil {
+				t.Errorf("Expect invalid timestamp %s, but passed", c.input)
+			}
+			continue
+		}
+		if ts.String() != c.output {
+			t.Errorf("Expect %s, got: %s", c.output, ts)
+		}
+	}
+}
+
+func TestTimestampString(t *testing.T) {
+	cases := []struct {
+		input  time.Duration
+		output string
+	}{
+		{0, "0:00:00.00"},
+		{-time.Second, "0:00:00.00"},
+		{1234567 * time.Millisecond, "0:20:34.56"},
+		{100*time.Hour + 59*time.Minute + 9*time.Second, "100:59:09.00"},
+	}
+	for _, c := range cases {
+		if s := Timestamp(c.input).String(); s != c.output {
+			t.Errorf("Expect %s, got: %s", c.output, s)
+		}
+	}
+}
+
+var benchTimestamps = []string{"0:00:01.50", "1:02:03:04", "10:59:59.99", "0:60:00.00", "bad"}
+
+func BenchmarkParseTimestamp(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, s := range benchTimestamps {
+			parseTimestamp(s)
+		}
+	}
+}
+
+// BenchmarkTimestampRegexp is the regexp formerly used by the validation
+func BenchmarkTimestampRegexp(b *testing.B) {
+	timeReg := regexp.MustCompile(`\d:[0-6]\d:[0-6]\d[:.]\d\d`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, s := range benchTimestamps {
+			timeReg.MatchString(s)
+		}
+	}
+}
+
+func BenchmarkTimestampString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Timestamp(i * 1e7).String()
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	evt := NewEvent(Timestamp(90*time.Second), 2500*time.Millisecond, "Default", "hi")
+	expect := Event{Start: "0:01:30.00", End: "0:01:32.50", Style: "Default", Text: "hi"}
+	if !reflect.DeepEqual(*evt, expect) {
+		t.Errorf("Expect %v, got: %v", expect, evt)
+	}
+
+	if err := evt.WithDuration(time.Hour); err != nil || evt.End != "1:01:30.00" {
+		t.Errorf("Expect end 1:01:30.00, got: %s, %v", evt.End, err)
+	}
+	evt.Start = "bad"
+	if err := evt.WithDuration(time.Second); err == nil || evt.End != "1:01:30.00" {
+		t.Errorf("Expect error and end unchanged, got: %s, %v", evt.End, err)
+	}
+}
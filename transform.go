@@ -0,0 +1,105 @@
+package ass
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Transform is a 3D rotation and shear of the text, as set by the tags
+// \frx, \fry, \frz, \fax and \fay
+type Transform struct {
+	// RotX, RotY and RotZ are the rotations around the axes, in degrees
+	RotX, RotY, RotZ float64
+	// ShearX and ShearY are the shear factors
+	ShearX, ShearY float64
+}
+
+// Tags returns the tags of the transform, without the zero ones
+func (t Transform) Tags() string {
+	var b strings.Builder
+	for _, tag := range []struct {
+		name  string
+		value float64
+	}{{`\frx`, t.RotX}, {`\fry`, t.RotY}, {`\frz`, t.RotZ}, {`\fax`, t.ShearX}, {`\fay`, t.ShearY}} {
+		if formatNumber(tag.value) != "0" {
+			b.WriteString(tag.name + formatNumber(tag.value))
+		}
+	}
+	return b.String()
+}
+
+// TextCenter returns the center of the text of a positioned event, with its
+// size measured by m, ApproxMeasurer if nil. The events without \pos or
+// \move are placed by the renderer, ok is false for them.
+func (as *Subtitle) TextCenter(evt *Event, m TextMeasurer) (x, y float64, ok bool) {
+	box, positioned := as.textBox(evt, m)
+	if !positioned {
+		return 0, 0, false
+	}
+	return box.X + box.Width/2, box.Y + box.Height/2, true
+}
+
+// eventLayout is textLayout with the alignment of the style of the event by
+// default
+func (as *Subtitle) eventLayout(evt *Event) (align int, x, y float64, positioned bool) {
+	return textLayout(evt.Text, as.styleByName(evt.Style).align())
+}
+
+// textBox returns the box of the text of the event, at its position or
+// within the margins of the event and its style if not positioned,
+// collisions and wrapping aside
+func (as *Subtitle) textBox(evt *Event, m TextMeasurer) (box Rect, positioned bool) {
+	if m == nil {
+		m = ApproxMeasurer{}
+	}
+	style := as.styleByName(evt.Style)
+	align, x, y, positioned := textLayout(evt.Text, style.align())
+	box.Width, box.Height = MeasureText(m, evt.Text, style)
+	column, band := (align-1)%3, (align-1)/3
+	if !positioned {
+		w, h := as.PlayRes()
+		l, r, v := evt.margins(style)
+		left, right := float64(l), float64(w)-float64(r)
+		x = [3]float64{left, (left + right) / 2, right}[column]
+		y = [3]float64{float64(h) - float64(v), float64(h) / 2, float64(v)}[band]
+	}
+	box.X = x - [3]float64{0, box.Width / 2, box.Width}[column]
+	box.Y = y - [3]float64{box.Height, box.Height / 2, 0}[band]
+	return box, positioned
+}
+
+// anchorPoint returns the point of the box the text is positioned by, for a
+// numpad alignment
+func anchorPoint(box Rect, align int) (x, y float64) {
+	return box.X + [3]float64{0, box.Width / 2, box.Width}[(align-1)%3],
+		box.Y + [3]float64{box.Height, box.Height / 2, 0}[(align-1)/3]
+}
+
+// Transform sets the rotation and shear of a positioned event, replacing
+// its own, with the rotation origin \org at the center of its text so that
+// it turns in place instead of around its anchor point. The size of the
+// text is measured by m, ApproxMeasurer if nil.
+func (as *Subtitle) Transform(evt *Event, t Transform, m TextMeasurer) error {
+	x, y, ok := as.TextCenter(evt, m)
+	if !ok {
+		return fmt.Errorf("Event not positioned with \\pos or \\move")
+	}
+	text := removeTags(evt.Text, isTransformTag)
+	evt.Text = fmt.Sprintf(`{\org(%s,%s)%s}`, formatNumber(x), formatNumber(y), t.Tags()) + text
+	return nil
+}
+
+// isTransformTag reports whether the tag is replaced by Transform: \org,
+// \frx, \fry, \frz, \fr, \fax and \fay
+func isTransformTag(tag string) bool {
+	switch {
+	case strings.HasPrefix(tag, `\org(`):
+		return true
+	case strings.HasPrefix(tag, `\frx`), strings.HasPrefix(tag, `\fry`), strings.HasPrefix(tag, `\frz`),
+		strings.HasPrefix(tag, `\fax`), strings.HasPrefix(tag, `\fay`):
+		return true
+	case strings.HasPrefix(tag, `\fr`):
+		return len(tag) == 3 || strings.ContainsAny(tag[3:4], "-.0123456789")
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package ass
+
+import "testing"
+
+func TestTransformTags(t *testing.T) {
+	cases := []struct {
+		t      Transform
+		expect string
+	}{
+		{Transform{}, ""},
+		{Transform{RotX: 10, RotY: -20.5}, `\frx10\fry-20.5`},
+		{Transform{RotZ: 0.0001, ShearX: 0.2, ShearY: -0.1}, `\fax0.2\fay-0.1`},
+	}
+	for _, c := range cases {
+		if tags := c.t.Tags(); tags != c.expect {
+			t.Errorf("Expect %q, got: %q", c.expect, tags)
+		}
+	}
+}
+
+func TestSubtitleTransform(t *testing.T) {
+	fixed := TextMeasurerFunc(func(text string, style *Style) (float64, float64) {
+		return float64(10 * len(text)), 20
+	})
+	sub := &Subtitle{}
+	cases := []struct {
+		text   string
+		expect string
+	}{
+		{`{\pos(100,100)\frz5\fad(100,100)}Exit`, `{\org(100,90)\fry30}{\pos(100,100)\fad(100,100)}Exit`},
+		{`{\an7\move(0,0,50,50)\fr10\fax1}Exit`, `{\org(20,10)\fry30}{\an7\move(0,0,50,50)}Exit`},
+		{`{\an6\pos(100,100)\org(1,1)}Ex\Nit`, `{\org(90,100)\fry30}{\an6\pos(100,100)}Ex\Nit`},
+	}
+	for _, c := range cases {
+		evt := &Event{Text: c.text}
+		if err := sub.Transform(evt, Transform{RotY: 30}, fixed); err != nil {
+			t.Fatalf("Expect no error, got: %v", err)
+		}
+		if evt.Text != c.expect {
+			t.Errorf("Expect %s, got: %s", c.expect, evt.Text)
+		}
+	}
+
+	if err := sub.Transform(&Event{Text: "Exit"}, Transform{RotY: 30}, nil); err == nil {
+		t.Errorf("Expect an error for an event without position")
+	}
+}
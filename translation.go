@@ -0,0 +1,170 @@
+package ass
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// protectText replaces the override blocks and drawings of dialogue text
+// with numbered placeholders like {1}, and \N with a real line break unless
+// it would leave a blank line, which ends an entry of the export
+func protectText(text string) (string, []string) {
+	var (
+		b            strings.Builder
+		placeholders []string
+		drawing      bool
+		adjacent     bool
+	)
+	for _, p := range splitText(text) {
+		if p.Override {
+			for _, tag := range splitTags(p.Text) {
+				if level, ok := drawingTag(tag); ok {
+					drawing = level > 0
+				}
+			}
+			block := "{" + p.Text + "}"
+			if adjacent {
+				// adjacent blocks share a placeholder
+				placeholders[len(placeholders)-1] += block
+				continue
+			}
+			placeholders = append(placeholders, block)
+			b.WriteString("{" + strconv.Itoa(len(placeholders)) + "}")
+			adjacent = true
+			continue
+		}
+		if drawing && len(placeholders) > 0 {
+			placeholders[len(placeholders)-1] += p.Text
+			continue
+		}
+		adjacent = false
+		b.WriteString(p.Text)
+	}
+	return breakLines(b.String()), placeholders
+}
+
+// breakLines turns \N into real line breaks, but keeps those around blank
+// lines
+func breakLines(text string) string {
+	lines := strings.Split(text, `\N`)
+	broken := lines[0]
+	for _, line := range lines[1:] {
+		last := broken[strings.LastIndexByte(broken, '\n')+1:]
+		if strings.TrimSpace(line) == "" || strings.TrimSpace(last) == "" {
+			broken += `\N` + line
+		} else {
+			broken += "\n" + line
+		}
+	}
+	return broken
+}
+
+var placeholderReg = regexp.MustCompile(`\{(\d+)\}`)
+
+// restoreText is the reverse of protectText, every placeholder must occur
+// exactly once in the translated text
+func restoreText(text string, placeholders []string) (string, error) {
+	seen := make([]bool, len(placeholders))
+	var err error
+	restored := placeholderReg.ReplaceAllStringFunc(text, func(m string) string {
+		n, _ := strconv.Atoi(m[1 : len(m)-1])
+		if n < 1 || n > len(placeholders) {
+			err = fmt.Errorf("Unknown placeholder: %s", m)
+			return m
+		}
+		if seen[n-1] {
+			err = fmt.Errorf("Duplicated placeholder: %s", m)
+			return m
+		}
+		seen[n-1] = true
+		return placeholders[n-1]
+	})
+	if err != nil {
+		return "", err
+	}
+	for i, ok := range seen {
+		if !ok {
+			return "", fmt.Errorf("Missing placeholder: {%d}", i+1)
+		}
+	}
+	return strings.Replace(restored, "\n", `\N`, -1), nil
+}
+
+// ExportTranslation writes the dialogue text in a translation friendly
+// format: entries separated by blank lines, each one starts with the number
+// of the event followed by its text. Override blocks and drawings are
+// replaced by placeholders like {1}, which must be kept in the translation.
+// Line breaks are written as is, but \N is kept around blank lines.
+func (as *Subtitle) ExportTranslation(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	for i, evt := range as.Events {
+		if evt == nil || evt.Comment {
+			continue
+		}
+		text, _ := protectText(evt.Text)
+		if strings.TrimSpace(strings.Replace(placeholderReg.ReplaceAllString(text, ""), `\N`, "", -1)) == "" {
+			continue
+		}
+		fmt.Fprintf(writer, "%d\n%s\n\n", i+1, text)
+	}
+	return writer.Flush()
+}
+
+// ImportTranslation reads a file produced by ExportTranslation and puts the
+// translated text back into the events. Nothing is changed if any entry is
+// invalid, e.g. it has lost or duplicated placeholders.
+func (as *Subtitle) ImportTranslation(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	texts := make(map[int]string)
+	var (
+		id    int
+		lines []string
+	)
+	flush := func() error {
+		if id == 0 {
+			return nil
+		}
+		evt := as.Events[id-1]
+		_, placeholders := protectText(evt.Text)
+		text, err := restoreText(strings.Join(lines, "\n"), placeholders)
+		if err != nil {
+			return fmt.Errorf("Invalid translation of event %d: %v", id, err)
+		}
+		texts[id-1] = text
+		id, lines = 0, nil
+		return nil
+	}
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		switch {
+		case strings.TrimSpace(line) == "":
+			if err := flush(); err != nil {
+				return err
+			}
+		case id == 0:
+			n, err := strconv.Atoi(strings.TrimSpace(line))
+			if err != nil || n < 1 || n > len(as.Events) || as.Events[n-1] == nil {
+				return fmt.Errorf("Invalid event number at line %d: %s", lineNo, line)
+			}
+			id = n
+		default:
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	for i, text := range texts {
+		as.Events[i].Text = text
+	}
+	return nil
+}
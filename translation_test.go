@@ -0,0 +1,82 @@
+package ass
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTranslationRoundTrip(t *testing.T) {
+	sub := &Subtitle{
+		Events: []*Event{
+			{Text: `{\i1}Hello{\i0} world\Nsecond line`},
+			{Text: `{\p1}m 0 0 l 10 10{\p0}`},
+			{Text: `Sign{\b1}{\c&HFF&}text`},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := sub.ExportTranslation(&buf); err != nil {
+		t.Fatalf("Expect export success, got: %v", err)
+	}
+	expect := "1\n{1}Hello{2} world\nsecond line\n\n3\nSign{1}text\n\n"
+	if buf.String() != expect {
+		t.Fatalf("Expect %q, got: %q", expect, buf.String())
+	}
+
+	translated := "1\n{1}Bonjour{2} le monde\ndeuxième ligne\n\n3\nPanneau{1}texte\n"
+	if err := sub.ImportTranslation(strings.NewReader(translated)); err != nil {
+		t.Fatalf("Expect import success, got: %v", err)
+	}
+	texts := []string{`{\i1}Bonjour{\i0} le monde\Ndeuxième ligne`, `{\p1}m 0 0 l 10 10{\p0}`, `Panneau{\b1}{\c&HFF&}texte`}
+	for i, text := range texts {
+		if sub.Events[i].Text != text {
+			t.Errorf("Expect %q, got: %q", text, sub.Events[i].Text)
+		}
+	}
+}
+
+func TestTranslationBlankLines(t *testing.T) {
+	texts := []string{`Hello\N\Nworld`, `\NHello`, `Hello\N`, `{\i1}A\N \N{\i0}B`, `\N\N`}
+	sub := &Subtitle{}
+	for _, text := range texts {
+		sub.Events = append(sub.Events, &Event{Text: text})
+	}
+
+	var buf bytes.Buffer
+	if err := sub.ExportTranslation(&buf); err != nil {
+		t.Fatalf("Expect export success, got: %v", err)
+	}
+	expect := "1\nHello\\N\nworld\n\n2\n\\NHello\n\n3\nHello\\N\n\n4\n{1}A\\N \n{2}B\n\n"
+	if buf.String() != expect {
+		t.Fatalf("Expect %q, got: %q", expect, buf.String())
+	}
+	if err := sub.ImportTranslation(&buf); err != nil {
+		t.Fatalf("Expect import success, got: %v", err)
+	}
+	for i, text := range texts {
+		if sub.Events[i].Text != text {
+			t.Errorf("Expect %q, got: %q", text, sub.Events[i].Text)
+		}
+	}
+}
+
+func TestImportTranslationInvalid(t *testing.T) {
+	cases := []string{
+		"1\nBonjour{2}\n",
+		"1\n{1}{1}Bonjour{2}\n",
+		"1\n{1}Bonjour{2}{3}\n",
+		"5\nBonjour\n",
+		"abc\nBonjour\n",
+	}
+
+	for _, c := range cases {
+		sub := &Subtitle{Events: []*Event{{Text: `{\i1}Hello{\i0}`}}}
+		if err := sub.ImportTranslation(strings.NewReader(c)); err == nil {
+			t.Errorf("Expect invalid translation %q, but passed", c)
+		}
+		if sub.Events[0].Text != `{\i1}Hello{\i0}` {
+			t.Errorf("Expect event unchanged, got: %q", sub.Events[0].Text)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package ass
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// RevealMode is how Typewriter reveals the text
+type RevealMode int
+
+// The reveal modes
+const (
+	// RevealKaraoke makes a single event with a \ko karaoke tag per
+	// character, the characters not revealed yet being transparent
+	RevealKaraoke RevealMode = iota
+	// RevealEvents makes an event per character, the characters not
+	// revealed yet being transparent so that the layout doesn't move
+	RevealEvents
+)
+
+// TypewriterOptions configures Typewriter
+type TypewriterOptions struct {
+	Mode RevealMode
+	// Interval is the time between two characters, 50ms by default. Spaces
+	// and line breaks are revealed with the previous character.
+	Interval time.Duration
+	// Pause is added after the punctuation ending sentences and clauses
+	Pause time.Duration
+}
+
+// revealUnit is a character, line break or override block of the text
+type revealUnit struct {
+	text     string
+	override bool
+	timed    bool // revealed after its own interval
+}
+
+// Typewriter reveals the text of the event character by character from its
+// start. The characters revealed after the end of the event are cut.
+func Typewriter(evt Event, opts TypewriterOptions) ([]*Event, error) {
+	start, end, err := evt.times()
+	if err != nil {
+		return nil, err
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = 50 * time.Millisecond
+	}
+
+	var units []revealUnit
+	for _, p := range splitText(evt.Text) {
+		if p.Override {
+			units = append(units, revealUnit{text: "{" + p.Text + "}", override: true})
+			continue
+		}
+		for t := p.Text; t != ""; {
+			if len(t) >= 2 && t[0] == '\\' && strings.IndexByte("Nnh", t[1]) >= 0 {
+				units = append(units, revealUnit{text: t[:2]})
+				t = t[2:]
+				continue
+			}
+			r, size := utf8.DecodeRuneInString(t)
+			units = append(units, revealUnit{text: t[:size], timed: !unicode.IsSpace(r)})
+			t = t[size:]
+		}
+	}
+
+	// the reveal time of the timed units, and the end of the last one
+	at := make([]Timestamp, len(units))
+	next := start
+	for i, u := range units {
+		if !u.timed {
+			continue
+		}
+		at[i] = next
+		next += Timestamp(interval)
+		if strings.ContainsAny(u.text, ".,;:!?…。、，！？") {
+			next += Timestamp(opts.Pause)
+		}
+	}
+	nextAt := func(i int) Timestamp {
+		for j := i + 1; j < len(units); j++ {
+			if units[j].timed {
+				return at[j]
+			}
+		}
+		return next
+	}
+
+	switch opts.Mode {
+	case RevealKaraoke:
+		var b strings.Builder
+		for i, u := range units {
+			if u.timed {
+				cs := (nextAt(i) - start).centiseconds() - (at[i] - start).centiseconds()
+				b.WriteString(`{\ko` + strconv.FormatInt(cs, 10) + `}`)
+			}
+			b.WriteString(u.text)
+		}
+		e := evt.clone()
+		e.Text = prependTags(b.String(), `\2a&HFF&`)
+		return []*Event{&e}, nil
+
+	case RevealEvents:
+		var events []*Event
+		for k, u := range units {
+			if !u.timed || at[k] >= end {
+				continue
+			}
+			// the last character stays until the end
+			to := nextAt(k)
+			if to > end || to == next {
+				to = end
+			}
+			e := evt.clone()
+			e.Start, e.End = at[k].String(), to.String()
+			if e.Start == e.End {
+				continue
+			}
+			e.Text = revealedText(units[:k+1]) + hiddenText(units[k+1:])
+			events = append(events, &e)
+		}
+		return events, nil
+	}
+	return nil, fmt.Errorf("Invalid reveal mode: %d", opts.Mode)
+}
+
+func revealedText(units []revealUnit) string {
+	var b strings.Builder
+	for _, u := range units {
+		b.WriteString(u.text)
+	}
+	return b.String()
+}
+
+// hiddenText writes the units transparent, whatever their override tags
+func hiddenText(units []revealUnit) string {
+	hasText := false
+	for _, u := range units {
+		hasText = hasText || !u.override
+	}
+	if !hasText {
+		return revealedText(units)
+	}
+	var b strings.Builder
+	b.WriteString(`{\alpha&HFF&}`)
+	for _, u := range units {
+		if u.override {
+			b.WriteString(u.text[:len(u.text)-1] + `\alpha&HFF&}`)
+			continue
+		}
+		b.WriteString(u.text)
+	}
+	return b.String()
+}
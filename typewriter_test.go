@@ -0,0 +1,51 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTypewriter(t *testing.T) {
+	base := Event{Start: "0:00:01.00", End: "0:00:01.50", Style: "Sign", Text: `{\b1}Hi, y\No`}
+	cases := []struct {
+		opts   TypewriterOptions
+		expect []string
+	}{
+		{TypewriterOptions{Interval: 100 * time.Millisecond}, []string{
+			`0:00:01.00 0:00:01.50 {\2a&HFF&\b1}{\ko10}H{\ko10}i{\ko10}, {\ko10}y\N{\ko10}o`,
+		}},
+		{TypewriterOptions{Interval: 100 * time.Millisecond, Pause: 200 * time.Millisecond}, []string{
+			`0:00:01.00 0:00:01.50 {\2a&HFF&\b1}{\ko10}H{\ko10}i{\ko30}, {\ko10}y\N{\ko10}o`,
+		}},
+		{TypewriterOptions{Mode: RevealEvents, Interval: 100 * time.Millisecond, Pause: 200 * time.Millisecond}, []string{
+			`0:00:01.00 0:00:01.10 {\b1}H{\alpha&HFF&}i, y\No`,
+			`0:00:01.10 0:00:01.20 {\b1}Hi{\alpha&HFF&}, y\No`,
+			`0:00:01.20 0:00:01.50 {\b1}Hi,{\alpha&HFF&} y\No`,
+		}},
+	}
+	for _, c := range cases {
+		events, err := Typewriter(base, c.opts)
+		if err != nil {
+			t.Fatalf("Expect no error, got: %v", err)
+		}
+		var got []string
+		for _, e := range events {
+			got = append(got, e.Start+" "+e.End+" "+e.Text)
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+	}
+
+	hidden := hiddenText([]revealUnit{{text: "a"}, {text: `{\1a&H00&}`, override: true}, {text: "b"}})
+	if hidden != `{\alpha&HFF&}a{\1a&H00&\alpha&HFF&}b` {
+		t.Errorf("Expect the override blocks to stay transparent, got: %s", hidden)
+	}
+	if _, err := Typewriter(Event{Start: "x", End: "0:00:01.00"}, TypewriterOptions{}); err == nil {
+		t.Errorf("Expect an error for an invalid start")
+	}
+	if _, err := Typewriter(base, TypewriterOptions{Mode: 9}); err == nil {
+		t.Errorf("Expect an error for an invalid mode")
+	}
+}
@@ -0,0 +1,92 @@
+package ass
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidationError is a problem of a subtitle, located by the index of the
+// style or event and the field, and by its line for a parsed subtitle
+type ValidationError struct {
+	// Section is Styles or Events, empty for the script info
+	Section string
+	// Index is the index of the style or event, -1 for the script info
+	Index int
+	// Field is the name of the offending field, empty if it's the whole item
+	Field string
+	// Line is the line of the parsed file, 0 if unknown
+	Line int
+	Err  error
+}
+
+func (e *ValidationError) Error() string {
+	return located(e.Line, e.Section, e.Index, e.Field, e.Err.Error())
+}
+
+// located prefixes msg with the location of the problem, like
+// Line 12: Events[1].Start, the line being left out if 0
+func located(line int, section string, index int, field, msg string) string {
+	var path string
+	if section != "" {
+		path = fmt.Sprintf("%s[%d]", section, index)
+	}
+	if field != "" {
+		if path != "" {
+			path += "."
+		}
+		path += field
+	}
+	if path != "" {
+		msg = path + ": " + msg
+	}
+	if line > 0 {
+		msg = fmt.Sprintf("Line %d: %s", line, msg)
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors are all the problems found by Validate
+type ValidationErrors []*ValidationError
+
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Is reports whether any of the errors matches target, for errors.Is
+func (errs ValidationErrors) Is(target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors matching target, for errors.As
+func (errs ValidationErrors) As(target interface{}) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the errors, for Go 1.20 and later
+func (errs ValidationErrors) Unwrap() []error {
+	list := make([]error, len(errs))
+	for i, err := range errs {
+		list[i] = err
+	}
+	return list
+}
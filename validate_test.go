@@ -0,0 +1,78 @@
+package ass
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	sub := &Subtitle{
+		Timer:       -1,
+		WrapStyle:   4,
+		YCbCrMatrix: "BT.2020",
+		Collisions:  "Sideways",
+		Styles: []*Style{
+			{Name: "Default", PrimaryColor: "red", Bold: 1},
+			nil,
+		},
+		Events: []*Event{
+			{Start: "0:00:00.00", End: "0:00:01.00"},
+			{Start: "bad", End: "worse", Name: "a,b"},
+		},
+	}
+
+	err := sub.Validate()
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("Expect ValidationErrors, got: %v", err)
+	}
+	expects := []string{
+		"Timer: Invalid timer: -1.000000",
+		"WrapStyle: Invalid wrap style: 4",
+		"YCbCrMatrix: Invalid YCbCr matrix: BT.2020",
+		"Collisions: Invalid collisions: Sideways",
+		"Styles[0].PrimaryColor: Invalid primary color: red",
+		"Styles[0].Bold: Invalid style bold: 1",
+		"Styles[1]: Style cannot be nil",
+		"Events[1].Start: Invalid start time: bad",
+		"Events[1].End: Invalid end time: worse",
+		`Events[1].Name: Invalid name, comma and line break are not allowed: "a,b"`,
+	}
+	if len(errs) != len(expects) {
+		t.Fatalf("Expect %d errors, got: %v", len(expects), err)
+	}
+	for i, expect := range expects {
+		if errs[i].Error() != expect {
+			t.Errorf("Expect %q, got: %q", expect, errs[i].Error())
+		}
+	}
+
+	if err := (&Subtitle{}).Validate(); err != nil {
+		t.Errorf("Expect valid empty subtitle, got: %v", err)
+	}
+}
+
+func TestValidationErrorsIsAs(t *testing.T) {
+	errs := ValidationErrors{
+		{Section: "Events", Index: 0, Field: "Start", Err: errors.New("bad")},
+		{Section: "Events", Index: 1, Field: "Text", Err: io.ErrUnexpectedEOF},
+	}
+	err := fmt.Errorf("wrapped: %w", errs)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expect errors.Is to find the second error")
+	}
+	if errors.Is(err, io.EOF) {
+		t.Errorf("Expect errors.Is not to find a missing error")
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr != errs[0] {
+		t.Errorf("Expect errors.As to find the first error, got: %v", verr)
+	}
+	var perr *os.PathError
+	if errors.As(err, &perr) {
+		t.Errorf("Expect errors.As not to match another type, got: %v", perr)
+	}
+}
@@ -0,0 +1,69 @@
+package ass
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VerticalRotation turns the text laid out with a vertical font, see
+// VerticalFont, so that it reads from top to bottom
+const VerticalRotation = `\frz-90`
+
+// VerticalFont returns the vertical variant of a font, the font name
+// prefixed with @, whose CJK glyphs are turned for vertical writing
+func VerticalFont(name string) string {
+	if IsVerticalFont(name) {
+		return name
+	}
+	return "@" + name
+}
+
+// IsVerticalFont reports whether the font name is a vertical variant
+func IsVerticalFont(name string) bool {
+	return strings.HasPrefix(name, "@")
+}
+
+// VerticalOptions configures Subtitle.VerticalEvents
+type VerticalOptions struct {
+	// Gap is the space between the columns, in script pixels
+	Gap float64
+}
+
+// VerticalEvents lays out the text of the event vertically, for Japanese
+// signs and credits: each line becomes a column, read from top to bottom,
+// the columns going from right to left. The first column starts at the
+// \pos of the event, its top right corner, or else at the top right corner
+// within the margins. An event is returned per column, in the vertical
+// variant of the font of the style.
+func (as *Subtitle) VerticalEvents(evt *Event, opts VerticalOptions) []*Event {
+	style := as.styleByName(evt.Style)
+	size, font := float64(defaultFontSize), defFontName
+	if style != nil {
+		if style.FontSize > 0 {
+			size = float64(style.FontSize)
+		}
+		if style.FontName != "" {
+			font = style.FontName
+		}
+	}
+	_, x, y, positioned := textLayout(evt.Text, style.align())
+	if !positioned {
+		w, _ := as.PlayRes()
+		_, r, v := evt.margins(style)
+		x, y = float64(w)-float64(r), float64(v)
+	}
+
+	text := removeTags(evt.Text, func(tag string) bool {
+		return isFitTag(tag) || isTransformTag(tag) || strings.HasPrefix(tag, `\fn`)
+	})
+	var events []*Event
+	for i, column := range strings.Split(text, `\N`) {
+		e := evt.clone()
+		// turned clockwise around its top left corner, the column is on the
+		// left of \pos
+		e.Text = prependTags(column, fmt.Sprintf(`\an7\pos(%s,%s)\fn%s%s`,
+			formatNumber(x-float64(i)*(size+opts.Gap)), formatNumber(y), VerticalFont(font), VerticalRotation))
+		events = append(events, &e)
+	}
+	return events
+}
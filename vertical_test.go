@@ -0,0 +1,40 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVerticalFont(t *testing.T) {
+	if f := VerticalFont("MS Gothic"); f != "@MS Gothic" || !IsVerticalFont(f) {
+		t.Errorf("Expect @MS Gothic, got: %s", f)
+	}
+	if f := VerticalFont("@MS Gothic"); f != "@MS Gothic" {
+		t.Errorf("Expect the vertical font unchanged, got: %s", f)
+	}
+}
+
+func TestVerticalEvents(t *testing.T) {
+	sub := &Subtitle{PlayerWidth: 640, PlayerHeight: 480, Styles: []*Style{{Name: "Sign", FontName: "Meiryo", FontSize: 40}}}
+	cases := []struct {
+		evt    Event
+		expect []string
+	}{
+		{Event{Style: "Sign", Text: `{\pos(600,50)\frz10\b1}縦書き\N二列目`}, []string{
+			`{\an7\pos(600,50)\fn@Meiryo\frz-90\b1}縦書き`,
+			`{\an7\pos(550,50)\fn@Meiryo\frz-90}二列目`,
+		}},
+		{Event{Text: `{\fnArial}縦`, MarginR: 40}, []string{
+			`{\an7\pos(600,2)\fn@Arial\frz-90}縦`,
+		}},
+	}
+	for _, c := range cases {
+		var got []string
+		for _, e := range sub.VerticalEvents(&c.evt, VerticalOptions{Gap: 10}) {
+			got = append(got, e.Text)
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("Expect %q, got: %q", c.expect, got)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package ass
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteVTT converts the subtitle to WebVTT: the events become cues in the
+// order of their start time, override tags are dropped except italic, bold
+// and underline which become <i>, <b> and <u>. The language, if set, is
+// written in a Language header.
+func (as Subtitle) WriteVTT(w io.Writer) (int64, error) {
+	if err := as.validate(); err != nil {
+		return 0, err
+	}
+	counter := &countingWriter{w: w}
+	writer := bufio.NewWriter(counter)
+	writer.WriteString("WEBVTT\n")
+	if as.Language != "" {
+		writer.WriteString("Language: " + as.Language + "\n")
+	}
+	for _, evt := range sortedEvents(as.Events) {
+		if evt.Comment {
+			continue
+		}
+		start, end, err := evt.times()
+		if err != nil {
+			return counter.n, err
+		}
+		writer.Write(appendVTTCue(nil, start.Duration(), end.Duration(), evt.Text))
+	}
+	err := writer.Flush()
+	return counter.n, err
+}
+
+// appendVTTCue appends a blank line and a cue
+func appendVTTCue(b []byte, start, end time.Duration, text string) []byte {
+	b = append(b, '\n')
+	b = append(b, vttTimestamp(start)...)
+	b = append(b, " --> "...)
+	b = append(b, vttTimestamp(end)...)
+	b = append(b, '\n')
+	b = append(b, markupText(text, true)...)
+	return append(b, '\n')
+}
+
+// vttTimestamp formats hh:mm:ss.ttt, negative durations are clamped to 0
+func vttTimestamp(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := int64(d / time.Millisecond)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// markupText converts dialogue text to the text of a WebVTT or SubRip cue,
+// escaping &, < and > for WebVTT
+func markupText(text string, escape bool) string {
+	var b strings.Builder
+	open := map[byte]bool{}
+	closeTag := func(c byte) {
+		if open[c] {
+			b.WriteString("</" + string(c) + ">")
+			open[c] = false
+		}
+	}
+	for _, p := range splitText(text) {
+		if p.Override {
+			for _, tag := range splitTags(p.Text) {
+				for _, c := range []byte("ibu") {
+					switch tag {
+					case `\` + string(c) + "1":
+						if !open[c] {
+							b.WriteString("<" + string(c) + ">")
+							open[c] = true
+						}
+					case `\` + string(c) + "0", `\` + string(c):
+						closeTag(c)
+					}
+				}
+			}
+			continue
+		}
+		for i := 0; i < len(p.Text); i++ {
+			switch c := p.Text[i]; {
+			case c == '\\' && i+1 < len(p.Text) && (p.Text[i+1] == 'N' || p.Text[i+1] == 'n'):
+				b.WriteByte('\n')
+				i++
+			case c == '\\' && i+1 < len(p.Text) && p.Text[i+1] == 'h':
+				if escape {
+					b.WriteString("&nbsp;")
+				} else {
+					b.WriteString("\u00a0")
+				}
+				i++
+			case c == '\n':
+				b.WriteByte('\n')
+			case c == '\r':
+			case c == '&' && escape:
+				b.WriteString("&amp;")
+			case c == '<' && escape:
+				b.WriteString("&lt;")
+			case c == '>' && escape:
+				b.WriteString("&gt;")
+			default:
+				b.WriteByte(c)
+			}
+		}
+	}
+	for _, c := range []byte("ubi") {
+		closeTag(c)
+	}
+	// a blank line would end the cue
+	lines := strings.Split(b.String(), "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
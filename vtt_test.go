@@ -0,0 +1,33 @@
+package ass
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteVTT(t *testing.T) {
+	sub := Subtitle{Events: []*Event{
+		{Start: "0:00:02.00", End: "1:00:03.50", Text: `{\i1}second{\i0} <line>\N\Nnext`},
+		{Start: "0:00:01.00", End: "0:00:02.00", Text: `{\pos(10,10)\b1}first & bold`},
+	}}
+	var buf bytes.Buffer
+	n, err := sub.WriteVTT(&buf)
+	if err != nil {
+		t.Fatalf("Expect no error, got: %v", err)
+	}
+	expect := "WEBVTT\n" +
+		"\n00:00:01.000 --> 00:00:02.000\n<b>first &amp; bold</b>\n" +
+		"\n00:00:02.000 --> 01:00:03.500\n<i>second</i> &lt;line&gt;\nnext\n"
+	if buf.String() != expect {
+		t.Errorf("Expect %q, got: %q", expect, buf.String())
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("Expect %d bytes written, got: %d", buf.Len(), n)
+	}
+
+	sub.Language = "en-GB"
+	buf.Reset()
+	if _, err := sub.WriteVTT(&buf); err != nil || !bytes.HasPrefix(buf.Bytes(), []byte("WEBVTT\nLanguage: en-GB\n\n")) {
+		t.Errorf("Expect a language header, got: %q, %v", buf.String(), err)
+	}
+}
@@ -0,0 +1,131 @@
+package ass
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Word is a word with its own timing, e.g. from speech recognition
+type Word struct {
+	Text  string    `json:"text"`
+	Start Timestamp `json:"start"`
+	End   Timestamp `json:"end"`
+	// Confidence is how sure the recognizer is of the word, from 0 to 1,
+	// 0 if unknown
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// WordMode is how ExpandWords presents the words
+type WordMode int
+
+// The word modes
+const (
+	// WordKaraoke makes a single event with a karaoke tag per word
+	WordKaraoke WordMode = iota
+	// WordPop makes an event per word, showing only that word
+	WordPop
+	// WordHighlight makes an event per word, showing the whole line with
+	// the current word highlighted
+	WordHighlight
+)
+
+// WordOptions configures ExpandWords
+type WordOptions struct {
+	Mode WordMode
+	// KaraokeTag is the karaoke tag used in WordKaraoke mode: k, kf or ko,
+	// k by default
+	KaraokeTag string
+	// HighlightTags are the override tags of the current word in
+	// WordHighlight mode, yellow text by default
+	HighlightTags string
+}
+
+// minWordDuration is the written precision, the duration given to the words
+// shorter than it so that no event ends when it starts
+const minWordDuration = Timestamp(10 * time.Millisecond)
+
+// centiseconds rounds the timestamp down to the written precision
+func (t Timestamp) centiseconds() int64 {
+	return int64(time.Duration(t) / (10 * time.Millisecond))
+}
+
+// ExpandWords generates events from timed words, the other fields of the
+// events are copied from base. Words must be in time order, those shorter
+// than a centisecond are given a centisecond.
+func ExpandWords(base Event, words []Word, opts WordOptions) ([]*Event, error) {
+	if len(words) == 0 {
+		return nil, nil
+	}
+	for i, w := range words {
+		if w.End < w.Start {
+			return nil, fmt.Errorf("Word %d ends before it starts", i)
+		}
+		if i > 0 && w.Start < words[i-1].Start {
+			return nil, fmt.Errorf("Word %d is out of order", i)
+		}
+	}
+	words = append([]Word(nil), words...)
+	for i, w := range words {
+		if cs := w.Start.centiseconds(); w.End.centiseconds() <= cs {
+			words[i].End = minWordDuration * Timestamp(cs+1)
+		}
+	}
+
+	switch opts.Mode {
+	case WordKaraoke:
+		tag := opts.KaraokeTag
+		if tag == "" {
+			tag = "k"
+		}
+		var b strings.Builder
+		prevEnd := words[0].Start.centiseconds()
+		for i, w := range words {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			if gap := w.Start.centiseconds() - prevEnd; gap > 0 {
+				b.WriteString(`{\` + tag + strconv.FormatInt(gap, 10) + `}`)
+			}
+			b.WriteString(`{\` + tag + strconv.FormatInt(w.End.centiseconds()-w.Start.centiseconds(), 10) + `}`)
+			b.WriteString(w.Text)
+			prevEnd = w.End.centiseconds()
+		}
+		evt := base.clone()
+		evt.Start = words[0].Start.String()
+		evt.End = words[len(words)-1].End.String()
+		evt.Text = b.String()
+		return []*Event{&evt}, nil
+
+	case WordPop, WordHighlight:
+		highlight := opts.HighlightTags
+		if highlight == "" {
+			highlight = `\c&H00FFFF&`
+		}
+		events := make([]*Event, 0, len(words))
+		for i, w := range words {
+			evt := base.clone()
+			evt.Start = w.Start.String()
+			evt.End = w.End.String()
+			if opts.Mode == WordPop {
+				evt.Text = w.Text
+				events = append(events, &evt)
+				continue
+			}
+			// keep the line on screen until the next word starts
+			if i+1 < len(words) && words[i+1].Start > w.End {
+				evt.End = words[i+1].Start.String()
+			}
+			texts := make([]string, len(words))
+			for j, other := range words {
+				texts[j] = other.Text
+			}
+			texts[i] = "{" + highlight + "}" + w.Text + `{\r}`
+			evt.Text = strings.Join(texts, " ")
+			events = append(events, &evt)
+		}
+		return events, nil
+	}
+	return nil, fmt.Errorf("Invalid word mode: %d", opts.Mode)
+}
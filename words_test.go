@@ -0,0 +1,109 @@
+package ass
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExpandWords(t *testing.T) {
+	ms := func(n int) Timestamp { return Timestamp(time.Duration(n) * time.Millisecond) }
+	words := []Word{
+		{Text: "Hello", Start: ms(1000), End: ms(1500)},
+		{Text: "big", Start: ms(1700), End: ms(2000)},
+		{Text: "world", Start: ms(2000), End: ms(2600)},
+	}
+
+	cases := []struct {
+		opts   WordOptions
+		expect []Event
+	}{
+		{
+			WordOptions{},
+			[]Event{{Start: "0:00:01.00", End: "0:00:02.60", Style: "Pop", Text: `{\k50}Hello {\k20}{\k30}big {\k60}world`}},
+		},
+		{
+			WordOptions{Mode: WordPop},
+			[]Event{
+				{Start: "0:00:01.00", End: "0:00:01.50", Style: "Pop", Text: "Hello"},
+				{Start: "0:00:01.70", End: "0:00:02.00", Style: "Pop", Text: "big"},
+				{Start: "0:00:02.00", End: "0:00:02.60", Style: "Pop", Text: "world"},
+			},
+		},
+		{
+			WordOptions{Mode: WordHighlight, HighlightTags: `\b1`},
+			[]Event{
+				{Start: "0:00:01.00", End: "0:00:01.70", Style: "Pop", Text: `{\b1}Hello{\r} big world`},
+				{Start: "0:00:01.70", End: "0:00:02.00", Style: "Pop", Text: `Hello {\b1}big{\r} world`},
+				{Start: "0:00:02.00", End: "0:00:02.60", Style: "Pop", Text: `Hello big {\b1}world{\r}`},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		events, err := ExpandWords(Event{Style: "Pop"}, words, c.opts)
+		if err != nil {
+			t.Errorf("Expect expand success, got: %v", err)
+			continue
+		}
+		if len(events) != len(c.expect) {
+			t.Errorf("Expect %d events, got: %d", len(c.expect), len(events))
+			continue
+		}
+		for i, expect := range c.expect {
+			if !reflect.DeepEqual(*events[i], expect) {
+				t.Errorf("Expect %+v, got: %+v", expect, *events[i])
+			}
+		}
+	}
+
+	// zero-length words still make valid events
+	short := []Word{
+		{Text: "a", Start: ms(1000), End: ms(1000)},
+		{Text: "b", Start: ms(1000), End: ms(1005)},
+		{Text: "c", Start: ms(1010), End: ms(1500)},
+	}
+	shortCases := []struct {
+		opts   WordOptions
+		expect []Event
+	}{
+		{
+			WordOptions{},
+			[]Event{{Start: "0:00:01.00", End: "0:00:01.50", Text: `{\k1}a {\k1}b {\k49}c`}},
+		},
+		{
+			WordOptions{Mode: WordPop},
+			[]Event{
+				{Start: "0:00:01.00", End: "0:00:01.01", Text: "a"},
+				{Start: "0:00:01.00", End: "0:00:01.01", Text: "b"},
+				{Start: "0:00:01.01", End: "0:00:01.50", Text: "c"},
+			},
+		},
+	}
+	for _, c := range shortCases {
+		events, err := ExpandWords(Event{}, short, c.opts)
+		if err != nil {
+			t.Errorf("Expect expand success, got: %v", err)
+			continue
+		}
+		if len(events) != len(c.expect) {
+			t.Errorf("Expect %d events, got: %d", len(c.expect), len(events))
+			continue
+		}
+		for i, expect := range c.expect {
+			if !reflect.DeepEqual(*events[i], expect) {
+				t.Errorf("Expect %+v, got: %+v", expect, *events[i])
+			}
+			if start, end, err := events[i].times(); err != nil || end <= start {
+				t.Errorf("Expect the event to end after it starts, got: %+v", *events[i])
+			}
+		}
+	}
+	if short[0].End != ms(1000) {
+		t.Errorf("Expect the words unchanged, got: %v", short[0].End)
+	}
+
+	if _, err := ExpandWords(Event{}, []Word{{Start: ms(2000), End: ms(1000)}}, WordOptions{}); err == nil {
+		t.Errorf("Expect invalid word error, but passed")
+	}
+}
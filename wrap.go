@@ -0,0 +1,242 @@
+package ass
+
+import "strings"
+
+// WrapStyle is how the lines are broken when they are too wide
+type WrapStyle int
+
+// The wrap styles defined by the ass spec
+const (
+	// WrapSmart breaks into lines of even width, the upper line wider
+	WrapSmart WrapStyle = 0
+	// WrapEndOfLine fills each line as much as possible
+	WrapEndOfLine WrapStyle = 1
+	// WrapNone never breaks automatically, only \N breaks the line
+	WrapNone WrapStyle = 2
+	// WrapSmartLower is the same as WrapSmart, but the lower line is wider
+	WrapSmartLower WrapStyle = 3
+)
+
+// WrapOptions configures the line wrapping
+type WrapOptions struct {
+	// Width is the maximal width of a line, in the unit of Measure
+	Width float64
+	// MaxLines limits the number of lines, 0 means no limit. Lines get wider
+	// than Width if the text can't fit otherwise.
+	MaxLines int
+	// Style is the wrap style to respect
+	Style WrapStyle
+	// Measure returns the width of some plain text,
+	// the default counts the characters, or uses Measurer if set
+	Measure func(text string) float64
+	// Measurer measures the text in the style of each event with WrapLines,
+	// in the default style with Wrap. It is ignored if Measure is set.
+	Measurer TextMeasurer
+}
+
+// countRunes counts the characters taking some width: the bidi controls
+// and the combining marks are left out
+func countRunes(text string) float64 {
+	n := 0
+	for _, r := range text {
+		if !isZeroWidth(r) {
+			n++
+		}
+	}
+	return float64(n)
+}
+
+// Wrap breaks dialogue text into lines by inserting \N. Existing \N breaks
+// are kept, override blocks don't take any width, and CJK text is broken
+// between characters but never before closing punctuation. A \q tag in the
+// text overrides opts.Style. The bidi embeddings and isolates open at a
+// line break are closed and opened again on the next line.
+func Wrap(text string, opts WrapOptions) string {
+	if style, ok := wrapStyleTag(text); ok {
+		opts.Style = style
+	}
+	if opts.Style == WrapNone || opts.Width <= 0 {
+		return text
+	}
+	if opts.Measure == nil && opts.Measurer != nil {
+		opts.Measure = widthFunc(opts.Measurer, nil)
+	}
+	if opts.Measure == nil {
+		opts.Measure = countRunes
+	}
+
+	paragraphs := strings.Split(text, `\N`)
+	for i, p := range paragraphs {
+		paragraphs[i] = wrapParagraph(p, opts)
+	}
+	return balanceBidi(strings.Join(paragraphs, `\N`))
+}
+
+// WrapLines wraps the text of all the events with the WrapStyle of the
+// subtitle, opts.Style is ignored, see Wrap
+func (as *Subtitle) WrapLines(opts WrapOptions) {
+	opts.Style = as.WrapStyle
+	for _, evt := range as.Events {
+		if evt == nil {
+			continue
+		}
+		evtOpts := opts
+		if opts.Measure == nil && opts.Measurer != nil {
+			evtOpts.Measure = widthFunc(opts.Measurer, as.styleByName(evt.Style))
+		}
+		evt.Text = Wrap(evt.Text, evtOpts)
+	}
+}
+
+// wrapStyleTag returns the wrap style set by the last \q tag of the text
+func wrapStyleTag(text string) (WrapStyle, bool) {
+	style, found := WrapSmart, false
+	for _, p := range splitText(text) {
+		if !p.Override {
+			continue
+		}
+		for _, tag := range splitTags(p.Text) {
+			if len(tag) == 3 && tag[:2] == `\q` && tag[2] >= '0' && tag[2] <= '3' {
+				style, found = WrapStyle(tag[2]-'0'), true
+			}
+		}
+	}
+	return style, found
+}
+
+// wrapSegment is an unbreakable piece of text
+type wrapSegment struct {
+	text  string // with override blocks
+	width float64
+	space bool // separated from the previous segment by a space
+}
+
+func wrapParagraph(text string, opts WrapOptions) string {
+	segs := wrapSegments(text, opts.Measure)
+	if len(segs) < 2 {
+		return text
+	}
+	spaceWidth := opts.Measure(" ")
+	lineWidth := func(i, j int) float64 {
+		w := 0.0
+		for k := i; k < j; k++ {
+			w += segs[k].width
+			if k > i && segs[k].space {
+				w += spaceWidth
+			}
+		}
+		return w
+	}
+
+	breaks := greedyBreaks(len(segs), opts.Width, lineWidth, false)
+	if len(breaks) == 0 {
+		return text
+	}
+	if opts.Style != WrapEndOfLine || (opts.MaxLines > 0 && len(breaks)+1 > opts.MaxLines) {
+		lines := len(breaks) + 1
+		if opts.MaxLines > 0 && lines > opts.MaxLines {
+			lines = opts.MaxLines
+		}
+		// the narrowest width that still fits in the same number of lines
+		lo, hi := 0.0, lineWidth(0, len(segs))
+		for i := 0; i < 50; i++ {
+			mid := (lo + hi) / 2
+			if len(greedyBreaks(len(segs), mid, lineWidth, false))+1 <= lines {
+				hi = mid
+			} else {
+				lo = mid
+			}
+		}
+		breaks = greedyBreaks(len(segs), hi, lineWidth, opts.Style == WrapSmartLower)
+	}
+
+	var b strings.Builder
+	next := 0
+	for i, seg := range segs {
+		if next < len(breaks) && breaks[next] == i {
+			b.WriteString(`\N`)
+			next++
+		} else if seg.space && i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(seg.text)
+	}
+	return b.String()
+}
+
+// greedyBreaks returns the indexes of segments starting a new line when
+// each line is filled as much as possible, from the last line if reverse
+func greedyBreaks(n int, width float64, lineWidth func(i, j int) float64, reverse bool) []int {
+	var breaks []int
+	if !reverse {
+		start := 0
+		for i := 1; i < n; i++ {
+			if lineWidth(start, i+1) > width {
+				breaks = append(breaks, i)
+				start = i
+			}
+		}
+		return breaks
+	}
+	end := n
+	for i := n - 2; i >= 0; i-- {
+		if lineWidth(i, end) > width {
+			breaks = append([]int{i + 1}, breaks...)
+			end = i + 1
+		}
+	}
+	return breaks
+}
+
+// wrapSegments splits text at the places where a line can break
+func wrapSegments(text string, measure func(string) float64) []wrapSegment {
+	var (
+		segs         []wrapSegment
+		cur, plain   strings.Builder
+		space        bool
+		prev         rune
+		hasPrevPlain bool
+	)
+	flush := func() {
+		if cur.Len() == 0 {
+			return
+		}
+		segs = append(segs, wrapSegment{text: cur.String(), width: measure(plain.String()), space: space})
+		cur.Reset()
+		plain.Reset()
+		space = false
+	}
+
+	for _, p := range splitText(text) {
+		if p.Override {
+			cur.WriteString("{" + p.Text + "}")
+			continue
+		}
+		for _, r := range p.Text {
+			if r == ' ' {
+				flush()
+				space = len(segs) > 0
+				hasPrevPlain = false
+				continue
+			}
+			if hasPrevPlain && (isCJK(r) || isCJK(prev)) && !noBreakBefore(r) && !noBreakAfter(prev) {
+				flush()
+			}
+			cur.WriteRune(r)
+			plain.WriteRune(r)
+			prev, hasPrevPlain = r, true
+		}
+	}
+	flush()
+	return segs
+}
+
+// noBreakBefore reports whether a line must not start with r
+func noBreakBefore(r rune) bool {
+	return strings.ContainsRune(")]}»、。，．,.!?！？：；:;）」』】〕〉》〟’”ー…‥ゝゞ々ぁぃぅぇぉっゃゅょゎァィゥェォッャュョヮヵヶ・", r)
+}
+
+// noBreakAfter reports whether a line must not end with r
+func noBreakAfter(r rune) bool {
+	return strings.ContainsRune("([{«（「『【〔〈《〝‘“", r)
+}
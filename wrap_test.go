@@ -0,0 +1,48 @@
+package ass
+
+import "testing"
+
+func TestWrap(t *testing.T) {
+	cases := []struct {
+		text   string
+		opts   WrapOptions
+		expect string
+	}{
+		{"short line", WrapOptions{Width: 20}, "short line"},
+		{"aaa bbb ccc ddd", WrapOptions{Width: 12, Style: WrapEndOfLine}, `aaa bbb ccc\Nddd`},
+		{"aaa bbb ccc ddd", WrapOptions{Width: 12, Style: WrapSmart}, `aaa bbb\Nccc ddd`},
+		{"aaa bbb ccc", WrapOptions{Width: 8, Style: WrapSmart}, `aaa bbb\Nccc`},
+		{"aaa bbb ccc", WrapOptions{Width: 8, Style: WrapSmartLower}, `aaa\Nbbb ccc`},
+		{"aaa bbb ccc", WrapOptions{Width: 8, Style: WrapNone}, "aaa bbb ccc"},
+		{"aaa bbb ccc ddd", WrapOptions{Width: 4, MaxLines: 2}, `aaa bbb\Nccc ddd`},
+		{`{\i1}aaa bbb{\i0} ccc`, WrapOptions{Width: 8}, `{\i1}aaa bbb{\i0}\Nccc`},
+		{`aaa bbb\Nccc ddd eee`, WrapOptions{Width: 8}, `aaa bbb\Nccc ddd\Neee`},
+		{"今日はいい天気ですね。", WrapOptions{Width: 6}, `今日はいい天\N気ですね。`},
+		{"あいうえ。", WrapOptions{Width: 4, Style: WrapEndOfLine}, `あいう\Nえ。`},
+		{`{\q2}aaa bbb ccc`, WrapOptions{Width: 8}, `{\q2}aaa bbb ccc`},
+		{`{\q2\q1}aaa bbb ccc ddd`, WrapOptions{Width: 12, Style: WrapNone}, `{\q2\q1}aaa bbb ccc\Nddd`},
+	}
+
+	for _, c := range cases {
+		if got := Wrap(c.text, c.opts); got != c.expect {
+			t.Errorf("Wrap %q: expect %q, got: %q", c.text, c.expect, got)
+		}
+	}
+}
+
+func TestWrapLines(t *testing.T) {
+	sub := &Subtitle{
+		WrapStyle: WrapEndOfLine,
+		Events: []*Event{
+			{Text: "aaa bbb ccc ddd"},
+			{Text: `{\q0}aaa bbb ccc ddd`},
+		},
+	}
+	sub.WrapLines(WrapOptions{Width: 12, Style: WrapNone})
+	expects := []string{`aaa bbb ccc\Nddd`, `{\q0}aaa bbb\Nccc ddd`}
+	for i, expect := range expects {
+		if sub.Events[i].Text != expect {
+			t.Errorf("Expect %q, got: %q", expect, sub.Events[i].Text)
+		}
+	}
+}
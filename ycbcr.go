@@ -0,0 +1,30 @@
+package ass
+
+import "fmt"
+
+// YCbCrMatrix is the color matrix of the video the subtitle was authored
+// against, renderers use it to convert the colors to match the video
+type YCbCrMatrix string
+
+// The matrices of the YCbCr Matrix field. The empty value omits the field,
+// letting the renderer guess.
+const (
+	MatrixNone   YCbCrMatrix = "None"
+	MatrixTV601  YCbCrMatrix = "TV.601"
+	MatrixPC601  YCbCrMatrix = "PC.601"
+	MatrixTV709  YCbCrMatrix = "TV.709"
+	MatrixPC709  YCbCrMatrix = "PC.709"
+	MatrixTVFCC  YCbCrMatrix = "TV.FCC"
+	MatrixPCFCC  YCbCrMatrix = "PC.FCC"
+	MatrixTV240M YCbCrMatrix = "TV.240M"
+	MatrixPC240M YCbCrMatrix = "PC.240M"
+)
+
+func (m YCbCrMatrix) validate() error {
+	switch m {
+	case "", MatrixNone, MatrixTV601, MatrixPC601, MatrixTV709, MatrixPC709,
+		MatrixTVFCC, MatrixPCFCC, MatrixTV240M, MatrixPC240M:
+		return nil
+	}
+	return fmt.Errorf("Invalid YCbCr matrix: %s", string(m))
+}